@@ -0,0 +1,302 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math"
+)
+
+// Cholesky is the factorization of a symmetric positive definite matrix A
+// A = L * L^T
+// where L is lower triangular with a positive diagonal.
+// It is about twice as fast as LU and needs no pivoting. L can also be used to
+// draw samples with covariance A: if z has independent standard normal entries,
+// L * z has covariance A.
+type Cholesky struct {
+	// L is lower triangular with a positive diagonal
+	L Matrix
+}
+
+// NewCholesky computes the Cholesky factorization of a symmetric positive definite matrix.
+// It returns ErrNotSymmetric if the matrix is not symmetric and ErrNotPositiveDefinite
+// if a pivot is not positive, use NearestPositiveDefinite to repair a covariance
+// matrix that went slightly indefinite from rounding.
+func NewCholesky(m Matrix) (Cholesky, error) {
+	if err := checkSquare("NewCholesky", m.Data); err != nil {
+		return Cholesky{}, err
+	}
+	if !IsMatrixSymmetric(m.Data) {
+		return Cholesky{}, newShapeError("NewCholesky", ErrNotSymmetric, m.Data)
+	}
+
+	n := len(m.Data)
+	L := make([][]float64, n)
+	for i := range L {
+		L[i] = make([]float64, n)
+	}
+
+	for j := 0; j < n; j++ {
+		d := m.Data[j][j]
+		for k := 0; k < j; k++ {
+			d -= L[j][k] * L[j][k]
+		}
+		if !(d > 0) {
+			return Cholesky{}, newShapeError("NewCholesky", ErrNotPositiveDefinite, m.Data)
+		}
+		L[j][j] = math.Sqrt(d)
+
+		for i := j + 1; i < n; i++ {
+			s := m.Data[i][j]
+			for k := 0; k < j; k++ {
+				s -= L[i][k] * L[j][k]
+			}
+			L[i][j] = s / L[j][j]
+		}
+	}
+
+	return Cholesky{L: Matrix{Data: L}}, nil
+}
+
+// IsPositiveDefinite returns true if the matrix is symmetric and x^T * A * x > 0
+// for every x that is not 0, which is when its Cholesky factorization exists
+func IsPositiveDefinite(matrix [][]float64) bool {
+	_, err := NewCholesky(Matrix{Data: matrix})
+	return err == nil
+}
+
+// Det returns the determinant of A, the square of the product of the diagonal of L.
+// A 0x0 matrix has determinant 0, like for GetDeterminant.
+func (f Cholesky) Det() float64 {
+	if len(f.L.Data) == 0 {
+		return 0
+	}
+
+	det := 1.0
+	for i := range f.L.Data {
+		det *= f.L.Data[i][i]
+	}
+
+	return det * det
+}
+
+// LogDet returns the natural log of the determinant of A.
+// A is positive definite so the determinant is always positive, except for a
+// 0x0 matrix which has determinant 0 and returns -Inf.
+func (f Cholesky) LogDet() float64 {
+	if len(f.L.Data) == 0 {
+		return math.Inf(-1)
+	}
+
+	res := 0.0
+	for i := range f.L.Data {
+		res += 2 * math.Log(f.L.Data[i][i])
+	}
+
+	return res
+}
+
+// Solve solves A * x = b by forward substitution with L and back substitution with L^T
+func (f Cholesky) Solve(b []float64) ([]float64, error) {
+	n := len(f.L.Data)
+	if len(b) != n {
+		return nil, &ShapeError{
+			Op:     "Cholesky.Solve",
+			Shapes: []Shape{{Rows: n, Cols: n}, {Rows: len(b), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	L := f.L.Data
+	x := make([]float64, n)
+	copy(x, b)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= L[i][j] * x[j]
+		}
+		x[i] /= L[i][i]
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= L[j][i] * x[j]
+		}
+		x[i] /= L[i][i]
+	}
+
+	return x, nil
+}
+
+// LDL is the factorization of a symmetric matrix A
+// A = L * D * L^T
+// where L is unit lower triangular and D is diagonal.
+// Unlike Cholesky it takes no square roots and also works for symmetric
+// matrices that are indefinite, as long as no leading minor is singular.
+type LDL struct {
+	// L is unit lower triangular
+	L Matrix
+	// D is the diagonal of D
+	D []float64
+}
+
+// NewLDL computes the LDL^T factorization of a symmetric matrix without pivoting.
+// It returns ErrNotSymmetric if the matrix is not symmetric and ErrSingular if a
+// pivot is 0 relative to the size of the entries of A.
+func NewLDL(m Matrix) (LDL, error) {
+	if err := checkSquare("NewLDL", m.Data); err != nil {
+		return LDL{}, err
+	}
+	if !IsMatrixSymmetric(m.Data) {
+		return LDL{}, newShapeError("NewLDL", ErrNotSymmetric, m.Data)
+	}
+
+	n := len(m.Data)
+	tol := float64(n) * machineEpsilon * maxAbsEntry(m.Data)
+	L := GenerateIdentityMatrix(n)
+	D := make([]float64, n)
+
+	for j := 0; j < n; j++ {
+		d := m.Data[j][j]
+		for k := 0; k < j; k++ {
+			d -= L[j][k] * L[j][k] * D[k]
+		}
+		if math.Abs(d) <= tol {
+			return LDL{}, newShapeError("NewLDL", ErrSingular, m.Data)
+		}
+		D[j] = d
+
+		for i := j + 1; i < n; i++ {
+			s := m.Data[i][j]
+			for k := 0; k < j; k++ {
+				s -= L[i][k] * L[j][k] * D[k]
+			}
+			L[i][j] = s / d
+		}
+	}
+
+	return LDL{L: Matrix{Data: L}, D: D}, nil
+}
+
+// IsPositiveDefinite returns true if every entry of D is positive
+func (f LDL) IsPositiveDefinite() bool {
+	for _, d := range f.D {
+		if d <= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Solve solves A * x = b with L * y = b, D * z = y and L^T * x = z
+func (f LDL) Solve(b []float64) ([]float64, error) {
+	n := len(f.D)
+	if len(b) != n {
+		return nil, &ShapeError{
+			Op:     "LDL.Solve",
+			Shapes: []Shape{{Rows: n, Cols: n}, {Rows: len(b), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	L := f.L.Data
+	x := make([]float64, n)
+	copy(x, b)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= L[i][j] * x[j]
+		}
+	}
+	for i := range x {
+		x[i] /= f.D[i]
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= L[j][i] * x[j]
+		}
+	}
+
+	return x, nil
+}
+
+// NearestPositiveDefinite returns the symmetric positive definite matrix closest
+// to A in the Frobenius norm, up to a small margin so that NewCholesky succeeds.
+// A covariance matrix that went slightly indefinite from rounding comes back
+// almost unchanged.
+// It symmetrizes A, raises its negative eigenvalues to a small positive floor
+// and rebuilds the matrix from the eigenvectors (Higham, 1988). If rounding in
+// the rebuild leaves it indefinite, a growing multiple of the identity is added.
+// It returns an error if A is not square or has NaN or infinite entries, and
+// ErrNoConvergence if the shifts do not make it positive definite.
+func NearestPositiveDefinite(m Matrix) (Matrix, error) {
+	const op = "NearestPositiveDefinite"
+	if err := checkSquare(op, m.Data); err != nil {
+		return Matrix{}, err
+	}
+
+	n := len(m.Data)
+	if n == 0 {
+		return Matrix{Data: [][]float64{}}, nil
+	}
+	for i := range m.Data {
+		for j, v := range m.Data[i] {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return Matrix{}, fmt.Errorf("%s: %w: entry (%d, %d) is %v", op, ErrInvalidArgument, i, j, v)
+			}
+		}
+	}
+	B := make([][]float64, n)
+	for i := range B {
+		B[i] = make([]float64, n)
+		for j := range B[i] {
+			B[i][j] = (m.Data[i][j] + m.Data[j][i]) / 2
+		}
+	}
+	if IsPositiveDefinite(B) {
+		return Matrix{Data: B}, nil
+	}
+
+	values, vectors, err := EigenSym(Matrix{Data: B})
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	scale := math.Max(math.Abs(values[0]), math.Abs(values[n-1]))
+	if scale == 0 {
+		scale = 1
+	}
+	floor := float64(n) * machineEpsilon * scale
+	for i := range values {
+		values[i] = math.Max(values[i], floor)
+	}
+
+	// V * diag(values) * V^T
+	V := vectors.Data
+	res := make([][]float64, n)
+	for i := range res {
+		res[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			s := 0.0
+			for k := 0; k < n; k++ {
+				s += V[i][k] * values[k] * V[j][k]
+			}
+			res[i][j] = s
+			res[j][i] = s
+		}
+	}
+
+	// the shift passes the size of the entries after about 50 doublings, a
+	// matrix that is still indefinite then is not going to get there
+	const maxShifts = 64
+	shift := floor
+	for steps := 0; !IsPositiveDefinite(res); steps++ {
+		if steps == maxShifts {
+			return Matrix{}, &ConvergenceError{Op: op, Iterations: steps}
+		}
+		for i := range res {
+			res[i][i] += shift
+		}
+		shift *= 2
+	}
+
+	return Matrix{Data: res}, nil
+}
@@ -0,0 +1,287 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewCholesky(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  [][]float64
+		wantL   [][]float64
+		wantErr error
+	}{
+		{
+			name:   "3x3",
+			matrix: [][]float64{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}},
+			wantL:  [][]float64{{2, 0, 0}, {6, 1, 0}, {-8, 5, 3}},
+		},
+		{
+			name:   "identity",
+			matrix: GenerateIdentityMatrix(3),
+			wantL:  GenerateIdentityMatrix(3),
+		},
+		{
+			name:    "indefinite",
+			matrix:  [][]float64{{1, 2}, {2, 1}},
+			wantErr: ErrNotPositiveDefinite,
+		},
+		{
+			name:    "positive semidefinite",
+			matrix:  [][]float64{{1, 1}, {1, 1}},
+			wantErr: ErrNotPositiveDefinite,
+		},
+		{
+			name:    "not symmetric",
+			matrix:  [][]float64{{4, 1}, {2, 3}},
+			wantErr: ErrNotSymmetric,
+		},
+		{
+			name:    "not square",
+			matrix:  [][]float64{{1, 2, 3}},
+			wantErr: ErrNotSquare,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCholesky(Matrix{Data: tt.matrix})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewCholesky() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !areMatricesEqual(got.L.Data, tt.wantL) {
+				t.Errorf("NewCholesky() L = %v, want %v", got.L.Data, tt.wantL)
+			}
+			llt := MultiplyMatrices(got.L.Data, TransposeMatrix(got.L.Data))
+			if !areMatricesEqual(llt, tt.matrix) {
+				t.Errorf("L * L^T = %v, want %v", llt, tt.matrix)
+			}
+		})
+	}
+}
+
+func TestCholesky_SolveAndDet(t *testing.T) {
+	A := [][]float64{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}}
+	chol, err := NewCholesky(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NewCholesky() unexpected error: %v", err)
+	}
+
+	want := []float64{1, -2, 3}
+	b := MultiplyMatrices(A, RowToColumnVector(want))
+	got, err := chol.Solve(TransposeMatrix(b)[0])
+	if err != nil {
+		t.Fatalf("Cholesky.Solve() unexpected error: %v", err)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-10 {
+			t.Fatalf("Cholesky.Solve() = %v, want %v", got, want)
+		}
+	}
+
+	// det = (2 * 1 * 3)^2
+	if det := chol.Det(); math.Abs(det-36) > 1e-10 {
+		t.Errorf("Cholesky.Det() = %v, want 36", det)
+	}
+	if logDet := chol.LogDet(); math.Abs(logDet-math.Log(36)) > 1e-12 {
+		t.Errorf("Cholesky.LogDet() = %v, want %v", logDet, math.Log(36))
+	}
+	// the same convention as GetDeterminant
+	empty, err := NewCholesky(Matrix{Data: [][]float64{}})
+	if err != nil {
+		t.Fatalf("NewCholesky() unexpected error: %v", err)
+	}
+	if det, logDet := empty.Det(), empty.LogDet(); det != 0 || !math.IsInf(logDet, -1) {
+		t.Errorf("Det() and LogDet() of a 0x0 matrix = %v and %v, want 0 and -Inf", det, logDet)
+	}
+
+	if _, err := chol.Solve([]float64{1, 2}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Cholesky.Solve() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestIsPositiveDefinite(t *testing.T) {
+	cov := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true).GetCovarianceMatrix()
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   bool
+	}{
+		{name: "covariance matrix", matrix: cov.Data, want: true},
+		{name: "identity", matrix: GenerateIdentityMatrix(4), want: true},
+		{name: "indefinite", matrix: [][]float64{{1, 2}, {2, 1}}, want: false},
+		{name: "negative definite", matrix: [][]float64{{-2, 0}, {0, -1}}, want: false},
+		{name: "not symmetric", matrix: [][]float64{{2, 1}, {0, 2}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPositiveDefinite(tt.matrix); got != tt.want {
+				t.Errorf("IsPositiveDefinite() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLDL(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  [][]float64
+		wantD   []float64
+		wantPD  bool
+		wantErr error
+	}{
+		{
+			name:   "positive definite",
+			matrix: [][]float64{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}},
+			wantD:  []float64{4, 1, 9},
+			wantPD: true,
+		},
+		{
+			name:   "indefinite",
+			matrix: [][]float64{{1, 2}, {2, 1}},
+			wantD:  []float64{1, -3},
+		},
+		{
+			name:    "zero leading pivot",
+			matrix:  [][]float64{{0, 1}, {1, 0}},
+			wantErr: ErrSingular,
+		},
+		{
+			name:    "not symmetric",
+			matrix:  [][]float64{{1, 2}, {3, 1}},
+			wantErr: ErrNotSymmetric,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewLDL(Matrix{Data: tt.matrix})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewLDL() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			for i := range tt.wantD {
+				if math.Abs(got.D[i]-tt.wantD[i]) > 1e-10 {
+					t.Errorf("NewLDL() D = %v, want %v", got.D, tt.wantD)
+					break
+				}
+			}
+			if got.IsPositiveDefinite() != tt.wantPD {
+				t.Errorf("LDL.IsPositiveDefinite() = %v, want %v", got.IsPositiveDefinite(), tt.wantPD)
+			}
+
+			D := make([][]float64, len(got.D))
+			for i := range D {
+				D[i] = make([]float64, len(got.D))
+				D[i][i] = got.D[i]
+			}
+			ldlt := MultiplyMatrices(MultiplyMatrices(got.L.Data, D), TransposeMatrix(got.L.Data))
+			if !areMatricesEqual(ldlt, tt.matrix) {
+				t.Errorf("L * D * L^T = %v, want %v", ldlt, tt.matrix)
+			}
+
+			want := make([]float64, len(tt.matrix))
+			for i := range want {
+				want[i] = float64(i + 1)
+			}
+			b := TransposeMatrix(MultiplyMatrices(tt.matrix, RowToColumnVector(want)))[0]
+			x, err := got.Solve(b)
+			if err != nil {
+				t.Fatalf("LDL.Solve() unexpected error: %v", err)
+			}
+			for i := range want {
+				if math.Abs(x[i]-want[i]) > 1e-10 {
+					t.Errorf("LDL.Solve() = %v, want %v", x, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNearestPositiveDefinite(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+	}{
+		{
+			// a correlation matrix that went slightly indefinite
+			name:   "slightly indefinite",
+			matrix: [][]float64{{1, 0.9, 0.7}, {0.9, 1, 0.9999}, {0.7, 0.9999, 1}},
+		},
+		{
+			name:   "indefinite",
+			matrix: [][]float64{{1, 2}, {2, 1}},
+		},
+		{
+			name:   "not symmetric",
+			matrix: [][]float64{{2, 1}, {0, 2}},
+		},
+		{
+			name:   "zero matrix",
+			matrix: [][]float64{{0, 0}, {0, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NearestPositiveDefinite(Matrix{Data: tt.matrix})
+			if err != nil {
+				t.Fatalf("NearestPositiveDefinite() unexpected error: %v", err)
+			}
+			if !IsPositiveDefinite(got.Data) {
+				t.Errorf("NearestPositiveDefinite() = %v is not positive definite", got.Data)
+			}
+		})
+	}
+}
+
+func TestNearestPositiveDefiniteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   error
+	}{
+		{name: "not square", matrix: [][]float64{{1, 2}}, want: ErrNotSquare},
+		{name: "NaN", matrix: [][]float64{{1, math.NaN()}, {0, 1}}, want: ErrInvalidArgument},
+		{name: "infinite", matrix: [][]float64{{math.Inf(-1), 0}, {0, 1}}, want: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NearestPositiveDefinite(Matrix{Data: tt.matrix}); !errors.Is(err, tt.want) {
+				t.Errorf("NearestPositiveDefinite() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNearestPositiveDefiniteKnownResult(t *testing.T) {
+	// [[1, 2], [2, 1]] has eigenvalues 3 and -1 with eigenvectors (1, 1) and (1, -1),
+	// dropping the negative one leaves 3/2 * [[1, 1], [1, 1]]
+	got, err := NearestPositiveDefinite(Matrix{Data: [][]float64{{1, 2}, {2, 1}}})
+	if err != nil {
+		t.Fatalf("NearestPositiveDefinite() unexpected error: %v", err)
+	}
+	want := [][]float64{{1.5, 1.5}, {1.5, 1.5}}
+	for i := range want {
+		for j := range want[i] {
+			if math.Abs(got.Data[i][j]-want[i][j]) > 1e-10 {
+				t.Fatalf("NearestPositiveDefinite() = %v, want %v", got.Data, want)
+			}
+		}
+	}
+
+	// a matrix that is already positive definite is returned unchanged
+	A := [][]float64{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}}
+	got, err = NearestPositiveDefinite(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NearestPositiveDefinite() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(got.Data, A) {
+		t.Errorf("NearestPositiveDefinite() = %v, want %v", got.Data, A)
+	}
+}
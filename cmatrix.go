@@ -0,0 +1,643 @@
+package linearalgebra
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// CMatrix is a matrix of complex numbers, for example the eigenvectors
+// returned by GetEigenvectors. It supports the same arithmetic as Matrix
+// plus the conjugate transpose.
+type CMatrix struct {
+	Data [][]complex128
+}
+
+// NewCMatrix returns a CMatrix with a copy of data
+func NewCMatrix(data [][]complex128) CMatrix {
+	if !isCRectangular(data) {
+		res := make([][]complex128, len(data))
+		for i := range data {
+			res[i] = append([]complex128(nil), data[i]...)
+		}
+		return CMatrix{Data: res}
+	}
+
+	res := NewZeroCMatrix(len(data), cShape(data).Cols)
+	for i := range data {
+		copy(res.Data[i], data[i])
+	}
+
+	return res
+}
+
+// NewZeroCMatrix returns a rows x cols matrix of 0s backed by a single []complex128
+func NewZeroCMatrix(rows, cols int) CMatrix {
+	if rows < 0 || cols < 0 {
+		panic("illegal operation")
+	}
+
+	data, _ := contiguousRows[complex128](rows, cols)
+	return CMatrix{Data: data}
+}
+
+// NewCMatrixFromReal returns m with an imaginary part of 0
+func NewCMatrixFromReal(m Matrix) CMatrix {
+	res := NewZeroCMatrix(m.Rows(), m.Cols())
+	for i := range m.Data {
+		for j, v := range m.Data[i] {
+			res.Data[i][j] = complex(v, 0)
+		}
+	}
+
+	return res
+}
+
+// NewCMatrixFromColumns returns the matrix whose columns are the given vectors,
+// such as the eigenvectors returned by GetEigenvectors.
+// It returns ErrDimensionMismatch if the vectors have different lengths.
+func NewCMatrixFromColumns(columns [][]complex128) (CMatrix, error) {
+	if !isCRectangular(columns) {
+		return CMatrix{}, &ShapeError{Op: "NewCMatrixFromColumns", Shapes: []Shape{cShape(columns)}, Err: ErrDimensionMismatch}
+	}
+
+	return NewCMatrix(columns).Transpose(), nil
+}
+
+// cShape returns the shape of a complex matrix
+func cShape(matrix [][]complex128) Shape {
+	if len(matrix) == 0 {
+		return Shape{}
+	}
+
+	return Shape{Rows: len(matrix), Cols: len(matrix[0])}
+}
+
+// isCRectangular returns true if all the rows have the same length
+func isCRectangular(matrix [][]complex128) bool {
+	for i := range matrix {
+		if len(matrix[i]) != len(matrix[0]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newCShapeError returns a ShapeError with the shapes of the given complex matrices
+func newCShapeError(op string, err error, matrices ...CMatrix) *ShapeError {
+	shapes := make([]Shape, len(matrices))
+	for i := range matrices {
+		shapes[i] = cShape(matrices[i].Data)
+	}
+
+	return &ShapeError{Op: op, Shapes: shapes, Err: err}
+}
+
+// Dims returns the number of rows and columns
+func (m CMatrix) Dims() (int, int) {
+	return m.Rows(), m.Cols()
+}
+
+// Rows returns the number of rows
+func (m CMatrix) Rows() int {
+	return len(m.Data)
+}
+
+// Cols returns the number of columns
+func (m CMatrix) Cols() int {
+	return cShape(m.Data).Cols
+}
+
+// At returns the element in row i and column j
+func (m CMatrix) At(i, j int) complex128 {
+	if i < 0 || i >= m.Rows() || j < 0 || j >= m.Cols() {
+		panic("index out of bounds")
+	}
+
+	return m.Data[i][j]
+}
+
+// Add returns A + B
+func (m CMatrix) Add(b CMatrix) (CMatrix, error) {
+	return m.elementwise("CMatrix.Add", b, func(x, y complex128) complex128 { return x + y })
+}
+
+// Sub returns A - B
+func (m CMatrix) Sub(b CMatrix) (CMatrix, error) {
+	return m.elementwise("CMatrix.Sub", b, func(x, y complex128) complex128 { return x - y })
+}
+
+// elementwise returns f applied to every pair of entries of A and B
+func (m CMatrix) elementwise(op string, b CMatrix, f func(x, y complex128) complex128) (CMatrix, error) {
+	if !isCRectangular(m.Data) || !isCRectangular(b.Data) || cShape(m.Data) != cShape(b.Data) {
+		return CMatrix{}, newCShapeError(op, ErrDimensionMismatch, m, b)
+	}
+
+	res := NewZeroCMatrix(m.Dims())
+	for i := range m.Data {
+		for j := range m.Data[i] {
+			res.Data[i][j] = f(m.Data[i][j], b.Data[i][j])
+		}
+	}
+
+	return res, nil
+}
+
+// Scale returns c * A
+func (m CMatrix) Scale(c complex128) CMatrix {
+	res := NewCMatrix(m.Data)
+	for i := range res.Data {
+		for j := range res.Data[i] {
+			res.Data[i][j] *= c
+		}
+	}
+
+	return res
+}
+
+// Mul returns A * B
+func (m CMatrix) Mul(b CMatrix) (CMatrix, error) {
+	if !isCRectangular(m.Data) || !isCRectangular(b.Data) || m.Cols() != b.Rows() {
+		return CMatrix{}, newCShapeError("CMatrix.Mul", ErrDimensionMismatch, m, b)
+	}
+
+	res := NewZeroCMatrix(m.Rows(), b.Cols())
+	for i := range m.Data {
+		out := res.Data[i]
+		for k, a := range m.Data[i] {
+			if a == 0 {
+				continue
+			}
+			for j, v := range b.Data[k] {
+				out[j] += a * v
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// MulVec returns A * x
+func (m CMatrix) MulVec(x []complex128) ([]complex128, error) {
+	if !isCRectangular(m.Data) || len(x) != m.Cols() {
+		return nil, &ShapeError{
+			Op:     "CMatrix.MulVec",
+			Shapes: []Shape{cShape(m.Data), {Rows: len(x), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := make([]complex128, m.Rows())
+	for i, row := range m.Data {
+		for j, v := range row {
+			res[i] += v * x[j]
+		}
+	}
+
+	return res, nil
+}
+
+// Transpose returns A^T, the entries are not conjugated
+func (m CMatrix) Transpose() CMatrix {
+	res := NewZeroCMatrix(m.Cols(), m.Rows())
+	for i := range m.Data {
+		for j, v := range m.Data[i] {
+			res.Data[j][i] = v
+		}
+	}
+
+	return res
+}
+
+// Conj returns the matrix with every entry conjugated
+func (m CMatrix) Conj() CMatrix {
+	res := NewCMatrix(m.Data)
+	for i := range res.Data {
+		for j, v := range res.Data[i] {
+			res.Data[i][j] = cmplx.Conj(v)
+		}
+	}
+
+	return res
+}
+
+// ConjugateTranspose returns the Hermitian adjoint A^H, the transpose with
+// every entry conjugated. For a real matrix it is the transpose.
+func (m CMatrix) ConjugateTranspose() CMatrix {
+	res := NewZeroCMatrix(m.Cols(), m.Rows())
+	for i := range m.Data {
+		for j, v := range m.Data[i] {
+			res.Data[j][i] = cmplx.Conj(v)
+		}
+	}
+
+	return res
+}
+
+// Real returns the real parts of the entries
+func (m CMatrix) Real() Matrix {
+	res := NewZeroMatrix(m.Dims())
+	for i := range m.Data {
+		for j, v := range m.Data[i] {
+			res.Data[i][j] = real(v)
+		}
+	}
+
+	return res
+}
+
+// Imag returns the imaginary parts of the entries
+func (m CMatrix) Imag() Matrix {
+	res := NewZeroMatrix(m.Dims())
+	for i := range m.Data {
+		for j, v := range m.Data[i] {
+			res.Data[i][j] = imag(v)
+		}
+	}
+
+	return res
+}
+
+// maxAbsCEntry returns the largest absolute value in the matrix
+func maxAbsCEntry(matrix [][]complex128) float64 {
+	res := 0.0
+	for i := range matrix {
+		for j := range matrix[i] {
+			if v := cmplx.Abs(matrix[i][j]); v > res {
+				res = v
+			}
+		}
+	}
+
+	return res
+}
+
+// IsHermitian returns true if the matrix is square and A[i][j] == conj(A[j][i])
+// up to a tolerance relative to the largest entry of the matrix, like
+// IsMatrixSymmetric. The diagonal of a Hermitian matrix is real.
+// A matrix with a NaN entry is not Hermitian.
+func (m CMatrix) IsHermitian() bool {
+	n := m.Rows()
+	if !isCRectangular(m.Data) || m.Cols() != n {
+		return false
+	}
+
+	tol := symmetryTolerance * maxAbsCEntry(m.Data)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			if cmplx.IsNaN(m.Data[i][j]) || cmplx.IsNaN(m.Data[j][i]) ||
+				cmplx.Abs(m.Data[i][j]-cmplx.Conj(m.Data[j][i])) > tol {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Det returns the determinant of a square matrix
+func (m CMatrix) Det() (complex128, error) {
+	lu, err := NewCLU(m)
+	if err != nil {
+		return 0, err
+	}
+
+	return lu.Det(), nil
+}
+
+// Inverse returns A^-1, ErrSingular if A is not invertible
+func (m CMatrix) Inverse() (CMatrix, error) {
+	lu, err := NewCLU(m)
+	if err != nil {
+		return CMatrix{}, err
+	}
+
+	return lu.Inverse()
+}
+
+// CLU is the LU factorization with partial pivoting of a square complex matrix A
+// P * A = L * U
+// with the same layout as LU.
+type CLU struct {
+	// P is the permutation matrix that records the row swaps
+	P Matrix
+	// L is unit lower triangular, it holds the multipliers used in the elimination
+	L CMatrix
+	// U is upper triangular, it is the result of the elimination
+	U CMatrix
+	// Pivots[i] is the row of A that ends up in row i of P * A
+	Pivots []int
+
+	// lu holds L below the diagonal and U on and above the diagonal
+	lu   [][]complex128
+	sign float64
+}
+
+// NewCLU computes the LU factorization of a square complex matrix, the pivot
+// is the entry of largest absolute value in its column.
+// A singular matrix can still be factored, but Solve and Inverse will return ErrSingular.
+func NewCLU(m CMatrix) (CLU, error) {
+	n := m.Rows()
+	if !isCRectangular(m.Data) || m.Cols() != n {
+		return CLU{}, newCShapeError("NewCLU", ErrNotSquare, m)
+	}
+
+	lu := NewCMatrix(m.Data).Data
+	pivots := make([]int, n)
+	for i := range pivots {
+		pivots[i] = i
+	}
+	sign := 1.0
+
+	for col := 0; col < n; col++ {
+		bestRow := col
+		bestVal := cmplx.Abs(lu[col][col])
+		for r := col + 1; r < n; r++ {
+			if v := cmplx.Abs(lu[r][col]); v > bestVal {
+				bestVal = v
+				bestRow = r
+			}
+		}
+
+		if bestRow != col {
+			lu[col], lu[bestRow] = lu[bestRow], lu[col]
+			pivots[col], pivots[bestRow] = pivots[bestRow], pivots[col]
+			sign = -sign
+		}
+
+		pivot := lu[col][col]
+		if pivot == 0 {
+			continue
+		}
+
+		for r := col + 1; r < n; r++ {
+			factor := lu[r][col] / pivot
+			lu[r][col] = factor
+			if factor == 0 {
+				continue
+			}
+			for j := col + 1; j < n; j++ {
+				lu[r][j] -= factor * lu[col][j]
+			}
+		}
+	}
+
+	// the pivots that are rounding noise of an exactly singular matrix become 0,
+	// with the same per pivot bound as in NewLU
+	for i := 0; i < n; i++ {
+		scale := cmplx.Abs(lu[i][i])
+		for k := 0; k < i; k++ {
+			scale += cmplx.Abs(lu[i][k]) * cmplx.Abs(lu[k][i])
+		}
+		if cmplx.Abs(lu[i][i]) <= float64(n)*machineEpsilon*scale {
+			lu[i][i] = 0
+		}
+	}
+
+	L := NewZeroCMatrix(n, n)
+	U := NewZeroCMatrix(n, n)
+	P := NewZeroMatrix(n, n)
+	for i := 0; i < n; i++ {
+		P.Data[i][pivots[i]] = 1
+		L.Data[i][i] = 1
+		for j := 0; j < n; j++ {
+			if j < i {
+				L.Data[i][j] = lu[i][j]
+			} else {
+				U.Data[i][j] = lu[i][j]
+			}
+		}
+	}
+
+	return CLU{
+		P:      P,
+		L:      L,
+		U:      U,
+		Pivots: pivots,
+		lu:     lu,
+		sign:   sign,
+	}, nil
+}
+
+// IsSingular returns true if any pivot of U is 0. NewCLU sets the pivots that
+// are rounding noise to 0, so IsSingular agrees with Det.
+func (f CLU) IsSingular() bool {
+	for i := range f.lu {
+		if f.lu[i][i] == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Det returns the determinant of A, the product of the pivots of U
+// with the sign flipped once for every row swap.
+// A 0x0 matrix has determinant 0, like for GetDeterminant and LU.Det.
+func (f CLU) Det() complex128 {
+	if len(f.lu) == 0 {
+		return 0
+	}
+
+	det := complex(f.sign, 0)
+	for i := range f.lu {
+		det *= f.lu[i][i]
+	}
+
+	return det
+}
+
+// Solve solves A * x = b using the factorization
+func (f CLU) Solve(b []complex128) ([]complex128, error) {
+	n := len(f.lu)
+	if len(b) != n {
+		return nil, &ShapeError{
+			Op:     "CLU.Solve",
+			Shapes: []Shape{{Rows: n, Cols: n}, {Rows: len(b), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+	if f.IsSingular() {
+		return nil, &ShapeError{Op: "CLU.Solve", Shapes: []Shape{{Rows: n, Cols: n}}, Err: ErrSingular}
+	}
+
+	x := make([]complex128, n)
+	for i := 0; i < n; i++ {
+		x[i] = b[f.Pivots[i]]
+	}
+	f.solveInPlace(x)
+
+	return x, nil
+}
+
+// solveInPlace replaces the permuted right hand side x with the solution
+func (f CLU) solveInPlace(x []complex128) {
+	n := len(f.lu)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= f.lu[i][j] * x[j]
+		}
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= f.lu[i][j] * x[j]
+		}
+		x[i] /= f.lu[i][i]
+	}
+}
+
+// SolveMatrix solves A * X = B for every column of B
+func (f CLU) SolveMatrix(B CMatrix) (CMatrix, error) {
+	n := len(f.lu)
+	if B.Rows() != n || !isCRectangular(B.Data) {
+		return CMatrix{}, &ShapeError{
+			Op:     "CLU.SolveMatrix",
+			Shapes: []Shape{{Rows: n, Cols: n}, cShape(B.Data)},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+	if f.IsSingular() {
+		return CMatrix{}, &ShapeError{Op: "CLU.SolveMatrix", Shapes: []Shape{{Rows: n, Cols: n}}, Err: ErrSingular}
+	}
+
+	X := NewZeroCMatrix(n, B.Cols())
+	column := make([]complex128, n)
+	for j := 0; j < B.Cols(); j++ {
+		for i := 0; i < n; i++ {
+			column[i] = B.Data[f.Pivots[i]][j]
+		}
+		f.solveInPlace(column)
+		for i := 0; i < n; i++ {
+			X.Data[i][j] = column[i]
+		}
+	}
+
+	return X, nil
+}
+
+// Inverse returns A^-1 by solving A * X = I
+func (f CLU) Inverse() (CMatrix, error) {
+	return f.SolveMatrix(NewCMatrixFromReal(NewMatrix(GenerateIdentityMatrix(len(f.lu)))))
+}
+
+// EigenHermitian returns the eigenvalues and eigenvectors of a Hermitian matrix.
+// Like for a real symmetric matrix the eigenvalues are real, they are returned
+// in descending order, and the eigenvectors are the orthonormal columns of the
+// returned matrix, column i belongs to eigenvalue i.
+// It uses the complex cyclic Jacobi method, the same as EigenSym with every
+// rotation preceded by a phase change that makes A[p][q] real.
+// It returns ErrNotSquare, ErrNotSymmetric if the matrix is not Hermitian and
+// a ConvergenceError if it is not diagonal after jacobiSweeps sweeps.
+func EigenHermitian(m CMatrix) ([]float64, CMatrix, error) {
+	n := m.Rows()
+	if !isCRectangular(m.Data) || m.Cols() != n {
+		return nil, CMatrix{}, newCShapeError("EigenHermitian", ErrNotSquare, m)
+	}
+	if !m.IsHermitian() {
+		return nil, CMatrix{}, newCShapeError("EigenHermitian", ErrNotSymmetric, m)
+	}
+
+	// average with the conjugate transpose to remove the rounding noise below the tolerance
+	A := NewZeroCMatrix(n, n).Data
+	for i := range A {
+		for j := range A[i] {
+			A[i][j] = (m.Data[i][j] + cmplx.Conj(m.Data[j][i])) / 2
+		}
+	}
+	V := NewCMatrixFromReal(NewMatrix(GenerateIdentityMatrix(n))).Data
+
+	for sweep := 0; ; sweep++ {
+		offDiagonal, total := 0.0, 0.0
+		for i := range A {
+			for j := range A[i] {
+				abs2 := real(A[i][j])*real(A[i][j]) + imag(A[i][j])*imag(A[i][j])
+				total += abs2
+				if i != j {
+					offDiagonal += abs2
+				}
+			}
+		}
+		if offDiagonal <= machineEpsilon*machineEpsilon*total {
+			break
+		}
+		if sweep == jacobiSweeps {
+			return nil, CMatrix{}, &ConvergenceError{Op: "EigenHermitian", Iterations: sweep}
+		}
+
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if A[p][q] == 0 {
+					continue
+				}
+				complexJacobiRotate(A, V, p, q)
+			}
+		}
+	}
+
+	eigenvalues := make([]float64, n)
+	for i := range eigenvalues {
+		eigenvalues[i] = real(A[i][i])
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return eigenvalues[order[i]] > eigenvalues[order[j]]
+	})
+
+	sortedValues := make([]float64, n)
+	vectors := NewZeroCMatrix(n, n)
+	for newIndex, oldIndex := range order {
+		sortedValues[newIndex] = eigenvalues[oldIndex]
+		for i := 0; i < n; i++ {
+			vectors.Data[i][newIndex] = V[i][oldIndex]
+		}
+	}
+
+	return sortedValues, vectors, nil
+}
+
+// complexJacobiRotate applies the unitary G = D * J that zeroes A[p][q], where
+// D multiplies column q by the phase that makes A[p][q] real and J is the
+// rotation of jacobiRotate. A becomes G^H * A * G and V becomes V * G.
+func complexJacobiRotate(A, V [][]complex128, p, q int) {
+	n := len(A)
+	r := cmplx.Abs(A[p][q])
+	phase := cmplx.Conj(A[p][q]) / complex(r, 0)
+
+	theta := (real(A[q][q]) - real(A[p][p])) / (2 * r)
+	t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+	if theta < 0 {
+		t = -t
+	}
+	c := 1 / math.Sqrt(t*t+1)
+	s := t * c
+
+	// columns p and q of G
+	gpp, gqp := complex(c, 0), -complex(s, 0)*phase
+	gpq, gqq := complex(s, 0), complex(c, 0)*phase
+
+	for k := 0; k < n; k++ {
+		akp, akq := A[k][p], A[k][q]
+		A[k][p] = akp*gpp + akq*gqp
+		A[k][q] = akp*gpq + akq*gqq
+	}
+	for k := 0; k < n; k++ {
+		apk, aqk := A[p][k], A[q][k]
+		A[p][k] = cmplx.Conj(gpp)*apk + cmplx.Conj(gqp)*aqk
+		A[q][k] = cmplx.Conj(gpq)*apk + cmplx.Conj(gqq)*aqk
+	}
+	// the rotation zeroes them up to rounding, and the diagonal of A stays real
+	A[p][q], A[q][p] = 0, 0
+	A[p][p], A[q][q] = complex(real(A[p][p]), 0), complex(real(A[q][q]), 0)
+
+	for k := 0; k < n; k++ {
+		vkp, vkq := V[k][p], V[k][q]
+		V[k][p] = vkp*gpp + vkq*gqp
+		V[k][q] = vkp*gpq + vkq*gqq
+	}
+}
@@ -0,0 +1,358 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// areCMatricesEqual returns true if the matrices have the same shape and
+// their entries differ by at most tol
+func areCMatricesEqual(a, b CMatrix, tol float64) bool {
+	if a.Rows() != b.Rows() || a.Cols() != b.Cols() {
+		return false
+	}
+	for i := range a.Data {
+		for j := range a.Data[i] {
+			if cmplx.Abs(a.Data[i][j]-b.Data[i][j]) > tol {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// cIdentity returns the n x n complex identity matrix
+func cIdentity(n int) CMatrix {
+	return NewCMatrixFromReal(NewMatrix(GenerateIdentityMatrix(n)))
+}
+
+// randomHermitian returns a random n x n Hermitian matrix
+func randomHermitian(rng *rand.Rand, n int) CMatrix {
+	res := NewZeroCMatrix(n, n)
+	for i := 0; i < n; i++ {
+		res.Data[i][i] = complex(rng.NormFloat64(), 0)
+		for j := i + 1; j < n; j++ {
+			v := complex(rng.NormFloat64(), rng.NormFloat64())
+			res.Data[i][j] = v
+			res.Data[j][i] = cmplx.Conj(v)
+		}
+	}
+
+	return res
+}
+
+// mustCMatrix returns a function that fails the test on a non nil error
+func mustCMatrix(t *testing.T) func(CMatrix, error) CMatrix {
+	return func(m CMatrix, err error) CMatrix {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return m
+	}
+}
+
+func TestCMatrixArithmetic(t *testing.T) {
+	must := mustCMatrix(t)
+	A := NewCMatrix([][]complex128{{1 + 1i, 2}, {-1i, 3 - 2i}})
+	B := NewCMatrix([][]complex128{{2, 1i}, {1, 1 + 1i}})
+
+	tests := []struct {
+		name string
+		got  CMatrix
+		want [][]complex128
+	}{
+		{name: "Add", got: must(A.Add(B)), want: [][]complex128{{3 + 1i, 2 + 1i}, {1 - 1i, 4 - 1i}}},
+		{name: "Sub", got: must(A.Sub(B)), want: [][]complex128{{-1 + 1i, 2 - 1i}, {-1 - 1i, 2 - 3i}}},
+		{name: "Scale", got: A.Scale(1i), want: [][]complex128{{-1 + 1i, 2i}, {1, 2 + 3i}}},
+		{name: "Mul", got: must(A.Mul(B)), want: [][]complex128{{4 + 2i, 1 + 3i}, {3 - 4i, 6 + 1i}}},
+		{name: "Conj", got: A.Conj(), want: [][]complex128{{1 - 1i, 2}, {1i, 3 + 2i}}},
+		{name: "Transpose", got: A.Transpose(), want: [][]complex128{{1 + 1i, -1i}, {2, 3 - 2i}}},
+		{name: "ConjugateTranspose", got: A.ConjugateTranspose(), want: [][]complex128{{1 - 1i, 1i}, {2, 3 + 2i}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !areCMatricesEqual(tt.got, NewCMatrix(tt.want), 1e-12) {
+				t.Errorf("%s() = %v, want %v", tt.name, tt.got.Data, tt.want)
+			}
+		})
+	}
+
+	// (A * B)^H = B^H * A^H
+	AB := must(A.Mul(B))
+	BhAh := must(B.ConjugateTranspose().Mul(A.ConjugateTranspose()))
+	if !areCMatricesEqual(AB.ConjugateTranspose(), BhAh, 1e-12) {
+		t.Errorf("(A*B)^H = %v, want B^H*A^H = %v", AB.ConjugateTranspose().Data, BhAh.Data)
+	}
+
+	x, err := A.MulVec([]complex128{1, 1i})
+	if err != nil {
+		t.Fatalf("MulVec() unexpected error: %v", err)
+	}
+	if want := []complex128{1 + 3i, 2 + 2i}; cmplx.Abs(x[0]-want[0]) > 1e-12 || cmplx.Abs(x[1]-want[1]) > 1e-12 {
+		t.Errorf("MulVec() = %v, want %v", x, want)
+	}
+
+	if got := A.Real(); !areMatricesEqual(got.Data, [][]float64{{1, 2}, {0, 3}}) {
+		t.Errorf("Real() = %v", got.Data)
+	}
+	if got := A.Imag(); !areMatricesEqual(got.Data, [][]float64{{1, 0}, {-1, -2}}) {
+		t.Errorf("Imag() = %v", got.Data)
+	}
+}
+
+func TestCMatrixErrors(t *testing.T) {
+	A := NewCMatrix([][]complex128{{1, 2, 3}, {4, 5, 6}})
+	if _, err := A.Add(A.Transpose()); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Add() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := A.Mul(A); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Mul() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := A.MulVec([]complex128{1}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("MulVec() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := A.Det(); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Det() error = %v, want %v", err, ErrNotSquare)
+	}
+	// the same convention as GetDeterminant
+	if det, err := NewCMatrix([][]complex128{}).Det(); err != nil || det != 0 {
+		t.Errorf("Det() of a 0x0 matrix = %v, %v, want 0", det, err)
+	}
+	if _, _, err := EigenHermitian(A); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("EigenHermitian() error = %v, want %v", err, ErrNotSquare)
+	}
+	notHermitian := NewCMatrix([][]complex128{{1, 1i}, {1i, 1}})
+	if _, _, err := EigenHermitian(notHermitian); !errors.Is(err, ErrNotSymmetric) {
+		t.Errorf("EigenHermitian() error = %v, want %v", err, ErrNotSymmetric)
+	}
+	withNaN := NewCMatrix([][]complex128{{cmplx.NaN(), 0}, {0, 1}})
+	if _, _, err := EigenHermitian(withNaN); !errors.Is(err, ErrNotSymmetric) {
+		t.Errorf("EigenHermitian() error = %v, want %v", err, ErrNotSymmetric)
+	}
+	singular := NewCMatrix([][]complex128{{1, 1i}, {1i, -1}})
+	if _, err := singular.Inverse(); !errors.Is(err, ErrSingular) {
+		t.Errorf("Inverse() error = %v, want %v", err, ErrSingular)
+	}
+	if _, err := NewCMatrixFromColumns([][]complex128{{1, 2}, {3}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("NewCMatrixFromColumns() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestCLU(t *testing.T) {
+	tests := []struct {
+		name string
+		A    CMatrix
+		det  complex128
+	}{
+		{name: "2x2", A: NewCMatrix([][]complex128{{1 + 1i, 2}, {3, 4 - 1i}}), det: -1 + 3i},
+		{name: "real", A: NewCMatrixFromReal(NewMatrix([][]float64{{2, 1, 1}, {4, -6, 0}, {-2, 7, 2}})), det: -16},
+		{name: "needs pivoting", A: NewCMatrix([][]complex128{{0, 1i, 2}, {1, 0, 0}, {0, 0, 1 - 1i}}), det: -1 - 1i},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			must := mustCMatrix(t)
+			lu, err := NewCLU(tt.A)
+			if err != nil {
+				t.Fatalf("NewCLU() unexpected error: %v", err)
+			}
+			PA := must(NewCMatrixFromReal(lu.P).Mul(tt.A))
+			LU := must(lu.L.Mul(lu.U))
+			if !areCMatricesEqual(PA, LU, 1e-12) {
+				t.Errorf("P*A = %v, want L*U = %v", PA.Data, LU.Data)
+			}
+
+			det, err := tt.A.Det()
+			if err != nil {
+				t.Fatalf("Det() unexpected error: %v", err)
+			}
+			if cmplx.Abs(det-tt.det) > 1e-12 {
+				t.Errorf("Det() = %v, want %v", det, tt.det)
+			}
+
+			inverse, err := tt.A.Inverse()
+			if err != nil {
+				t.Fatalf("Inverse() unexpected error: %v", err)
+			}
+			if got := must(tt.A.Mul(inverse)); !areCMatricesEqual(got, cIdentity(tt.A.Rows()), 1e-12) {
+				t.Errorf("A * Inverse() = %v, want I", got.Data)
+			}
+
+			b := make([]complex128, tt.A.Rows())
+			for i := range b {
+				b[i] = complex(float64(i+1), -1)
+			}
+			x, err := lu.Solve(b)
+			if err != nil {
+				t.Fatalf("Solve() unexpected error: %v", err)
+			}
+			ax, _ := tt.A.MulVec(x)
+			for i := range b {
+				if cmplx.Abs(ax[i]-b[i]) > 1e-12 {
+					t.Errorf("A * Solve(b) = %v, want %v", ax, b)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCLUIsSingular(t *testing.T) {
+	tests := []struct {
+		name         string
+		A            CMatrix
+		wantSingular bool
+		wantDet      complex128
+	}{
+		{
+			// the last row is the sum of the first two, elimination leaves rounding noise in the last pivot
+			name:         "singular with rounding noise",
+			A:            NewCMatrix([][]complex128{{1 + 1i, 2, 3}, {4, 5i, 6}, {5 + 1i, 2 + 5i, 9}}),
+			wantSingular: true,
+			wantDet:      0,
+		},
+		{
+			name:         "badly scaled",
+			A:            NewCMatrix([][]complex128{{1e20, 0, 0, 0}, {0, 1i, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, -1i}}),
+			wantSingular: false,
+			wantDet:      1e20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lu, err := NewCLU(tt.A)
+			if err != nil {
+				t.Fatalf("NewCLU() unexpected error: %v", err)
+			}
+			if got := lu.IsSingular(); got != tt.wantSingular {
+				t.Errorf("IsSingular() = %v, want %v", got, tt.wantSingular)
+			}
+			if got := lu.Det(); got != tt.wantDet {
+				t.Errorf("Det() = %v, want %v", got, tt.wantDet)
+			}
+			_, err = lu.Inverse()
+			if tt.wantSingular && !errors.Is(err, ErrSingular) {
+				t.Errorf("Inverse() error = %v, want %v", err, ErrSingular)
+			}
+			if !tt.wantSingular && err != nil {
+				t.Errorf("Inverse() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCMatrixIsHermitian(t *testing.T) {
+	tests := []struct {
+		name string
+		A    CMatrix
+		want bool
+	}{
+		{name: "hermitian", A: NewCMatrix([][]complex128{{2, 1 - 1i}, {1 + 1i, 3}}), want: true},
+		{name: "symmetric not hermitian", A: NewCMatrix([][]complex128{{2, 1i}, {1i, 3}}), want: false},
+		{name: "complex diagonal", A: NewCMatrix([][]complex128{{1i, 0}, {0, 1}}), want: false},
+		{name: "small entries", A: NewCMatrix([][]complex128{{0, 1e-12i}, {0, 0}}), want: false},
+		{name: "zero matrix", A: NewCMatrix([][]complex128{{0, 0}, {0, 0}}), want: true},
+		{name: "NaN", A: NewCMatrix([][]complex128{{1, cmplx.NaN()}, {cmplx.NaN(), 1}}), want: false},
+		{name: "not square", A: NewCMatrix([][]complex128{{1, 2}}), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.A.IsHermitian(); got != tt.want {
+				t.Errorf("IsHermitian() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEigenHermitian(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	tests := []struct {
+		name string
+		A    CMatrix
+		want []float64
+	}{
+		{name: "Pauli Y", A: NewCMatrix([][]complex128{{0, -1i}, {1i, 0}}), want: []float64{1, -1}},
+		{name: "diagonal", A: NewCMatrix([][]complex128{{-2, 0}, {0, 5}}), want: []float64{5, -2}},
+		{name: "repeated", A: NewCMatrix([][]complex128{{2, 1 - 1i, 0}, {1 + 1i, 1, 0}, {0, 0, 3}}), want: []float64{3, 3, 0}},
+		{name: "random 6x6", A: randomHermitian(rng, 6)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			must := mustCMatrix(t)
+			values, V, err := EigenHermitian(tt.A)
+			if err != nil {
+				t.Fatalf("EigenHermitian() unexpected error: %v", err)
+			}
+			for i := range tt.want {
+				if d := values[i] - tt.want[i]; d > 1e-12 || d < -1e-12 {
+					t.Errorf("EigenHermitian() values = %v, want %v", values, tt.want)
+					break
+				}
+			}
+			for i := 1; i < len(values); i++ {
+				if values[i] > values[i-1] {
+					t.Errorf("EigenHermitian() values = %v, want descending", values)
+				}
+			}
+
+			// A * V = V * diag(values) and V^H * V = I
+			n := tt.A.Rows()
+			lambda := NewZeroCMatrix(n, n)
+			for i, v := range values {
+				lambda.Data[i][i] = complex(v, 0)
+			}
+			AV := must(tt.A.Mul(V))
+			VL := must(V.Mul(lambda))
+			if !areCMatricesEqual(AV, VL, 1e-10) {
+				t.Errorf("A*V = %v, want V*diag(values) = %v", AV.Data, VL.Data)
+			}
+			if VhV := must(V.ConjugateTranspose().Mul(V)); !areCMatricesEqual(VhV, cIdentity(n), 1e-12) {
+				t.Errorf("V^H*V = %v, want I", VhV.Data)
+			}
+		})
+	}
+
+	// a real symmetric matrix has the eigenvalues of EigenSym
+	symmetric := NewMatrix([][]float64{{4, 1, 2}, {1, 3, 0}, {2, 0, 1}})
+	want, _, err := EigenSym(symmetric)
+	if err != nil {
+		t.Fatalf("EigenSym() unexpected error: %v", err)
+	}
+	got, _, err := EigenHermitian(NewCMatrixFromReal(symmetric))
+	if err != nil {
+		t.Fatalf("EigenHermitian() unexpected error: %v", err)
+	}
+	for i := range want {
+		if d := got[i] - want[i]; d > 1e-12 || d < -1e-12 {
+			t.Errorf("EigenHermitian() = %v, want EigenSym() = %v", got, want)
+			break
+		}
+	}
+}
+
+func TestGetEigenvectorsAsCMatrix(t *testing.T) {
+	must := mustCMatrix(t)
+	// a rotation has the complex eigenvalues ±i
+	matrix := [][]float64{{0, -1, 0}, {1, 0, 0}, {0, 0, 2}}
+	values := GetEigenvalues(matrix)
+	V, err := NewCMatrixFromColumns(GetEigenvectors(matrix))
+	if err != nil {
+		t.Fatalf("NewCMatrixFromColumns() unexpected error: %v", err)
+	}
+
+	lambda := NewZeroCMatrix(len(values), len(values))
+	for i, v := range values {
+		lambda.Data[i][i] = v
+	}
+	AV := must(NewCMatrixFromReal(NewMatrix(matrix)).Mul(V))
+	VL := must(V.Mul(lambda))
+	if !areCMatricesEqual(AV, VL, 1e-10) {
+		t.Errorf("A*V = %v, want V*diag(values) = %v", AV.Data, VL.Data)
+	}
+	if det, err := V.Det(); err != nil || cmplx.Abs(det) < 1e-6 {
+		t.Errorf("Det(V) = %v, %v, want the eigenvectors to be independent", det, err)
+	}
+}
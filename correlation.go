@@ -0,0 +1,109 @@
+package linearalgebra
+
+import "math"
+
+// columnMeansAndStds returns the mean and the sample standard deviation of
+// every column, with the constant columns scaled by standardizingScale. Any
+// column of a matrix with fewer than 2 rows gets std 1 too.
+func columnMeansAndStds(data [][]float64) (means, stds []float64) {
+	rows := len(data)
+	if rows == 0 {
+		return []float64{}, []float64{}
+	}
+	cols := len(data[0])
+	means = make([]float64, cols)
+	stds = make([]float64, cols)
+	column := make([]float64, rows)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			means[j] += data[i][j]
+		}
+		means[j] /= float64(rows)
+
+		stds[j] = 1
+		if rows < 2 {
+			continue
+		}
+		for i := 0; i < rows; i++ {
+			column[i] = data[i][j] - means[j]
+		}
+		// norm2 scales the entries so the sum of squares cannot overflow
+		stds[j] = standardizingScale(norm2(column) / math.Sqrt(float64(rows-1)))
+	}
+
+	return means, stds
+}
+
+// standardizingScale returns what a centered column with standard deviation
+// std is divided by to standardize it: std itself, or 1 for a constant column
+// so it stays 0 instead of becoming NaN. Standardizing from the data, as
+// FitPCA does, and from the covariance, as CovarianceAccumulator.FitPCA does,
+// both go through it so the two agree on constant columns.
+func standardizingScale(std float64) float64 {
+	if std > 0 {
+		return std
+	}
+
+	return 1
+}
+
+// StandardizeMatrix returns the z-scores of the columns: every column minus
+// its mean, divided by its sample standard deviation, so all columns have mean 0
+// and variance 1 whatever their units. A constant column becomes 0.
+func StandardizeMatrix(m Matrix) Matrix {
+	if len(m.Data) == 0 || len(m.Data[0]) == 0 {
+		return m
+	}
+
+	means, stds := columnMeansAndStds(m.Data)
+	standardized := NewMatrix(m.Data)
+	for i := range standardized.Data {
+		for j := range standardized.Data[i] {
+			standardized.Data[i][j] = (standardized.Data[i][j] - means[j]) / stds[j]
+		}
+	}
+
+	return standardized
+}
+
+// Standardize replaces the columns with their z-scores, see StandardizeMatrix
+func (m *Matrix) Standardize() {
+	*m = StandardizeMatrix(*m)
+}
+
+// GetCorrelationMatrix returns the Pearson correlation matrix of the columns,
+// the covariance matrix of the standardized data:
+// corr(X)[i][j] = cov(X)[i][j] / (std_i * std_j)
+// The diagonal is 1 and every entry is in [-1, 1]. Unlike the covariance it does
+// not depend on the units of the columns. A constant column has correlation 0
+// with every other column.
+func (m Matrix) GetCorrelationMatrix() Matrix {
+	return correlationFromCovariance(m.GetCovarianceMatrix())
+}
+
+// correlationFromCovariance divides every covariance by the standard deviations
+// on the diagonal, see GetCorrelationMatrix
+func correlationFromCovariance(cov Matrix) Matrix {
+	n := len(cov.Data)
+	stds := make([]float64, n)
+	for i := range stds {
+		stds[i] = math.Sqrt(cov.Data[i][i])
+	}
+
+	corr := NewZeroMatrix(n, n)
+	for i := range corr.Data {
+		for j := range corr.Data[i] {
+			switch {
+			case i == j:
+				corr.Data[i][j] = 1
+			case stds[i] == 0 || stds[j] == 0:
+				corr.Data[i][j] = 0
+			default:
+				// rounding can push a perfect correlation just past 1
+				corr.Data[i][j] = math.Max(-1, math.Min(1, cov.Data[i][j]/(stds[i]*stds[j])))
+			}
+		}
+	}
+
+	return corr
+}
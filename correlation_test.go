@@ -0,0 +1,131 @@
+package linearalgebra
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetCorrelationMatrix(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   [][]float64
+	}{
+		{
+			name:   "perfectly correlated",
+			matrix: [][]float64{{1, 10, -3}, {2, 20, -6}, {4, 40, -12}},
+			want:   [][]float64{{1, 1, -1}, {1, 1, -1}, {-1, -1, 1}},
+		},
+		{
+			name:   "uncorrelated",
+			matrix: [][]float64{{1, 1}, {-1, 1}, {1, -1}, {-1, -1}},
+			want:   [][]float64{{1, 0}, {0, 1}},
+		},
+		{
+			name:   "partly correlated",
+			matrix: [][]float64{{1, 2}, {2, 1}, {3, 4}, {4, 3}},
+			want:   [][]float64{{1, 0.6}, {0.6, 1}},
+		},
+		{
+			name:   "constant column",
+			matrix: [][]float64{{1, 5}, {2, 5}, {3, 5}},
+			want:   [][]float64{{1, 0}, {0, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMatrix(tt.matrix).GetCorrelationMatrix()
+			if !MatricesNearlyEqual(got.Data, tt.want, Tolerance{Absolute: 1e-12}) {
+				t.Errorf("GetCorrelationMatrix() = %v, want %v", got.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestStandardizeMatrix(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	standardized := StandardizeMatrix(m)
+
+	// z-scores have mean 0 and variance 1, and their covariance is the correlation
+	cov := standardized.GetCovarianceMatrix()
+	corr := m.GetCorrelationMatrix()
+	if !MatricesNearlyEqual(cov.Data, corr.Data, Tolerance{Absolute: 1e-12}) {
+		t.Errorf("cov(StandardizeMatrix(m)) != GetCorrelationMatrix(m)")
+	}
+	for j := 0; j < standardized.Cols(); j++ {
+		if mean := GetMean(standardized.GetColumn(j)); math.Abs(mean) > 1e-12 {
+			t.Errorf("column %d has mean %v, want 0", j, mean)
+		}
+		for i := range corr.Data {
+			if math.Abs(corr.Data[i][j]) > 1 {
+				t.Errorf("corr[%d][%d] = %v, want it in [-1, 1]", i, j, corr.Data[i][j])
+			}
+		}
+	}
+
+	// the correlation does not depend on the units
+	scaled := NewMatrix(m.Data)
+	for i := range scaled.Data {
+		scaled.Data[i][0] *= 1e6
+		scaled.Data[i][3] *= -1e-4
+	}
+	if !MatricesNearlyEqual(scaled.GetCorrelationMatrix().Data, flipSign(corr.Data, 3), Tolerance{Absolute: 1e-12}) {
+		t.Errorf("GetCorrelationMatrix() changed when the columns were rescaled")
+	}
+
+	constant := NewMatrix([][]float64{{1, 7}, {3, 7}})
+	constant.Standardize()
+	if want := [][]float64{{-math.Sqrt(0.5), 0}, {math.Sqrt(0.5), 0}}; !MatricesNearlyEqual(constant.Data, want, Tolerance{Absolute: 1e-12}) {
+		t.Errorf("Standardize() = %v, want %v", constant.Data, want)
+	}
+}
+
+// flipSign returns the correlation matrix after column k was multiplied by a negative number
+func flipSign(corr [][]float64, k int) [][]float64 {
+	res := CopyMatrix(corr)
+	for i := range res {
+		if i != k {
+			res[i][k] = -res[i][k]
+			res[k][i] = -res[k][i]
+		}
+	}
+	return res
+}
+
+func TestStandardizedPCA(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	raw, err := FitPCA(m, PCAOptions{})
+	if err != nil {
+		t.Fatalf("FitPCA() unexpected error: %v", err)
+	}
+	standardized, err := FitPCA(m, PCAOptions{Standardize: true})
+	if err != nil {
+		t.Fatalf("FitPCA() unexpected error: %v", err)
+	}
+
+	// the variances are the eigenvalues of the correlation matrix, which add up
+	// to the number of columns
+	values, _, err := EigenSym(m.GetCorrelationMatrix())
+	if err != nil {
+		t.Fatalf("EigenSym() unexpected error: %v", err)
+	}
+	for i, pc := range standardized.Components {
+		if math.Abs(pc.Variance-values[i]) > 1e-9 {
+			t.Errorf("component %d variance = %v, want eigenvalue %v", i, pc.Variance, values[i])
+		}
+	}
+	if cols := float64(m.Cols()); math.Abs(standardized.TotalVariance-cols) > 1e-9 {
+		t.Errorf("TotalVariance = %v, want %v", standardized.TotalVariance, cols)
+	}
+
+	// the large columns dominate the raw first component, error_rate (~0.07)
+	// plays no part in it
+	const errorRate = 15
+	if v := math.Abs(raw.Components[0].Vector[errorRate]); v > 0.01 {
+		t.Errorf("raw first component loads %v on error_rate, want about 0", v)
+	}
+	if raw.ExplainedVarianceRatio()[0] <= standardized.ExplainedVarianceRatio()[0] {
+		t.Errorf("raw first ratio %v <= standardized %v, want the raw one dominated by the large columns",
+			raw.ExplainedVarianceRatio()[0], standardized.ExplainedVarianceRatio()[0])
+	}
+}
@@ -0,0 +1,282 @@
+package linearalgebra
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// CovarianceAccumulator computes the column means and the covariance matrix of
+// a data set one row at a time, so the data never has to be in memory at once.
+// Add uses Welford's update, which keeps a running mean and the sum of outer
+// products of the deviations from it, instead of the sums of x and x^2, so
+// there is no cancellation when the means are large compared to the spread.
+// Merge combines the accumulators of separate shards with the pairwise update
+// of Chan, Golub and LeVeque, the result is the same as one pass over all rows.
+// The zero value is an empty accumulator, the first row sets the number of
+// features. An accumulator is not safe for concurrent use, give every
+// goroutine its own and Merge them at the end.
+type CovarianceAccumulator struct {
+	count int
+	mean  []float64
+	// comoment is the sum over all rows of (x - mean) * (x - mean)^T
+	comoment [][]float64
+}
+
+// Count returns the number of rows added so far
+func (a *CovarianceAccumulator) Count() int {
+	return a.count
+}
+
+// Features returns the number of columns, 0 before the first row
+func (a *CovarianceAccumulator) Features() int {
+	return len(a.mean)
+}
+
+// Add adds one row.
+// It returns an error if the row does not have as many entries as the rows
+// before it, the accumulator is unchanged then.
+func (a *CovarianceAccumulator) Add(row []float64) error {
+	if err := a.init("CovarianceAccumulator.Add", len(row)); err != nil {
+		return err
+	}
+
+	a.count++
+	n := float64(a.count)
+	delta := make([]float64, len(row))
+	for i := range row {
+		delta[i] = row[i] - a.mean[i]
+		a.mean[i] += delta[i] / n
+	}
+	// (x - oldMean) * (x - newMean)^T = (n - 1) / n * delta * delta^T,
+	// the symmetric form keeps the comoment exactly symmetric
+	a.addOuter(delta, (n-1)/n)
+
+	return nil
+}
+
+// AddMatrix adds every row of m, see Add
+func (a *CovarianceAccumulator) AddMatrix(m Matrix) error {
+	// a rectangular matrix fails on its first row or not at all
+	if err := checkRectangular("CovarianceAccumulator.AddMatrix", m.Data); err != nil {
+		return err
+	}
+
+	for _, row := range m.Data {
+		if err := a.Add(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddCSV reads CSV rows from reader one at a time and adds them, the file is
+// never loaded as a whole like NewMatrixFromReader does. With skipHeader the
+// first record is ignored.
+// It returns an error if a record cannot be parsed or has a different number
+// of fields, the rows before it stay added.
+func (a *CovarianceAccumulator) AddCSV(reader io.Reader, skipHeader bool) error {
+	const op = "CovarianceAccumulator.AddCSV"
+	csvreader := csv.NewReader(reader)
+	csvreader.ReuseRecord = true
+	if skipHeader {
+		if _, err := csvreader.Read(); err != nil && err != io.EOF {
+			return fmt.Errorf("%s: header: %w", op, err)
+		}
+	}
+
+	var row []float64
+	for i := 0; ; i++ {
+		record, err := csvreader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return fmt.Errorf("%s: row %d: %w: %v", op, i, ErrInvalidFormat, err)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: row %d: %w", op, i, err)
+		}
+
+		if len(row) != len(record) {
+			row = make([]float64, len(record))
+		}
+		for j, value := range record {
+			if row[j], err = strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("%s: row %d: %w: %v", op, i, ErrInvalidFormat, err)
+			}
+		}
+		if err := a.Add(row); err != nil {
+			return fmt.Errorf("%s: row %d: %w", op, i, err)
+		}
+	}
+}
+
+// Merge adds all rows that were added to other, as if they had been added to
+// a directly. other is not changed.
+// It returns an error if both have rows with a different number of features.
+func (a *CovarianceAccumulator) Merge(other *CovarianceAccumulator) error {
+	if other.count == 0 {
+		return nil
+	}
+	if err := a.init("CovarianceAccumulator.Merge", other.Features()); err != nil {
+		return err
+	}
+
+	na, nb := float64(a.count), float64(other.count)
+	n := na + nb
+	delta := make([]float64, len(a.mean))
+	for i := range delta {
+		delta[i] = other.mean[i] - a.mean[i]
+		a.mean[i] += delta[i] * nb / n
+	}
+	for i := range a.comoment {
+		for j := range a.comoment[i] {
+			a.comoment[i][j] += other.comoment[i][j]
+		}
+	}
+	a.addOuter(delta, na*nb/n)
+	a.count += other.count
+
+	return nil
+}
+
+// Mean returns the column means
+func (a *CovarianceAccumulator) Mean() []float64 {
+	res := make([]float64, len(a.mean))
+	copy(res, a.mean)
+
+	return res
+}
+
+// Covariance returns the sample covariance matrix, the same as
+// GetCovarianceMatrix of all the rows.
+// It returns an error if fewer than 2 rows were added.
+func (a *CovarianceAccumulator) Covariance() (Matrix, error) {
+	if a.count < 2 {
+		return Matrix{}, fmt.Errorf("CovarianceAccumulator.Covariance: %w: need at least 2 rows, got %d", ErrInvalidArgument, a.count)
+	}
+
+	cov := NewMatrix(a.comoment)
+	for i := range cov.Data {
+		for j := range cov.Data[i] {
+			cov.Data[i][j] /= float64(a.count - 1)
+		}
+	}
+
+	return cov, nil
+}
+
+// Correlation returns the correlation matrix, the same as GetCorrelationMatrix
+// of all the rows.
+// It returns an error if fewer than 2 rows were added.
+func (a *CovarianceAccumulator) Correlation() (Matrix, error) {
+	cov, err := a.Covariance()
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	return correlationFromCovariance(cov), nil
+}
+
+// FitPCA returns the principal components of the rows added so far, from the
+// eigendecomposition of the covariance matrix (of the standardized columns
+// with Standardize, the correlation matrix but with variance 0 for a constant
+// column). Adding more rows and calling FitPCA again updates the model
+// without going over the earlier rows, which makes it an incremental PCA for
+// data that does not fit in memory. The options are the ones of the FitPCA
+// function, except Randomized, which needs the rows themselves.
+// It returns an error if fewer than 2 rows were added or the options are invalid.
+func (a *CovarianceAccumulator) FitPCA(opts PCAOptions) (PCAModel, error) {
+	const op = "CovarianceAccumulator.FitPCA"
+	if a.count < 2 {
+		return PCAModel{}, fmt.Errorf("%s: %w: need at least 2 samples, got %d", op, ErrInvalidArgument, a.count)
+	}
+	if opts.Randomized != nil {
+		return PCAModel{}, fmt.Errorf("%s: %w: the randomized solver needs the data, not the covariance", op, ErrInvalidArgument)
+	}
+	if err := opts.validate(op, min(a.count, a.Features())); err != nil {
+		return PCAModel{}, err
+	}
+
+	cov, err := a.Covariance()
+	if err != nil {
+		return PCAModel{}, err
+	}
+	model := PCAModel{Means: a.Mean(), Samples: a.count}
+	if opts.Standardize {
+		// the covariance of the standardized columns, a constant column keeps
+		// its variance of 0
+		model.Stds = make([]float64, len(cov.Data))
+		for i := range model.Stds {
+			model.Stds[i] = standardizingScale(math.Sqrt(cov.Data[i][i]))
+		}
+		for i := range cov.Data {
+			for j := range cov.Data[i] {
+				cov.Data[i][j] /= model.Stds[i] * model.Stds[j]
+			}
+		}
+	}
+
+	values, vectors, err := EigenSym(cov)
+	if err != nil {
+		return PCAModel{}, err
+	}
+	variances := make([]float64, min(a.count, len(values)))
+	for i := range variances {
+		// rounding can leave the eigenvalues of a singular covariance just under 0
+		variances[i] = math.Max(values[i], 0)
+	}
+	for i := range cov.Data {
+		model.TotalVariance += cov.Data[i][i]
+	}
+
+	k := opts.componentCount(variances, model.TotalVariance)
+	model.Components = make([]PrincipalComponent, k)
+	for i := range model.Components {
+		vector := vectors.GetColumn(i)
+		if opts.Sign == SignLargestPositive && canonicalSignFlip(vector) {
+			for j := range vector {
+				vector[j] = -vector[j]
+			}
+		}
+		model.Components[i] = PrincipalComponent{Vector: vector, Variance: variances[i]}
+	}
+
+	return model, nil
+}
+
+// init allocates the mean and comoment for the given number of features on
+// the first row, and checks the number against the earlier rows after that
+func (a *CovarianceAccumulator) init(op string, features int) error {
+	if a.count > 0 || a.mean != nil {
+		if features != len(a.mean) {
+			return &ShapeError{
+				Op:     op,
+				Shapes: []Shape{{Rows: 1, Cols: features}, {Rows: a.count, Cols: len(a.mean)}},
+				Err:    ErrDimensionMismatch,
+			}
+		}
+		return nil
+	}
+
+	a.mean = make([]float64, features)
+	a.comoment = NewZeroMatrix(features, features).Data
+	return nil
+}
+
+// addOuter adds scale * delta * delta^T to the comoment
+func (a *CovarianceAccumulator) addOuter(delta []float64, scale float64) {
+	for i := range delta {
+		di := scale * delta[i]
+		for j := i; j < len(delta); j++ {
+			a.comoment[i][j] += di * delta[j]
+			a.comoment[j][i] = a.comoment[i][j]
+		}
+	}
+}
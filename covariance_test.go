@@ -0,0 +1,226 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCovarianceAccumulatorCSV(t *testing.T) {
+	file, err := os.Open("data/pca_dataset.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var acc CovarianceAccumulator
+	if err := acc.AddCSV(file, true); err != nil {
+		t.Fatalf("AddCSV() unexpected error: %v", err)
+	}
+
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	if acc.Count() != m.Rows() || acc.Features() != m.Cols() {
+		t.Fatalf("Count() = %d, Features() = %d, want %d and %d", acc.Count(), acc.Features(), m.Rows(), m.Cols())
+	}
+	means, _ := columnMeansAndStds(m.Data)
+	if !MatricesNearlyEqual([][]float64{acc.Mean()}, [][]float64{means}, Tolerance{Relative: 1e-12}) {
+		t.Errorf("Mean() = %v, want %v", acc.Mean(), means)
+	}
+	cov, err := acc.Covariance()
+	if err != nil {
+		t.Fatalf("Covariance() unexpected error: %v", err)
+	}
+	if !MatricesNearlyEqual(cov.Data, m.GetCovarianceMatrix().Data, Tolerance{Relative: 1e-12}) {
+		t.Errorf("Covariance() != GetCovarianceMatrix()")
+	}
+	corr, err := acc.Correlation()
+	if err != nil {
+		t.Fatalf("Correlation() unexpected error: %v", err)
+	}
+	if !MatricesNearlyEqual(corr.Data, m.GetCorrelationMatrix().Data, Tolerance{Absolute: 1e-12}) {
+		t.Errorf("Correlation() != GetCorrelationMatrix()")
+	}
+}
+
+func TestCovarianceAccumulatorMerge(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	var whole CovarianceAccumulator
+	if err := whole.AddMatrix(m); err != nil {
+		t.Fatalf("AddMatrix() unexpected error: %v", err)
+	}
+
+	// uneven shards, one of them empty, accumulated in parallel
+	bounds := []int{0, 1, 300, 300, 777, m.Rows()}
+	shards := make([]CovarianceAccumulator, len(bounds)-1)
+	var wg sync.WaitGroup
+	for s := range shards {
+		wg.Add(1)
+		go func(s int) {
+			defer wg.Done()
+			if err := shards[s].AddMatrix(m.Slice(bounds[s], bounds[s+1], 0, m.Cols())); err != nil {
+				t.Errorf("shard %d: AddMatrix() unexpected error: %v", s, err)
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	var merged CovarianceAccumulator
+	for s := range shards {
+		if err := merged.Merge(&shards[s]); err != nil {
+			t.Fatalf("Merge() unexpected error: %v", err)
+		}
+	}
+	if merged.Count() != whole.Count() {
+		t.Fatalf("Count() = %d, want %d", merged.Count(), whole.Count())
+	}
+	if !MatricesNearlyEqual([][]float64{merged.Mean()}, [][]float64{whole.Mean()}, Tolerance{Relative: 1e-12}) {
+		t.Errorf("merged Mean() = %v, want %v", merged.Mean(), whole.Mean())
+	}
+	got, _ := merged.Covariance()
+	want, _ := whole.Covariance()
+	if !MatricesNearlyEqual(got.Data, want.Data, Tolerance{Relative: 1e-12}) {
+		t.Errorf("merged Covariance() != Covariance() of one pass")
+	}
+	if shards[0].Count() != 1 {
+		t.Errorf("Merge() changed the merged accumulator, Count() = %d, want 1", shards[0].Count())
+	}
+}
+
+func TestCovarianceAccumulatorLargeMean(t *testing.T) {
+	// the spread is 1e-9 of the mean, the sum of squares formula loses all digits
+	rng := rand.New(rand.NewSource(1))
+	noise := randomMatrix(rng, 500, 3)
+	var acc CovarianceAccumulator
+	for _, row := range noise {
+		if err := acc.Add([]float64{1e9 + row[0], -1e9 + row[1], 1e9 + row[2]}); err != nil {
+			t.Fatalf("Add() unexpected error: %v", err)
+		}
+	}
+
+	got, err := acc.Covariance()
+	if err != nil {
+		t.Fatalf("Covariance() unexpected error: %v", err)
+	}
+	want := NewMatrix(noise).GetCovarianceMatrix()
+	if !MatricesNearlyEqual(got.Data, want.Data, Tolerance{Absolute: 1e-5}) {
+		t.Errorf("Covariance() = %v, want %v", got.Data, want.Data)
+	}
+}
+
+func TestCovarianceAccumulatorFitPCA(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	for _, standardize := range []bool{false, true} {
+		opts := PCAOptions{Components: 5, Standardize: standardize}
+		want, err := FitPCA(m, opts)
+		if err != nil {
+			t.Fatalf("FitPCA() unexpected error: %v", err)
+		}
+
+		// fit on the first half, then update with the second
+		var acc CovarianceAccumulator
+		if err := acc.AddMatrix(m.Slice(0, 500, 0, m.Cols())); err != nil {
+			t.Fatalf("AddMatrix() unexpected error: %v", err)
+		}
+		half, err := acc.FitPCA(opts)
+		if err != nil {
+			t.Fatalf("CovarianceAccumulator.FitPCA() unexpected error: %v", err)
+		}
+		if half.Samples != 500 {
+			t.Errorf("Samples = %d, want 500", half.Samples)
+		}
+		if err := acc.AddMatrix(m.Slice(500, m.Rows(), 0, m.Cols())); err != nil {
+			t.Fatalf("AddMatrix() unexpected error: %v", err)
+		}
+		got, err := acc.FitPCA(opts)
+		if err != nil {
+			t.Fatalf("CovarianceAccumulator.FitPCA() unexpected error: %v", err)
+		}
+
+		if got.Samples != want.Samples || math.Abs(got.TotalVariance-want.TotalVariance) > 1e-10*want.TotalVariance {
+			t.Errorf("standardize %v: Samples = %d, TotalVariance = %v, want %d and %v",
+				standardize, got.Samples, got.TotalVariance, want.Samples, want.TotalVariance)
+		}
+		if !MatricesNearlyEqual([][]float64{got.Means}, [][]float64{want.Means}, Tolerance{Relative: 1e-12}) {
+			t.Errorf("standardize %v: Means = %v, want %v", standardize, got.Means, want.Means)
+		}
+		if (got.Stds == nil) != (want.Stds == nil) ||
+			want.Stds != nil && !MatricesNearlyEqual([][]float64{got.Stds}, [][]float64{want.Stds}, Tolerance{Relative: 1e-12}) {
+			t.Errorf("standardize %v: Stds = %v, want %v", standardize, got.Stds, want.Stds)
+		}
+		for i, pc := range got.Components {
+			if math.Abs(pc.Variance-want.Components[i].Variance) > 1e-9*want.TotalVariance {
+				t.Errorf("standardize %v: component %d variance = %v, want %v", standardize, i, pc.Variance, want.Components[i].Variance)
+			}
+			if !MatricesNearlyEqual([][]float64{pc.Vector}, [][]float64{want.Components[i].Vector}, Tolerance{Absolute: 1e-8}) {
+				t.Errorf("standardize %v: component %d = %v, want %v", standardize, i, pc.Vector, want.Components[i].Vector)
+			}
+		}
+	}
+
+	// a constant column adds no variance when standardized
+	var acc CovarianceAccumulator
+	constant := NewMatrix([][]float64{{1, 1000, 5}, {2, 2000, 5}, {4, 4000, 5}, {7, 7000, 5}})
+	if err := acc.AddMatrix(constant); err != nil {
+		t.Fatalf("AddMatrix() unexpected error: %v", err)
+	}
+	got, err := acc.FitPCA(PCAOptions{Standardize: true})
+	if err != nil {
+		t.Fatalf("CovarianceAccumulator.FitPCA() unexpected error: %v", err)
+	}
+	want, _ := FitPCA(constant, PCAOptions{Standardize: true})
+	if math.Abs(got.TotalVariance-want.TotalVariance) > 1e-12 || math.Abs(got.Components[0].Variance-want.Components[0].Variance) > 1e-12 {
+		t.Errorf("TotalVariance = %v, first variance = %v, want %v and %v",
+			got.TotalVariance, got.Components[0].Variance, want.TotalVariance, want.Components[0].Variance)
+	}
+}
+
+func TestCovarianceAccumulatorErrors(t *testing.T) {
+	var acc CovarianceAccumulator
+	if _, err := acc.Covariance(); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Covariance() of no rows error = %v, want %v", err, ErrInvalidArgument)
+	}
+	if err := acc.Add([]float64{1, 2}); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+	if _, err := acc.FitPCA(PCAOptions{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("FitPCA() of 1 row error = %v, want %v", err, ErrInvalidArgument)
+	}
+	if err := acc.Add([]float64{1, 2, 3}); !errors.Is(err, ErrDimensionMismatch) || acc.Count() != 1 {
+		t.Errorf("Add() error = %v with Count() %d, want %v and 1", err, acc.Count(), ErrDimensionMismatch)
+	}
+	var other CovarianceAccumulator
+	if err := other.Add([]float64{1}); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+	if err := acc.Merge(&other); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Merge() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if err := acc.Add([]float64{3, 5}); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+	if _, err := acc.FitPCA(PCAOptions{Components: 1, Randomized: &RandomizedSVDOptions{}}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("FitPCA() with Randomized error = %v, want %v", err, ErrInvalidArgument)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "not a number", input: "a,b\n1,2\n3,x\n"},
+		{name: "field count", input: "a,b\n1,2\n3,4,5\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var acc CovarianceAccumulator
+			if err := acc.AddCSV(strings.NewReader(tt.input), true); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("AddCSV() error = %v, want %v", err, ErrInvalidFormat)
+			}
+			if acc.Count() != 1 {
+				t.Errorf("Count() = %d, want the 1 row before the error", acc.Count())
+			}
+		})
+	}
+}
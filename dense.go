@@ -0,0 +1,198 @@
+package linearalgebra
+
+// contiguousRows returns a rows x cols matrix whose rows are windows into one
+// backing array of length rows*cols, and the backing array itself.
+// The capacity of every row is its length, so appending to a row never
+// overwrites the next one.
+func contiguousRows[T any](rows, cols int) ([][]T, []T) {
+	data := make([]T, rows*cols)
+	res := make([][]T, rows)
+	for i := range res {
+		res[i] = data[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+
+	return res, data
+}
+
+// newContiguousMatrix returns a rows x cols matrix of 0s in flat storage
+func newContiguousMatrix(rows, cols int) Matrix {
+	res, data := contiguousRows[float64](rows, cols)
+	return Matrix{Data: res, data: data, rows: rows, cols: cols, stride: cols}
+}
+
+// isRectangular returns true if every row has the same length
+func isRectangular(matrix [][]float64) bool {
+	return checkRectangular("", matrix) == nil
+}
+
+// NewZeroMatrix returns a rows x cols matrix of 0s backed by a single []float64
+func NewZeroMatrix(rows, cols int) Matrix {
+	if rows < 0 || cols < 0 {
+		panic("illegal operation")
+	}
+
+	return newContiguousMatrix(rows, cols)
+}
+
+// NewMatrixFromFlat returns a rows x cols matrix that uses data as its backing
+// array in row major order, element (i, j) is data[i*cols+j]. data is not copied,
+// writes to the matrix are visible in data and the other way around.
+// It returns ErrDimensionMismatch if len(data) is not rows*cols.
+func NewMatrixFromFlat(rows, cols int, data []float64) (Matrix, error) {
+	if rows < 0 || cols < 0 || len(data) != rows*cols {
+		return Matrix{}, &ShapeError{
+			Op:     "NewMatrixFromFlat",
+			Shapes: []Shape{{Rows: rows, Cols: cols}, {Rows: 1, Cols: len(data)}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := make([][]float64, rows)
+	for i := range res {
+		res[i] = data[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+
+	return Matrix{Data: res, data: data, rows: rows, cols: cols, stride: cols}, nil
+}
+
+// Rows returns the number of rows
+func (m Matrix) Rows() int {
+	return len(m.Data)
+}
+
+// Cols returns the number of columns
+func (m Matrix) Cols() int {
+	if len(m.Data) == 0 {
+		return 0
+	}
+
+	return len(m.Data[0])
+}
+
+// Dims returns the number of rows and columns
+func (m Matrix) Dims() (int, int) {
+	return m.Rows(), m.Cols()
+}
+
+// MulVec returns A * x
+func (m Matrix) MulVec(x []float64) ([]float64, error) {
+	if len(x) != m.Cols() {
+		return nil, &ShapeError{
+			Op:     "Matrix.MulVec",
+			Shapes: []Shape{GetShape(m.Data), {Rows: len(x), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := make([]float64, m.Rows())
+	for i, row := range m.Data {
+		res[i] = dot(row, x)
+	}
+
+	return res, nil
+}
+
+// MulVecTrans returns A^T * x without transposing A
+func (m Matrix) MulVecTrans(x []float64) ([]float64, error) {
+	if len(x) != m.Rows() {
+		return nil, &ShapeError{
+			Op:     "Matrix.MulVecTrans",
+			Shapes: []Shape{GetShape(m.Data), {Rows: len(x), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := make([]float64, m.Cols())
+	for i, row := range m.Data {
+		axpy(x[i], row, res)
+	}
+
+	return res, nil
+}
+
+// At returns the element in row i and column j
+func (m Matrix) At(i, j int) float64 {
+	if i < 0 || i >= m.Rows() || j < 0 || j >= m.Cols() {
+		panic("index out of bounds")
+	}
+
+	return m.Data[i][j]
+}
+
+// isFlat returns true if the matrix has flat storage and every row of Data is
+// still the window of the backing array it was built with. A row that was
+// replaced, reordered or resized makes it false.
+func (m Matrix) isFlat() bool {
+	if m.data == nil || len(m.Data) != m.rows {
+		return false
+	}
+	if m.cols == 0 {
+		return true
+	}
+
+	for i, row := range m.Data {
+		if len(row) != m.cols || &row[0] != &m.data[i*m.stride] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsContiguous returns true if the matrix is stored in a single []float64
+// with no gaps between the rows, so Flat returns it without copying
+func (m Matrix) IsContiguous() bool {
+	return m.isFlat() && m.stride == m.cols
+}
+
+// Flat returns the elements in row major order. For a contiguous matrix it is
+// the backing array itself, otherwise it is a copy.
+func (m Matrix) Flat() []float64 {
+	if m.IsContiguous() {
+		return m.data[: m.rows*m.cols : m.rows*m.cols]
+	}
+
+	rows, cols := m.Rows(), m.Cols()
+	res := make([]float64, 0, rows*cols)
+	for i := range m.Data {
+		res = append(res, m.Data[i]...)
+	}
+
+	return res
+}
+
+// ToSlices returns a copy of the matrix as a [][]float64 that shares no memory with it
+func (m Matrix) ToSlices() [][]float64 {
+	return CopyMatrix(m.Data)
+}
+
+// Slice returns a view of the rows [r0, r1) and the columns [c0, c1).
+// No elements are copied, writes to the view are visible in the matrix.
+func (m Matrix) Slice(r0, r1, c0, c1 int) Matrix {
+	if r0 < 0 || r1 < r0 || r1 > m.Rows() || c0 < 0 || c1 < c0 || c1 > m.Cols() {
+		panic("index out of bounds")
+	}
+
+	res := make([][]float64, r1-r0)
+	for i := range res {
+		res[i] = m.Data[r0+i][c0:c1:c1]
+	}
+
+	view := Matrix{Data: res}
+	if m.isFlat() && len(res) > 0 && c1 > c0 {
+		view.data = m.data[r0*m.stride+c0:]
+		view.rows, view.cols, view.stride = r1-r0, c1-c0, m.stride
+	}
+
+	return view
+}
+
+// RowView returns a 1 x n view of row i
+func (m Matrix) RowView(i int) Matrix {
+	return m.Slice(i, i+1, 0, m.Cols())
+}
+
+// ColView returns an m x 1 view of column j
+func (m Matrix) ColView(j int) Matrix {
+	return m.Slice(0, m.Rows(), j, j+1)
+}
@@ -0,0 +1,217 @@
+package linearalgebra
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewMatrixFromFlat(t *testing.T) {
+	data := []float64{1, 2, 3, 4, 5, 6}
+	m, err := NewMatrixFromFlat(2, 3, data)
+	if err != nil {
+		t.Fatalf("NewMatrixFromFlat() unexpected error: %v", err)
+	}
+
+	want := [][]float64{{1, 2, 3}, {4, 5, 6}}
+	if !areMatricesEqual(m.Data, want) {
+		t.Errorf("NewMatrixFromFlat() = %v, want %v", m.Data, want)
+	}
+	if m.Rows() != 2 || m.Cols() != 3 {
+		t.Errorf("NewMatrixFromFlat() is %dx%d, want 2x3", m.Rows(), m.Cols())
+	}
+
+	// the matrix and the slice share memory
+	m.SetIndex(1, 0, 40)
+	if data[3] != 40 {
+		t.Errorf("write to the matrix not visible in data: %v", data)
+	}
+	data[5] = 60
+	if m.At(1, 2) != 60 {
+		t.Errorf("write to data not visible in the matrix: %v", m.Data)
+	}
+	if !m.IsContiguous() || &m.Flat()[0] != &data[0] {
+		t.Errorf("Flat() of a contiguous matrix should return the backing array")
+	}
+
+	if _, err := NewMatrixFromFlat(2, 2, data); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("NewMatrixFromFlat() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestNewMatrixCopiesIntoFlatStorage(t *testing.T) {
+	data := [][]float64{{1, 2}, {3, 4}, {5, 6}}
+	m := NewMatrix(data)
+	if !m.IsContiguous() {
+		t.Fatalf("NewMatrix() is not contiguous")
+	}
+	if got := m.Flat(); !areMatricesEqual([][]float64{got}, [][]float64{{1, 2, 3, 4, 5, 6}}) {
+		t.Errorf("Flat() = %v, want [1 2 3 4 5 6]", got)
+	}
+
+	m.Data[0][0] = 10
+	if data[0][0] != 1 {
+		t.Errorf("NewMatrix() shares memory with its input")
+	}
+
+	out := m.ToSlices()
+	out[1][1] = 40
+	if m.At(1, 1) != 4 {
+		t.Errorf("ToSlices() shares memory with the matrix")
+	}
+
+	// appending to a row must not overwrite the next one
+	_ = append(m.Data[0], 99)
+	if m.At(1, 0) != 3 {
+		t.Errorf("append to row 0 overwrote row 1: %v", m.Data)
+	}
+
+	jagged := NewMatrix([][]float64{{1}, {2, 3}})
+	if jagged.IsContiguous() || !areMatricesEqual([][]float64{jagged.Flat()}, [][]float64{{1, 2, 3}}) {
+		t.Errorf("NewMatrix() of a jagged matrix = %v", jagged.Data)
+	}
+}
+
+func TestMatrix_Views(t *testing.T) {
+	m := NewMatrix([][]float64{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	})
+
+	tests := []struct {
+		name string
+		view Matrix
+		want [][]float64
+		flat bool
+	}{
+		{name: "submatrix", view: m.Slice(1, 3, 1, 3), want: [][]float64{{6, 7}, {10, 11}}},
+		{name: "full width rows", view: m.Slice(1, 3, 0, 4), want: [][]float64{{5, 6, 7, 8}, {9, 10, 11, 12}}, flat: true},
+		{name: "row", view: m.RowView(2), want: [][]float64{{9, 10, 11, 12}}, flat: true},
+		{name: "column", view: m.ColView(1), want: [][]float64{{2}, {6}, {10}}},
+		{name: "empty", view: m.Slice(1, 1, 0, 4), want: [][]float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !areMatricesEqual(tt.view.Data, tt.want) {
+				t.Errorf("view = %v, want %v", tt.view.Data, tt.want)
+			}
+			if tt.view.IsContiguous() != tt.flat {
+				t.Errorf("IsContiguous() = %v, want %v", tt.view.IsContiguous(), tt.flat)
+			}
+			var flat []float64
+			for _, row := range tt.want {
+				flat = append(flat, row...)
+			}
+			got := tt.view.Flat()
+			if len(got) != len(flat) {
+				t.Fatalf("Flat() = %v, want %v", got, flat)
+			}
+			for i := range got {
+				if got[i] != flat[i] {
+					t.Fatalf("Flat() = %v, want %v", got, flat)
+				}
+			}
+		})
+	}
+
+	// writes go through to the matrix and to the other views
+	sub := m.Slice(1, 3, 1, 3)
+	col := m.ColView(2)
+	sub.SetIndex(0, 1, 70)
+	if m.At(1, 2) != 70 || col.At(1, 0) != 70 {
+		t.Errorf("write to a view not visible: matrix %v, column %v", m.Data, col.Data)
+	}
+
+	// a view of a view
+	inner := sub.ColView(0)
+	inner.SetIndex(1, 0, 100)
+	if m.At(2, 1) != 100 {
+		t.Errorf("write to a nested view not visible: %v", m.Data)
+	}
+}
+
+func TestMatrix_ViewsOfLiteral(t *testing.T) {
+	// a literal keeps its own rows, views still alias them
+	m := Matrix{Data: [][]float64{{1, 2}, {3, 4}}}
+	if m.IsContiguous() {
+		t.Errorf("IsContiguous() of a literal = true")
+	}
+	col := m.ColView(1)
+	col.SetIndex(0, 0, 20)
+	if m.Data[0][1] != 20 {
+		t.Errorf("write to a view of a literal not visible: %v", m.Data)
+	}
+}
+
+func TestMatrix_FlatAfterSwapRows(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2}, {3, 4}})
+	SwapRows(m.Data, 0, 1)
+	if m.IsContiguous() {
+		t.Errorf("IsContiguous() = true after the row slices were swapped")
+	}
+
+	got := m.Flat()
+	want := []float64{3, 4, 1, 2}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Flat() = %v, want %v", got, want)
+		}
+	}
+	if view := m.RowView(0); view.At(0, 0) != 3 {
+		t.Errorf("RowView(0) = %v, want [[3 4]]", view.Data)
+	}
+}
+
+func TestMatrix_FlatAfterReplacingData(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2}, {3, 4}})
+	m.Data = [][]float64{{5, 6}, {7, 8}}
+	if m.IsContiguous() {
+		t.Errorf("IsContiguous() = true after Data was replaced")
+	}
+	if got := m.Flat(); got[0] != 5 || got[3] != 8 {
+		t.Errorf("Flat() = %v, want [5 6 7 8]", got)
+	}
+
+	m = NewMatrix([][]float64{{1, 2}, {3, 4}})
+	m.Data[1] = []float64{7, 8}
+	if m.IsContiguous() {
+		t.Errorf("IsContiguous() = true after row 1 was replaced")
+	}
+	if got := m.Flat(); got[0] != 1 || got[2] != 7 {
+		t.Errorf("Flat() = %v, want [1 2 7 8]", got)
+	}
+	if view := m.Slice(1, 2, 0, 2); view.At(0, 0) != 7 {
+		t.Errorf("Slice() of a replaced row = %v, want [[7 8]]", view.Data)
+	}
+
+	m = NewMatrix([][]float64{{1, 2}, {3, 4}})
+	m.Data = append(m.Data, []float64{5, 6})
+	if m.IsContiguous() || len(m.Flat()) != 6 {
+		t.Errorf("IsContiguous() = %v, Flat() = %v after a row was appended", m.IsContiguous(), m.Flat())
+	}
+}
+
+func TestNewZeroMatrix(t *testing.T) {
+	m := NewZeroMatrix(2, 3)
+	if !areMatricesEqual(m.Data, [][]float64{{0, 0, 0}, {0, 0, 0}}) {
+		t.Errorf("NewZeroMatrix() = %v", m.Data)
+	}
+	if !m.IsContiguous() {
+		t.Errorf("NewZeroMatrix() is not contiguous")
+	}
+}
+
+func BenchmarkCopyMatrix(b *testing.B) {
+	matrix := make([][]float64, 256)
+	for i := range matrix {
+		matrix[i] = make([]float64, 256)
+		for j := range matrix[i] {
+			matrix[i][j] = float64(i*256 + j)
+		}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		CopyMatrix(matrix)
+	}
+}
@@ -0,0 +1,144 @@
+package linearalgebra
+
+import (
+	"math"
+	"sort"
+)
+
+// symmetryTolerance is the largest |A[i][j] - A[j][i]|, relative to the largest
+// entry of A, for which EigenSym still treats A as symmetric
+const symmetryTolerance = 1e-10
+
+// IsMatrixSymmetric returns true if the matrix is square and A[i][j] == A[j][i]
+// up to a tolerance relative to the largest entry of the matrix, so A and
+// 1e-20 * A give the same answer. A matrix of 0s is symmetric.
+func IsMatrixSymmetric(matrix [][]float64) bool {
+	if !IsMatrixSquare(matrix) {
+		return false
+	}
+
+	tol := symmetryTolerance * maxAbsEntry(matrix)
+	for i := range matrix {
+		for j := i + 1; j < len(matrix); j++ {
+			if math.Abs(matrix[i][j]-matrix[j][i]) > tol {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// EigenSym returns the eigenvalues and eigenvectors of a symmetric matrix,
+// such as the output of GetCovarianceMatrix.
+// The eigenvalues of a symmetric matrix are always real, they are returned in
+// descending order. The eigenvectors are the columns of the returned matrix,
+// column i belongs to eigenvalue i and the columns are orthonormal, also for
+// repeated eigenvalues.
+// It uses the cyclic Jacobi method: every off diagonal entry is zeroed in turn
+// by a plane rotation until the matrix is diagonal, the product of the rotations
+// is the eigenvector matrix.
+// It returns ErrNotSquare or ErrNotSymmetric if the matrix is not symmetric and
+// a ConvergenceError if it is not diagonal after jacobiSweeps sweeps.
+func EigenSym(m Matrix) ([]float64, Matrix, error) {
+	if err := checkSquare("EigenSym", m.Data); err != nil {
+		return nil, Matrix{}, err
+	}
+	if !IsMatrixSymmetric(m.Data) {
+		return nil, Matrix{}, newShapeError("EigenSym", ErrNotSymmetric, m.Data)
+	}
+
+	n := len(m.Data)
+	// average with the transpose to remove the rounding noise below the tolerance
+	A := make([][]float64, n)
+	for i := range A {
+		A[i] = make([]float64, n)
+		for j := range A[i] {
+			A[i][j] = (m.Data[i][j] + m.Data[j][i]) / 2
+		}
+	}
+	V := GenerateIdentityMatrix(n)
+
+	for sweep := 0; ; sweep++ {
+		offDiagonal, total := 0.0, 0.0
+		for i := range A {
+			for j := range A[i] {
+				total += A[i][j] * A[i][j]
+				if i != j {
+					offDiagonal += A[i][j] * A[i][j]
+				}
+			}
+		}
+		if offDiagonal <= machineEpsilon*machineEpsilon*total {
+			break
+		}
+		if sweep == jacobiSweeps {
+			return nil, Matrix{}, &ConvergenceError{Op: "EigenSym", Iterations: sweep}
+		}
+
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if A[p][q] == 0 {
+					continue
+				}
+				jacobiRotate(A, V, p, q)
+			}
+		}
+	}
+
+	eigenvalues := make([]float64, n)
+	for i := range eigenvalues {
+		eigenvalues[i] = A[i][i]
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return eigenvalues[order[i]] > eigenvalues[order[j]]
+	})
+
+	sortedValues := make([]float64, n)
+	vectors := make([][]float64, n)
+	for i := range vectors {
+		vectors[i] = make([]float64, n)
+	}
+	for newIndex, oldIndex := range order {
+		sortedValues[newIndex] = eigenvalues[oldIndex]
+		for i := 0; i < n; i++ {
+			vectors[i][newIndex] = V[i][oldIndex]
+		}
+	}
+
+	return sortedValues, Matrix{Data: vectors}, nil
+}
+
+// jacobiRotate applies the rotation J in the (p, q) plane that zeroes A[p][q],
+// A becomes J^T * A * J and V becomes V * J
+func jacobiRotate(A, V [][]float64, p, q int) {
+	n := len(A)
+	theta := (A[q][q] - A[p][p]) / (2 * A[p][q])
+	t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+	if theta < 0 {
+		t = -t
+	}
+	c := 1 / math.Sqrt(t*t+1)
+	s := t * c
+
+	for k := 0; k < n; k++ {
+		akp, akq := A[k][p], A[k][q]
+		A[k][p] = c*akp - s*akq
+		A[k][q] = s*akp + c*akq
+	}
+	for k := 0; k < n; k++ {
+		apk, aqk := A[p][k], A[q][k]
+		A[p][k] = c*apk - s*aqk
+		A[q][k] = s*apk + c*aqk
+	}
+	for k := 0; k < n; k++ {
+		vkp, vkq := V[k][p], V[k][q]
+		V[k][p] = c*vkp - s*vkq
+		V[k][q] = s*vkp + c*vkq
+	}
+}
@@ -0,0 +1,133 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIsMatrixSymmetric(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   bool
+	}{
+		{name: "symmetric", matrix: [][]float64{{2, 1}, {1, 3}}, want: true},
+		{name: "not symmetric", matrix: [][]float64{{2, 1}, {0, 3}}, want: false},
+		{name: "not square", matrix: [][]float64{{1, 2, 3}, {2, 1, 3}}, want: false},
+		{name: "rounding noise", matrix: [][]float64{{1e6, 0.1}, {0.1 + 1e-12, 1}}, want: true},
+		{name: "small entries", matrix: [][]float64{{0, 1e-12}, {0, 0}}, want: false},
+		{name: "small symmetric", matrix: [][]float64{{1e-20, 3e-20}, {3e-20, 2e-20}}, want: true},
+		{name: "zero matrix", matrix: [][]float64{{0, 0}, {0, 0}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMatrixSymmetric(tt.matrix); got != tt.want {
+				t.Errorf("IsMatrixSymmetric() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEigenSym(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   []float64
+	}{
+		{
+			name:   "2x2",
+			matrix: [][]float64{{3, 1}, {1, 3}},
+			want:   []float64{4, 2},
+		},
+		{
+			name:   "3x3",
+			matrix: [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}},
+			want:   []float64{2 + math.Sqrt2, 2, 2 - math.Sqrt2},
+		},
+		{
+			name:   "negative eigenvalues",
+			matrix: [][]float64{{0, 2}, {2, 0}},
+			want:   []float64{2, -2},
+		},
+		{
+			name:   "identity has a repeated eigenvalue",
+			matrix: GenerateIdentityMatrix(3),
+			want:   []float64{1, 1, 1},
+		},
+		{
+			// J - I where J is all ones has eigenvalues 3, -1, -1, -1
+			name:   "repeated eigenvalue with a full eigenspace",
+			matrix: [][]float64{{0, 1, 1, 1}, {1, 0, 1, 1}, {1, 1, 0, 1}, {1, 1, 1, 0}},
+			want:   []float64{3, -1, -1, -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, vectors, err := EigenSym(Matrix{Data: tt.matrix})
+			if err != nil {
+				t.Fatalf("EigenSym() unexpected error: %v", err)
+			}
+			for i := range tt.want {
+				if math.Abs(values[i]-tt.want[i]) > 1e-10 {
+					t.Fatalf("EigenSym() eigenvalues = %v, want %v", values, tt.want)
+				}
+			}
+
+			// A * V = V * diag(values)
+			av := MultiplyMatrices(tt.matrix, vectors.Data)
+			for i := range av {
+				for j := range av[i] {
+					if math.Abs(av[i][j]-vectors.Data[i][j]*values[j]) > 1e-10 {
+						t.Fatalf("A * V = %v, want V * diag(%v)", av, values)
+					}
+				}
+			}
+
+			vtv := MultiplyMatrices(TransposeMatrix(vectors.Data), vectors.Data)
+			identity := GenerateIdentityMatrix(len(tt.matrix))
+			for i := range vtv {
+				for j := range vtv[i] {
+					if math.Abs(vtv[i][j]-identity[i][j]) > 1e-12 {
+						t.Fatalf("V^T * V = %v, want identity", vtv)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEigenSymCovarianceMatchesPCA(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	values, _, err := EigenSym(m.GetCovarianceMatrix())
+	if err != nil {
+		t.Fatalf("EigenSym() unexpected error: %v", err)
+	}
+
+	pcs := PCA(m)
+	for i := range pcs {
+		if math.Abs(values[i]-pcs[i].Variance) > 1e-8*math.Max(1, pcs[i].Variance) {
+			t.Errorf("EigenSym() eigenvalue %d = %v, PCA variance = %v", i, values[i], pcs[i].Variance)
+		}
+	}
+}
+
+func TestEigenSymErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  [][]float64
+		wantErr error
+	}{
+		{name: "not square", matrix: [][]float64{{1, 2, 3}, {4, 5, 6}}, wantErr: ErrNotSquare},
+		{name: "not symmetric", matrix: [][]float64{{1, 2}, {3, 4}}, wantErr: ErrNotSymmetric},
+		{name: "NaN", matrix: [][]float64{{1, math.NaN()}, {math.NaN(), 4}}, wantErr: ErrNoConvergence},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := EigenSym(Matrix{Data: tt.matrix})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("EigenSym() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math"
+)
+
+// EliminationCertificate records how Gauss-Jordan elimination reduces A:
+// E * P * A = R
+// where R = ToRowReducedEchelonForm(A), P is the permutation matrix of the row
+// swaps done for pivoting and E is invertible and holds the scalings and row
+// additions. Like P * A = L * U, it moves all the swaps in front, so E is the
+// elimination of P * A without any swaps.
+// For an invertible A, R is the identity and E * P is A^-1.
+type EliminationCertificate struct {
+	// A is a copy of the reduced matrix
+	A Matrix
+	// E is the invertible elimination matrix
+	E Matrix
+	// P is the permutation matrix, row i of P * A is row Pivots[i] of A
+	P Matrix
+	// R is the reduced row echelon form of A
+	R Matrix
+	// Pivots[i] is the row of A that ends up in row i of P * A
+	Pivots []int
+}
+
+// NewEliminationCertificate runs ToRowReducedEchelonForm on the augmented
+// matrix [A | I]. The right block ends up as the product of all the row
+// operations, E * P, from which the permutation is split off.
+// It returns an error if the rows of A have different lengths.
+func NewEliminationCertificate(m Matrix) (EliminationCertificate, error) {
+	if err := checkRectangular("NewEliminationCertificate", m.Data); err != nil {
+		return EliminationCertificate{}, err
+	}
+
+	shape := GetShape(m.Data)
+	augmented := NewZeroMatrix(shape.Rows, shape.Cols+shape.Rows)
+	for i := range m.Data {
+		copy(augmented.Data[i], m.Data[i])
+		augmented.Data[i][shape.Cols+i] = 1
+	}
+	pivots, _ := gaussJordan(augmented.Data, shape.Cols, rrefTolerance.Absolute)
+
+	R := NewZeroMatrix(shape.Rows, shape.Cols)
+	P := NewZeroMatrix(shape.Rows, shape.Rows)
+	E := NewZeroMatrix(shape.Rows, shape.Rows)
+	for i, row := range augmented.Data {
+		copy(R.Data[i], row[:shape.Cols])
+		P.Data[i][pivots[i]] = 1
+		// E = (E * P) * P^T, column pivots[j] of E * P is column j of E
+		for j := range pivots {
+			E.Data[i][j] = row[shape.Cols+pivots[j]]
+		}
+	}
+
+	return EliminationCertificate{A: NewMatrix(m.Data), E: E, P: P, R: R, Pivots: pivots}, nil
+}
+
+// Verify checks the certificate: P is a permutation matrix, E is invertible,
+// R is in reduced row echelon form and E * P * A equals R up to rounding.
+// It returns nil if all of them hold and an error wrapping ErrVerificationFailed
+// naming the first one that does not.
+func (c EliminationCertificate) Verify() error {
+	n := c.A.Rows()
+	if c.E.Rows() != n || c.E.Cols() != n || c.P.Rows() != n || c.P.Cols() != n ||
+		c.R.Rows() != n || c.R.Cols() != c.A.Cols() {
+		return newShapeError("EliminationCertificate.Verify", ErrDimensionMismatch, c.A.Data, c.E.Data, c.P.Data, c.R.Data)
+	}
+
+	for i := range c.P.Data {
+		ones, rowSum, colSum := 0, 0.0, 0.0
+		for j := range c.P.Data[i] {
+			rowSum += c.P.Data[i][j]
+			colSum += c.P.Data[j][i]
+			if c.P.Data[i][j] == 1 {
+				ones++
+			} else if c.P.Data[i][j] != 0 {
+				ones = -1
+				break
+			}
+		}
+		if ones != 1 || rowSum != 1 || colSum != 1 {
+			return fmt.Errorf("EliminationCertificate.Verify: %w: P is not a permutation matrix", ErrVerificationFailed)
+		}
+	}
+
+	if n > 0 {
+		lu, err := NewLU(c.E)
+		if err != nil {
+			return err
+		}
+		if lu.IsSingular() {
+			return fmt.Errorf("EliminationCertificate.Verify: %w: E is singular", ErrVerificationFailed)
+		}
+	}
+
+	if !isReducedRowEchelon(c.R.Data) {
+		return fmt.Errorf("EliminationCertificate.Verify: %w: R is not in reduced row echelon form", ErrVerificationFailed)
+	}
+
+	if n > 0 && c.A.Cols() > 0 {
+		EPA := MultiplyMatrices(MultiplyMatrices(c.E.Data, c.P.Data), c.A.Data)
+		// the rounding of the elimination grows with the size of E and A
+		tol := 1e-9 * math.Max(1, maxAbsEntry(c.E.Data)*maxAbsEntry(c.A.Data))
+		for i := range EPA {
+			for j := range EPA[i] {
+				if d := math.Abs(EPA[i][j] - c.R.Data[i][j]); d > tol {
+					return fmt.Errorf("EliminationCertificate.Verify: %w: (E*P*A)[%d][%d] = %v, R[%d][%d] = %v",
+						ErrVerificationFailed, i, j, EPA[i][j], i, j, c.R.Data[i][j])
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isReducedRowEchelon reports whether every nonzero row starts with a 1 to the
+// right of the leading 1 of the row above, the zero rows are at the bottom and
+// the column of every leading 1 is 0 elsewhere. Unlike IsReducedRowEchelonForm
+// it only checks the columns of the leading entries, so a free column with
+// several nonzero entries is accepted.
+func isReducedRowEchelon(matrix [][]float64) bool {
+	lastPivot := -1
+	for i, row := range matrix {
+		pivot := -1
+		for j, v := range row {
+			if v != 0 {
+				pivot = j
+				break
+			}
+		}
+		if pivot == -1 {
+			lastPivot = len(row)
+			continue
+		}
+		if pivot <= lastPivot || row[pivot] != 1 {
+			return false
+		}
+		for z := range matrix {
+			if z != i && matrix[z][pivot] != 0 {
+				return false
+			}
+		}
+		lastPivot = pivot
+	}
+
+	return true
+}
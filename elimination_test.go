@@ -0,0 +1,104 @@
+package linearalgebra
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewEliminationCertificate(t *testing.T) {
+	tests := []struct {
+		name     string
+		matrix   [][]float64
+		identity bool
+	}{
+		{name: "zero matrix", matrix: [][]float64{{0, 0}, {0, 0}}, identity: true},
+		{name: "needs swaps", matrix: [][]float64{{0, 1, 2}, {1, 0, 3}, {4, -3, 8}}},
+		{name: "rank deficient", matrix: [][]float64{{1, 2, 3}, {2, 4, 6}, {1, 0, 1}}},
+		{name: "wide", matrix: [][]float64{{2, 4, 1, 0}, {1, 2, 0, 1}}},
+		{name: "tall", matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}}},
+		// 1e-17 is rounding noise, not a pivot
+		{name: "tiny entry", matrix: [][]float64{{1e-17, 1}, {0, 0}}},
+		{name: "1x1", matrix: [][]float64{{5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := CopyMatrix(tt.matrix)
+			certificate, err := NewEliminationCertificate(Matrix{Data: tt.matrix})
+			if err != nil {
+				t.Fatalf("NewEliminationCertificate() unexpected error: %v", err)
+			}
+			if err := certificate.Verify(); err != nil {
+				t.Errorf("Verify() unexpected error: %v", err)
+			}
+			if want := ToRowReducedEchelonForm(tt.matrix); !areMatricesEqual(certificate.R.Data, want) {
+				t.Errorf("R = %v, want %v", certificate.R.Data, want)
+			}
+			EPA := MultiplyMatrices(MultiplyMatrices(certificate.E.Data, certificate.P.Data), tt.matrix)
+			if !areMatricesEqual(EPA, certificate.R.Data) {
+				t.Errorf("E*P*A = %v, want R = %v", EPA, certificate.R.Data)
+			}
+			if !areMatricesEqual(tt.matrix, original) {
+				t.Errorf("NewEliminationCertificate() modified its input: %v", tt.matrix)
+			}
+			if identity := GenerateIdentityMatrix(len(tt.matrix)); tt.identity && !areMatricesEqual(GetEliminationMatrix(tt.matrix), identity) {
+				t.Errorf("GetEliminationMatrix() = %v, want %v", GetEliminationMatrix(tt.matrix), identity)
+			}
+		})
+	}
+}
+
+func TestEliminationCertificateSwaps(t *testing.T) {
+	A := [][]float64{{0, 2}, {3, 1}}
+	certificate, err := NewEliminationCertificate(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NewEliminationCertificate() unexpected error: %v", err)
+	}
+	if want := [][]float64{{0, 1}, {1, 0}}; !areMatricesEqual(certificate.P.Data, want) {
+		t.Errorf("P = %v, want %v", certificate.P.Data, want)
+	}
+	if certificate.Pivots[0] != 1 || certificate.Pivots[1] != 0 {
+		t.Errorf("Pivots = %v, want [1 0]", certificate.Pivots)
+	}
+	// without swaps left, E reduces P*A = [[3 1] [0 2]] by scaling and adding rows: E is upper triangular
+	if certificate.E.Data[1][0] != 0 {
+		t.Errorf("E = %v, want upper triangular", certificate.E.Data)
+	}
+	inverse, err := TryGetInverseMatrixByDeterminant(A)
+	if err != nil {
+		t.Fatalf("TryGetInverseMatrixByDeterminant() unexpected error: %v", err)
+	}
+	if got := GetEliminationMatrix(A); !areMatricesEqual(got, inverse) {
+		t.Errorf("GetEliminationMatrix() = %v, want A^-1 = %v", got, inverse)
+	}
+}
+
+func TestEliminationCertificateVerifyFails(t *testing.T) {
+	A := [][]float64{{1, 2}, {3, 4}}
+	tests := []struct {
+		name   string
+		tamper func(c *EliminationCertificate)
+		want   error
+	}{
+		{name: "wrong E", tamper: func(c *EliminationCertificate) { c.E.Data[0][0] += 1 }, want: ErrVerificationFailed},
+		{name: "singular E", tamper: func(c *EliminationCertificate) { c.E = NewZeroMatrix(2, 2) }, want: ErrVerificationFailed},
+		{name: "P not a permutation", tamper: func(c *EliminationCertificate) { c.P = NewMatrix([][]float64{{1, 1}, {0, 0}}) }, want: ErrVerificationFailed},
+		{name: "R not reduced", tamper: func(c *EliminationCertificate) { c.R.Data[0][1] = 5 }, want: ErrVerificationFailed},
+		{name: "wrong shape", tamper: func(c *EliminationCertificate) { c.R = NewZeroMatrix(3, 2) }, want: ErrDimensionMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificate, err := NewEliminationCertificate(NewMatrix(A))
+			if err != nil {
+				t.Fatalf("NewEliminationCertificate() unexpected error: %v", err)
+			}
+			tt.tamper(&certificate)
+			if err := certificate.Verify(); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewEliminationCertificate(Matrix{Data: [][]float64{{1, 2}, {3}}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("NewEliminationCertificate() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
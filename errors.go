@@ -0,0 +1,381 @@
+package linearalgebra
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Sentinel errors returned by the Try* variants of the matrix operations.
+// Use errors.Is to check for them, and errors.As with *ShapeError or
+// *IndexError to get the shapes or index that caused the failure.
+var (
+	ErrDimensionMismatch   = errors.New("dimension mismatch")
+	ErrSingular            = errors.New("matrix is singular")
+	ErrNotSquare           = errors.New("matrix is not square")
+	ErrIndexOutOfRange     = errors.New("index out of range")
+	ErrNotSymmetric        = errors.New("matrix is not symmetric")
+	ErrNoConvergence       = errors.New("algorithm did not converge")
+	ErrNotPositiveDefinite = errors.New("matrix is not positive definite")
+	ErrInvalidFormat       = errors.New("invalid format")
+	ErrInvalidArgument     = errors.New("invalid argument")
+	ErrVerificationFailed  = errors.New("verification failed")
+)
+
+// Shape is the number of rows and columns of a matrix
+type Shape struct {
+	Rows int
+	Cols int
+}
+
+func (s Shape) String() string {
+	return fmt.Sprintf("%dx%d", s.Rows, s.Cols)
+}
+
+// GetShape returns the shape of a matrix, a matrix with no rows is 0x0
+func GetShape(matrix [][]float64) Shape {
+	if len(matrix) == 0 {
+		return Shape{}
+	}
+
+	return Shape{Rows: len(matrix), Cols: len(matrix[0])}
+}
+
+// ShapeError is returned when the shapes of the operands do not allow an operation.
+// Err is one of ErrDimensionMismatch, ErrNotSquare, ErrSingular, ErrNotSymmetric
+// or ErrNotPositiveDefinite.
+type ShapeError struct {
+	Op     string
+	Shapes []Shape
+	Err    error
+}
+
+func (e *ShapeError) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Op, e.Err)
+	for i, s := range e.Shapes {
+		if i == 0 {
+			msg += " ("
+		} else {
+			msg += ", "
+		}
+		msg += s.String()
+	}
+	if len(e.Shapes) > 0 {
+		msg += ")"
+	}
+
+	return msg
+}
+
+func (e *ShapeError) Unwrap() error {
+	return e.Err
+}
+
+// IndexError is returned when a row or column index is outside of the matrix
+type IndexError struct {
+	Op    string
+	Index int
+	Len   int
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("%s: %v: index %d with length %d", e.Op, ErrIndexOutOfRange, e.Index, e.Len)
+}
+
+func (e *IndexError) Unwrap() error {
+	return ErrIndexOutOfRange
+}
+
+// ConvergenceError is returned when an iterative algorithm stops
+// after Iterations steps without reaching its tolerance
+type ConvergenceError struct {
+	Op         string
+	Iterations int
+}
+
+func (e *ConvergenceError) Error() string {
+	return fmt.Sprintf("%s: %v after %d iterations", e.Op, ErrNoConvergence, e.Iterations)
+}
+
+func (e *ConvergenceError) Unwrap() error {
+	return ErrNoConvergence
+}
+
+func newShapeError(op string, err error, matrices ...[][]float64) *ShapeError {
+	shapes := make([]Shape, len(matrices))
+	for i := range matrices {
+		shapes[i] = GetShape(matrices[i])
+	}
+
+	return &ShapeError{Op: op, Shapes: shapes, Err: err}
+}
+
+// checkRectangular returns an error if the rows of the matrix have different lengths
+func checkRectangular(op string, matrix [][]float64) error {
+	for i := range matrix {
+		if len(matrix[i]) != len(matrix[0]) {
+			return &ShapeError{
+				Op:     op,
+				Shapes: []Shape{GetShape(matrix), {Rows: 1, Cols: len(matrix[i])}},
+				Err:    ErrDimensionMismatch,
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSquare returns an error if the matrix is not square
+func checkSquare(op string, matrix [][]float64) error {
+	if !IsMatrixSquare(matrix) {
+		return newShapeError(op, ErrNotSquare, matrix)
+	}
+
+	return nil
+}
+
+// TryMultiplyMatrices is MultiplyMatrices but returns an error
+// instead of panicking when the matrices cannot be multiplied
+func TryMultiplyMatrices(matrixA, matrixB [][]float64) ([][]float64, error) {
+	if err := checkRectangular("MultiplyMatrices", matrixA); err != nil {
+		return nil, err
+	}
+	if err := checkRectangular("MultiplyMatrices", matrixB); err != nil {
+		return nil, err
+	}
+	if !CanMultiplyMatrices(matrixA, matrixB) {
+		return nil, newShapeError("MultiplyMatrices", ErrDimensionMismatch, matrixA, matrixB)
+	}
+
+	return MultiplyMatrices(matrixA, matrixB), nil
+}
+
+// TryAddMatrices is AddMatrices but returns an error
+// instead of panicking when the matrices have different shapes
+func TryAddMatrices(matrixA, matrixB [][]float64) ([][]float64, error) {
+	if err := checkRectangular("AddMatrices", matrixA); err != nil {
+		return nil, err
+	}
+	if err := checkRectangular("AddMatrices", matrixB); err != nil {
+		return nil, err
+	}
+	if GetShape(matrixA) != GetShape(matrixB) {
+		return nil, newShapeError("AddMatrices", ErrDimensionMismatch, matrixA, matrixB)
+	}
+
+	return AddMatrices(matrixA, matrixB), nil
+}
+
+// TryHadamardProduct is HadamardProduct but returns an error
+// instead of panicking when the matrices have different shapes
+func TryHadamardProduct(matrixA, matrixB [][]float64) ([][]float64, error) {
+	if err := checkRectangular("HadamardProduct", matrixA); err != nil {
+		return nil, err
+	}
+	if err := checkRectangular("HadamardProduct", matrixB); err != nil {
+		return nil, err
+	}
+	if GetShape(matrixA) != GetShape(matrixB) {
+		return nil, newShapeError("HadamardProduct", ErrDimensionMismatch, matrixA, matrixB)
+	}
+	if len(matrixA) == 0 {
+		return [][]float64{}, nil
+	}
+
+	result := make([][]float64, len(matrixA))
+	for i := range matrixA {
+		result[i] = make([]float64, len(matrixA[i]))
+		for j := range matrixA[i] {
+			result[i][j] = matrixA[i][j] * matrixB[i][j]
+		}
+	}
+
+	return result, nil
+}
+
+// TryAppendMatrix is AppendMatrix but returns an error
+// instead of panicking when the matrices have a different number of rows
+func TryAppendMatrix(matrixA, matrixB [][]float64) ([][]float64, error) {
+	if len(matrixA) != len(matrixB) && len(matrixA) > 0 && len(matrixB) > 0 {
+		return nil, newShapeError("AppendMatrix", ErrDimensionMismatch, matrixA, matrixB)
+	}
+
+	return AppendMatrix(matrixA, matrixB), nil
+}
+
+// TryGetDeterminant is GetDeterminant but returns an error
+// instead of panicking when the matrix is not square
+func TryGetDeterminant(matrix [][]float64) (float64, error) {
+	if err := checkSquare("GetDeterminant", matrix); err != nil {
+		return 0, err
+	}
+
+	return GetDeterminant(matrix), nil
+}
+
+// TryGetMinor is GetMinor but returns an error
+// instead of panicking when i or j are outside of the matrix
+func TryGetMinor(matrix [][]float64, i, j int) ([][]float64, error) {
+	if i < 0 || i >= len(matrix) {
+		return nil, &IndexError{Op: "GetMinor", Index: i, Len: len(matrix)}
+	}
+	if j < 0 || j >= len(matrix[i]) {
+		return nil, &IndexError{Op: "GetMinor", Index: j, Len: len(matrix[i])}
+	}
+
+	return GetMinor(matrix, i, j), nil
+}
+
+// TryGetCofactorMatrix is GetCofactorMatrix but returns an error
+// instead of panicking when the matrix is not square
+func TryGetCofactorMatrix(matrix [][]float64) ([][]float64, error) {
+	if err := checkSquare("GetCofactorMatrix", matrix); err != nil {
+		return nil, err
+	}
+
+	return GetCofactorMatrix(matrix), nil
+}
+
+// TryGetInverseMatrixByDeterminant is GetInverseMatrixByDeterminant but returns an error
+// instead of panicking when the matrix is not square or not invertible
+func TryGetInverseMatrixByDeterminant(matrix [][]float64) ([][]float64, error) {
+	if err := checkSquare("GetInverseMatrixByDeterminant", matrix); err != nil {
+		return nil, err
+	}
+	if GetDeterminant(matrix) == 0 {
+		return nil, newShapeError("GetInverseMatrixByDeterminant", ErrSingular, matrix)
+	}
+
+	return GetInverseMatrixByDeterminant(matrix), nil
+}
+
+// TryCrossProduct is CrossProduct but returns an error
+// instead of panicking when the vectors are not in 3 dimensions
+func TryCrossProduct(vectorA, vectorB []float64) ([]float64, error) {
+	if len(vectorA) != 3 || len(vectorB) != 3 {
+		return nil, &ShapeError{
+			Op:     "CrossProduct",
+			Shapes: []Shape{{Rows: 1, Cols: len(vectorA)}, {Rows: 1, Cols: len(vectorB)}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	return CrossProduct(vectorA, vectorB), nil
+}
+
+// TryGetColumn is GetColumn but returns an error
+// instead of panicking when the column index is outside of the matrix
+func TryGetColumn(matrix [][]float64, columnIndex int) ([][]float64, error) {
+	cols := GetShape(matrix).Cols
+	if columnIndex < 0 || columnIndex >= cols {
+		return nil, &IndexError{Op: "GetColumn", Index: columnIndex, Len: cols}
+	}
+	if err := checkRectangular("GetColumn", matrix); err != nil {
+		return nil, err
+	}
+
+	return GetColumn(matrix, columnIndex), nil
+}
+
+// TrySwapRows is SwapRows but returns an error
+// instead of panicking when a row index is outside of the matrix
+func TrySwapRows(matrix [][]float64, i, j int) ([][]float64, error) {
+	for _, index := range []int{i, j} {
+		if index < 0 || index >= len(matrix) {
+			return nil, &IndexError{Op: "SwapRows", Index: index, Len: len(matrix)}
+		}
+	}
+
+	return SwapRows(matrix, i, j), nil
+}
+
+// TryMultiplyRowByScalar is MultiplyRowByScalar but returns an error
+// instead of panicking when the row index is outside of the matrix
+func TryMultiplyRowByScalar(matrix [][]float64, rowIndex int, scalar float64) ([][]float64, error) {
+	if rowIndex < 0 || rowIndex >= len(matrix) {
+		return nil, &IndexError{Op: "MultiplyRowByScalar", Index: rowIndex, Len: len(matrix)}
+	}
+
+	return MultiplyRowByScalar(matrix, rowIndex, scalar), nil
+}
+
+// TryAddRowToRow is AddRowToRow but returns an error instead of panicking
+// when the row index is outside of the matrix or the row has the wrong length
+func TryAddRowToRow(matrix [][]float64, rowToAdd []float64, rowIndex int) ([][]float64, error) {
+	if rowIndex < 0 || rowIndex >= len(matrix) {
+		return nil, &IndexError{Op: "AddRowToRow", Index: rowIndex, Len: len(matrix)}
+	}
+	if len(matrix[0]) != len(rowToAdd) {
+		return nil, &ShapeError{
+			Op:     "AddRowToRow",
+			Shapes: []Shape{GetShape(matrix), {Rows: 1, Cols: len(rowToAdd)}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	return AddRowToRow(matrix, rowToAdd, rowIndex), nil
+}
+
+// TryGetEigenvalues is GetEigenvalues but returns an error instead of panicking
+// when the matrix is not square or the QR algorithm does not converge.
+// Non convergence is reported as ErrNoConvergence, no partial result is returned.
+func TryGetEigenvalues(matrix [][]float64) ([]complex128, error) {
+	if err := checkSquare("GetEigenvalues", matrix); err != nil {
+		return nil, err
+	}
+
+	hess, err := NewHessenberg(Matrix{Data: matrix})
+	if err != nil {
+		return nil, err
+	}
+
+	return hessenbergEigenvalues(hess.H.Data)
+}
+
+// TryGetEigenvectors is GetEigenvectors but returns an error
+// instead of panicking when the matrix is not square
+func TryGetEigenvectors(matrix [][]float64) ([][]complex128, error) {
+	if err := checkSquare("GetEigenvectors", matrix); err != nil {
+		return nil, err
+	}
+
+	return GetEigenvectors(matrix), nil
+}
+
+// TryReadCSVToMatrixFromFile is ReadCSVToMatrixFromFile but returns an error
+// instead of panicking when the file cannot be opened or parsed
+func TryReadCSVToMatrixFromFile(filePath string, skipHeader bool) (Matrix, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return Matrix{}, err
+	}
+	defer file.Close()
+
+	return TryNewMatrixFromReader(file, skipHeader)
+}
+
+// TryNewMatrixFromReader is NewMatrixFromReader but returns an error
+// instead of panicking when the CSV data cannot be parsed
+func TryNewMatrixFromReader(reader io.Reader, skipHeader bool) (Matrix, error) {
+	csvreader := csv.NewReader(reader)
+	records, err := csvreader.ReadAll()
+	if err != nil {
+		return Matrix{}, err
+	}
+	if skipHeader && len(records) > 0 {
+		records = records[1:]
+	}
+
+	matrixData := make([][]float64, len(records))
+	for i, record := range records {
+		row, err := parseCSVRecord(record)
+		if err != nil {
+			return Matrix{}, fmt.Errorf("row %d: %w", i, err)
+		}
+		matrixData[i] = row
+	}
+
+	return NewMatrix(matrixData), nil
+}
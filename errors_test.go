@@ -0,0 +1,339 @@
+package linearalgebra
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTryMultiplyMatrices(t *testing.T) {
+	tests := []struct {
+		name       string
+		matrixA    [][]float64
+		matrixB    [][]float64
+		want       [][]float64
+		wantErr    error
+		wantShapes []Shape
+	}{
+		{
+			name:    "valid multiplication",
+			matrixA: [][]float64{{1, 2}, {3, 4}},
+			matrixB: [][]float64{{5, 6}, {7, 8}},
+			want:    [][]float64{{19, 22}, {43, 50}},
+		},
+		{
+			name:       "columns of A do not match rows of B",
+			matrixA:    [][]float64{{1, 2, 3}, {4, 5, 6}},
+			matrixB:    [][]float64{{1, 2, 3}, {4, 5, 6}},
+			wantErr:    ErrDimensionMismatch,
+			wantShapes: []Shape{{Rows: 2, Cols: 3}, {Rows: 2, Cols: 3}},
+		},
+		{
+			name:       "ragged matrix",
+			matrixA:    [][]float64{{1, 2}, {3}},
+			matrixB:    [][]float64{{1}, {2}},
+			wantErr:    ErrDimensionMismatch,
+			wantShapes: []Shape{{Rows: 2, Cols: 2}, {Rows: 1, Cols: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TryMultiplyMatrices(tt.matrixA, tt.matrixB)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TryMultiplyMatrices() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				var shapeErr *ShapeError
+				if !errors.As(err, &shapeErr) {
+					t.Fatalf("expected *ShapeError, got %T", err)
+				}
+				if !reflect.DeepEqual(shapeErr.Shapes, tt.wantShapes) {
+					t.Errorf("ShapeError.Shapes = %v, want %v", shapeErr.Shapes, tt.wantShapes)
+				}
+				return
+			}
+			if !areMatricesEqual(got, tt.want) {
+				t.Errorf("TryMultiplyMatrices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTryAddMatrices(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrixA [][]float64
+		matrixB [][]float64
+		want    [][]float64
+		wantErr error
+	}{
+		{
+			name:    "same shape",
+			matrixA: [][]float64{{1, 2}, {3, 4}},
+			matrixB: [][]float64{{1, 1}, {1, 1}},
+			want:    [][]float64{{2, 3}, {4, 5}},
+		},
+		{
+			name:    "different number of rows",
+			matrixA: [][]float64{{1, 2}, {3, 4}},
+			matrixB: [][]float64{{1, 1}},
+			wantErr: ErrDimensionMismatch,
+		},
+		{
+			name:    "different number of columns",
+			matrixA: [][]float64{{1, 2}, {3, 4}},
+			matrixB: [][]float64{{1}, {1}},
+			wantErr: ErrDimensionMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TryAddMatrices(tt.matrixA, tt.matrixB)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TryAddMatrices() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !areMatricesEqual(got, tt.want) {
+				t.Errorf("TryAddMatrices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTryHadamardProduct(t *testing.T) {
+	got, err := TryHadamardProduct([][]float64{{1, 2}, {3, 4}}, [][]float64{{2, 2}, {0, 1}})
+	if err != nil {
+		t.Fatalf("TryHadamardProduct() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(got, [][]float64{{2, 4}, {0, 4}}) {
+		t.Errorf("TryHadamardProduct() = %v", got)
+	}
+
+	// 2x3 and 3x2 can be multiplied but do not have the same shape
+	_, err = TryHadamardProduct([][]float64{{1, 2, 3}, {4, 5, 6}}, [][]float64{{1, 2}, {3, 4}, {5, 6}})
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("TryHadamardProduct() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestTryGetDeterminant(t *testing.T) {
+	got, err := TryGetDeterminant([][]float64{{1, 2}, {3, 4}})
+	if err != nil || got != -2 {
+		t.Errorf("TryGetDeterminant() = %v, %v, want -2, nil", got, err)
+	}
+
+	_, err = TryGetDeterminant([][]float64{{1, 2, 3}, {4, 5, 6}})
+	if !errors.Is(err, ErrNotSquare) {
+		t.Errorf("TryGetDeterminant() error = %v, want %v", err, ErrNotSquare)
+	}
+}
+
+func TestTryGetInverseMatrixByDeterminant(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  [][]float64
+		want    [][]float64
+		wantErr error
+	}{
+		{
+			name:   "invertible",
+			matrix: [][]float64{{4, 7}, {2, 6}},
+			want:   [][]float64{{0.6, -0.7}, {-0.2, 0.4}},
+		},
+		{
+			name:    "singular",
+			matrix:  [][]float64{{1, 2}, {2, 4}},
+			wantErr: ErrSingular,
+		},
+		{
+			name:    "not square",
+			matrix:  [][]float64{{1, 2, 3}},
+			wantErr: ErrNotSquare,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TryGetInverseMatrixByDeterminant(tt.matrix)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TryGetInverseMatrixByDeterminant() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !areMatricesEqual(got, tt.want) {
+				t.Errorf("TryGetInverseMatrixByDeterminant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTryCrossProduct(t *testing.T) {
+	got, err := TryCrossProduct([]float64{1, 0, 0}, []float64{0, 1, 0})
+	if err != nil || !reflect.DeepEqual(got, []float64{0, 0, 1}) {
+		t.Errorf("TryCrossProduct() = %v, %v, want [0 0 1], nil", got, err)
+	}
+
+	_, err = TryCrossProduct([]float64{1, 0}, []float64{0, 1, 0})
+	var shapeErr *ShapeError
+	if !errors.As(err, &shapeErr) || !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("TryCrossProduct() error = %v, want *ShapeError wrapping %v", err, ErrDimensionMismatch)
+	}
+	if shapeErr.Shapes[0] != (Shape{Rows: 1, Cols: 2}) {
+		t.Errorf("ShapeError.Shapes[0] = %v, want 1x2", shapeErr.Shapes[0])
+	}
+}
+
+func TestTryIndexErrors(t *testing.T) {
+	matrix := [][]float64{{1, 2}, {3, 4}}
+	tests := []struct {
+		name      string
+		call      func() error
+		wantIndex int
+		wantLen   int
+	}{
+		{
+			name: "GetColumn negative index",
+			call: func() error {
+				_, err := TryGetColumn(matrix, -1)
+				return err
+			},
+			wantIndex: -1,
+			wantLen:   2,
+		},
+		{
+			name: "GetColumn index past the end",
+			call: func() error {
+				_, err := TryGetColumn(matrix, 2)
+				return err
+			},
+			wantIndex: 2,
+			wantLen:   2,
+		},
+		{
+			name: "SwapRows index equal to length",
+			call: func() error {
+				_, err := TrySwapRows(CopyMatrix(matrix), 0, 2)
+				return err
+			},
+			wantIndex: 2,
+			wantLen:   2,
+		},
+		{
+			name: "MultiplyRowByScalar",
+			call: func() error {
+				_, err := TryMultiplyRowByScalar(CopyMatrix(matrix), 5, 2)
+				return err
+			},
+			wantIndex: 5,
+			wantLen:   2,
+		},
+		{
+			name: "GetMinor",
+			call: func() error {
+				_, err := TryGetMinor(matrix, 0, 3)
+				return err
+			},
+			wantIndex: 3,
+			wantLen:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, ErrIndexOutOfRange) {
+				t.Fatalf("error = %v, want %v", err, ErrIndexOutOfRange)
+			}
+			var indexErr *IndexError
+			if !errors.As(err, &indexErr) {
+				t.Fatalf("expected *IndexError, got %T", err)
+			}
+			if indexErr.Index != tt.wantIndex || indexErr.Len != tt.wantLen {
+				t.Errorf("IndexError = %+v, want index %d len %d", indexErr, tt.wantIndex, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestTrySwapRows(t *testing.T) {
+	got, err := TrySwapRows([][]float64{{1, 2}, {3, 4}}, 0, 1)
+	if err != nil {
+		t.Fatalf("TrySwapRows() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(got, [][]float64{{3, 4}, {1, 2}}) {
+		t.Errorf("TrySwapRows() = %v", got)
+	}
+}
+
+func TestTryNewMatrixFromReader(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		skipHeader bool
+		want       [][]float64
+		wantErr    bool
+	}{
+		{
+			name:  "valid CSV",
+			input: "1,2\n3,4\n",
+			want:  [][]float64{{1, 2}, {3, 4}},
+		},
+		{
+			name:       "only a header",
+			input:      "a,b\n",
+			skipHeader: true,
+			want:       [][]float64{},
+		},
+		{
+			name:       "empty input with header skip",
+			input:      "",
+			skipHeader: true,
+			want:       [][]float64{},
+		},
+		{
+			name:    "non numeric value",
+			input:   "1,2\n3,x\n",
+			wantErr: true,
+		},
+		{
+			name:    "header not skipped",
+			input:   "a,b\n1,2\n",
+			wantErr: true,
+		},
+		{
+			name:    "rows with different number of fields",
+			input:   "1,2\n3\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TryNewMatrixFromReader(strings.NewReader(tt.input), tt.skipHeader)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TryNewMatrixFromReader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !areMatricesEqual(got.Data, tt.want) {
+				t.Errorf("TryNewMatrixFromReader() = %v, want %v", got.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestTryReadCSVToMatrixFromFile(t *testing.T) {
+	_, err := TryReadCSVToMatrixFromFile("/nonexistent/matrix.csv", false)
+	if err == nil {
+		t.Errorf("TryReadCSVToMatrixFromFile() expected error for missing file")
+	}
+
+	m, err := TryReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	if err != nil {
+		t.Fatalf("TryReadCSVToMatrixFromFile() unexpected error: %v", err)
+	}
+	if len(m.Data) == 0 {
+		t.Errorf("TryReadCSVToMatrixFromFile() returned empty matrix")
+	}
+}
+
+func TestShapeError_Error(t *testing.T) {
+	err := newShapeError("MultiplyMatrices", ErrDimensionMismatch, [][]float64{{1, 2, 3}}, [][]float64{{1}})
+	want := "MultiplyMatrices: dimension mismatch (1x3, 1x1)"
+	if err.Error() != want {
+		t.Errorf("ShapeError.Error() = %q, want %q", err.Error(), want)
+	}
+}
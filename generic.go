@@ -0,0 +1,470 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scalar is the set of element types GenericMatrix supports: float32 to save
+// memory, float64, and *big.Rat for exact fractions
+type Scalar interface {
+	float32 | float64 | *big.Rat
+}
+
+// arithmetic is the field operations GenericMatrix needs for one Scalar type.
+// *big.Rat has no operators, so the generic code goes through this instead.
+type arithmetic[T Scalar] interface {
+	zero() T
+	one() T
+	add(a, b T) T
+	sub(a, b T) T
+	mul(a, b T) T
+	quo(a, b T) T
+	isZero(a T) bool
+	// abs is |a| as a float64, it is only used to compare pivots
+	abs(a T) float64
+	// epsilon is the machine epsilon of T, 0 for exact arithmetic
+	epsilon() float64
+	// fromFloat64 converts v, false if T cannot represent it
+	fromFloat64(v float64) (T, bool)
+	toFloat64(a T) float64
+	clone(a T) T
+	format(a T) string
+}
+
+// arithmeticFor returns the arithmetic of T
+func arithmeticFor[T Scalar]() arithmetic[T] {
+	var zero T
+	switch any(zero).(type) {
+	case float32:
+		return any(floatArithmetic[float32]{eps: 1.1920929e-07, bits: 32}).(arithmetic[T])
+	case float64:
+		return any(floatArithmetic[float64]{eps: machineEpsilon, bits: 64}).(arithmetic[T])
+	default:
+		return any(ratArithmetic{}).(arithmetic[T])
+	}
+}
+
+// floatArithmetic is the arithmetic of float32 and float64
+type floatArithmetic[F float32 | float64] struct {
+	eps  float64
+	bits int
+}
+
+func (floatArithmetic[F]) zero() F                         { return 0 }
+func (floatArithmetic[F]) one() F                          { return 1 }
+func (floatArithmetic[F]) add(a, b F) F                    { return a + b }
+func (floatArithmetic[F]) sub(a, b F) F                    { return a - b }
+func (floatArithmetic[F]) mul(a, b F) F                    { return a * b }
+func (floatArithmetic[F]) quo(a, b F) F                    { return a / b }
+func (floatArithmetic[F]) isZero(a F) bool                 { return a == 0 }
+func (floatArithmetic[F]) abs(a F) float64                 { return math.Abs(float64(a)) }
+func (f floatArithmetic[F]) epsilon() float64              { return f.eps }
+func (floatArithmetic[F]) fromFloat64(v float64) (F, bool) { return F(v), true }
+func (floatArithmetic[F]) toFloat64(a F) float64           { return float64(a) }
+func (floatArithmetic[F]) clone(a F) F                     { return a }
+func (f floatArithmetic[F]) format(a F) string {
+	return strconv.FormatFloat(float64(a), 'g', -1, f.bits)
+}
+
+// ratArithmetic is the exact arithmetic of *big.Rat, a nil entry is read as 0.
+// Every operation returns a new value, so entries are never shared.
+type ratArithmetic struct{}
+
+// rat returns a, or 0 if a is nil
+func rat(a *big.Rat) *big.Rat {
+	if a == nil {
+		return new(big.Rat)
+	}
+	return a
+}
+
+func (ratArithmetic) zero() *big.Rat             { return new(big.Rat) }
+func (ratArithmetic) one() *big.Rat              { return big.NewRat(1, 1) }
+func (ratArithmetic) add(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(rat(a), rat(b)) }
+func (ratArithmetic) sub(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(rat(a), rat(b)) }
+func (ratArithmetic) mul(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(rat(a), rat(b)) }
+func (ratArithmetic) quo(a, b *big.Rat) *big.Rat { return new(big.Rat).Quo(rat(a), rat(b)) }
+func (ratArithmetic) isZero(a *big.Rat) bool     { return rat(a).Sign() == 0 }
+func (ratArithmetic) epsilon() float64           { return 0 }
+func (ratArithmetic) clone(a *big.Rat) *big.Rat  { return new(big.Rat).Set(rat(a)) }
+func (ratArithmetic) format(a *big.Rat) string   { return rat(a).RatString() }
+func (ratArithmetic) toFloat64(a *big.Rat) float64 {
+	v, _ := rat(a).Float64()
+	return v
+}
+func (r ratArithmetic) abs(a *big.Rat) float64 {
+	return math.Abs(r.toFloat64(a))
+}
+
+// fromFloat64 returns the exact value of v, 0.1 is 3602879701896397/36028797018963968.
+// Use ParseRatMatrix to enter decimal fractions exactly.
+// It returns false for NaN and infinities, which are not fractions.
+func (ratArithmetic) fromFloat64(v float64) (*big.Rat, bool) {
+	res := new(big.Rat).SetFloat64(v)
+	return res, res != nil
+}
+
+// GenericMatrix is a matrix over any Scalar type. The same RREF, Mul and Det
+// code runs in float32, float64 or exact rational arithmetic.
+// A GenericMatrix[*big.Rat] never rounds, so RREF returns 3/10 where
+// ToRowReducedEchelonForm returns 0.30000000000000004.
+type GenericMatrix[T Scalar] struct {
+	Data [][]T
+}
+
+// NewGenericMatrix returns a GenericMatrix with a copy of data
+func NewGenericMatrix[T Scalar](data [][]T) GenericMatrix[T] {
+	f := arithmeticFor[T]()
+	res := make([][]T, len(data))
+	for i := range data {
+		res[i] = make([]T, len(data[i]))
+		for j, v := range data[i] {
+			res[i][j] = f.clone(v)
+		}
+	}
+
+	return GenericMatrix[T]{Data: res}
+}
+
+// NewGenericZeroMatrix returns a rows x cols GenericMatrix of 0s
+func NewGenericZeroMatrix[T Scalar](rows, cols int) GenericMatrix[T] {
+	if rows < 0 || cols < 0 {
+		panic("illegal operation")
+	}
+
+	f := arithmeticFor[T]()
+	data, flat := contiguousRows[T](rows, cols)
+	for i := range flat {
+		flat[i] = f.zero()
+	}
+
+	return GenericMatrix[T]{Data: data}
+}
+
+// NewGenericIdentityMatrix returns the n x n identity matrix
+func NewGenericIdentityMatrix[T Scalar](n int) GenericMatrix[T] {
+	f := arithmeticFor[T]()
+	res := NewGenericZeroMatrix[T](n, n)
+	for i := 0; i < n; i++ {
+		res.Data[i][i] = f.one()
+	}
+
+	return res
+}
+
+// GenericMatrixFrom converts a float64 Matrix to element type T.
+// Converting to *big.Rat keeps the exact binary value of every float64,
+// it returns ErrInvalidArgument for NaN and infinities.
+func GenericMatrixFrom[T Scalar](m Matrix) (GenericMatrix[T], error) {
+	f := arithmeticFor[T]()
+	res := NewGenericZeroMatrix[T](m.Rows(), m.Cols())
+	for i := range m.Data {
+		for j, v := range m.Data[i] {
+			converted, ok := f.fromFloat64(v)
+			if !ok {
+				return GenericMatrix[T]{}, fmt.Errorf("GenericMatrixFrom: %w: %v at row %d column %d is not a fraction", ErrInvalidArgument, v, i, j)
+			}
+			res.Data[i][j] = converted
+		}
+	}
+
+	return res, nil
+}
+
+// ParseRatMatrix reads a matrix of exact fractions written as "3/10", "0.3", "-2" or "1e-3".
+// It returns an error wrapping ErrInvalidFormat if an entry is not a number,
+// or ErrDimensionMismatch if the rows have different lengths.
+func ParseRatMatrix(rows [][]string) (GenericMatrix[*big.Rat], error) {
+	res := make([][]*big.Rat, len(rows))
+	for i := range rows {
+		if len(rows[i]) != len(rows[0]) {
+			return GenericMatrix[*big.Rat]{}, &ShapeError{
+				Op:     "ParseRatMatrix",
+				Shapes: []Shape{{Rows: len(rows), Cols: len(rows[0])}, {Rows: 1, Cols: len(rows[i])}},
+				Err:    ErrDimensionMismatch,
+			}
+		}
+		res[i] = make([]*big.Rat, len(rows[i]))
+		for j, s := range rows[i] {
+			v, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+			if !ok {
+				return GenericMatrix[*big.Rat]{}, fmt.Errorf("ParseRatMatrix: row %d column %d: %w: %q", i, j, ErrInvalidFormat, s)
+			}
+			res[i][j] = v
+		}
+	}
+
+	return GenericMatrix[*big.Rat]{Data: res}, nil
+}
+
+// Dims returns the number of rows and columns
+func (m GenericMatrix[T]) Dims() (int, int) {
+	if len(m.Data) == 0 {
+		return 0, 0
+	}
+
+	return len(m.Data), len(m.Data[0])
+}
+
+// At returns the element in row i and column j
+func (m GenericMatrix[T]) At(i, j int) T {
+	rows, cols := m.Dims()
+	if i < 0 || i >= rows || j < 0 || j >= cols {
+		panic("index out of bounds")
+	}
+
+	return m.Data[i][j]
+}
+
+// shape returns the shape of m, and an error if its rows have different lengths
+func (m GenericMatrix[T]) shape(op string) (Shape, error) {
+	rows, cols := m.Dims()
+	for i := range m.Data {
+		if len(m.Data[i]) != cols {
+			return Shape{}, &ShapeError{
+				Op:     op,
+				Shapes: []Shape{{Rows: rows, Cols: cols}, {Rows: 1, Cols: len(m.Data[i])}},
+				Err:    ErrDimensionMismatch,
+			}
+		}
+	}
+
+	return Shape{Rows: rows, Cols: cols}, nil
+}
+
+// ToMatrix converts the matrix to float64, rounding fractions to the nearest float64
+func (m GenericMatrix[T]) ToMatrix() Matrix {
+	f := arithmeticFor[T]()
+	rows, cols := m.Dims()
+	res := NewZeroMatrix(rows, cols)
+	for i := range m.Data {
+		for j, v := range m.Data[i] {
+			res.Data[i][j] = f.toFloat64(v)
+		}
+	}
+
+	return res
+}
+
+// Transpose returns the transpose, the entries are shared with m
+func (m GenericMatrix[T]) Transpose() GenericMatrix[T] {
+	return GenericMatrix[T]{Data: TransposeMatrix(m.Data)}
+}
+
+// String formats the matrix one row per line, fractions as "3/10"
+func (m GenericMatrix[T]) String() string {
+	f := arithmeticFor[T]()
+	var sb strings.Builder
+	for i := range m.Data {
+		sb.WriteString("[")
+		for j, v := range m.Data[i] {
+			if j > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(f.format(v))
+		}
+		sb.WriteString("]\n")
+	}
+
+	return sb.String()
+}
+
+// Equal returns true if both matrices have the same shape and exactly the same entries
+func (m GenericMatrix[T]) Equal(b GenericMatrix[T]) bool {
+	f := arithmeticFor[T]()
+	if len(m.Data) != len(b.Data) {
+		return false
+	}
+	for i := range m.Data {
+		if len(m.Data[i]) != len(b.Data[i]) {
+			return false
+		}
+		for j := range m.Data[i] {
+			if !f.isZero(f.sub(m.Data[i][j], b.Data[i][j])) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Mul returns A * B
+func (m GenericMatrix[T]) Mul(b GenericMatrix[T]) (GenericMatrix[T], error) {
+	shapeA, err := m.shape("GenericMatrix.Mul")
+	if err != nil {
+		return GenericMatrix[T]{}, err
+	}
+	shapeB, err := b.shape("GenericMatrix.Mul")
+	if err != nil {
+		return GenericMatrix[T]{}, err
+	}
+	if shapeA.Cols != shapeB.Rows {
+		return GenericMatrix[T]{}, &ShapeError{Op: "GenericMatrix.Mul", Shapes: []Shape{shapeA, shapeB}, Err: ErrDimensionMismatch}
+	}
+
+	// float64 goes through the tiled and parallel MultiplyMatrices
+	if a64, ok := any(m).(GenericMatrix[float64]); ok {
+		b64 := any(b).(GenericMatrix[float64])
+		res := MultiplyMatrices(a64.Data, b64.Data)
+		return any(GenericMatrix[float64]{Data: res}).(GenericMatrix[T]), nil
+	}
+
+	f := arithmeticFor[T]()
+	res := NewGenericZeroMatrix[T](shapeA.Rows, shapeB.Cols)
+	for i := range m.Data {
+		out := res.Data[i]
+		for k, a := range m.Data[i] {
+			if f.isZero(a) {
+				continue
+			}
+			for j, v := range b.Data[k] {
+				out[j] = f.add(out[j], f.mul(a, v))
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// genericTolerance is the size under which an entry counts as 0 during
+// elimination, 0 for exact arithmetic
+func genericTolerance[T Scalar](f arithmetic[T], matrix [][]T) float64 {
+	if f.epsilon() == 0 {
+		return 0
+	}
+
+	maxAbs := 0.0
+	for i := range matrix {
+		for _, v := range matrix[i] {
+			maxAbs = math.Max(maxAbs, f.abs(v))
+		}
+	}
+	size := len(matrix)
+	if len(matrix) > 0 && len(matrix[0]) > size {
+		size = len(matrix[0])
+	}
+
+	return float64(size) * f.epsilon() * maxAbs
+}
+
+// findPivot returns the row at or below start to use as the pivot of column col,
+// or -1 if every candidate is 0. In exact arithmetic it takes the first non
+// zero entry, as done by hand, otherwise the largest one for stability.
+func findPivot[T Scalar](f arithmetic[T], matrix [][]T, start, col int, tol float64) int {
+	best, bestVal := -1, tol
+	for r := start; r < len(matrix); r++ {
+		if f.isZero(matrix[r][col]) {
+			continue
+		}
+		v := f.abs(matrix[r][col])
+		if f.epsilon() == 0 {
+			return r
+		}
+		if v > bestVal {
+			best, bestVal = r, v
+		}
+	}
+
+	return best
+}
+
+// RREF returns the reduced row echelon form. In float32 and float64 entries
+// smaller than n * epsilon * max|A| count as 0, with *big.Rat the result is exact.
+func (m GenericMatrix[T]) RREF() (GenericMatrix[T], error) {
+	shape, err := m.shape("GenericMatrix.RREF")
+	if err != nil {
+		return GenericMatrix[T]{}, err
+	}
+
+	f := arithmeticFor[T]()
+	matrix := NewGenericMatrix(m.Data).Data
+	tol := genericTolerance(f, matrix)
+
+	pivotRow := 0
+	for col := 0; col < shape.Cols && pivotRow < shape.Rows; col++ {
+		best := findPivot(f, matrix, pivotRow, col, tol)
+		if best < 0 {
+			continue
+		}
+		matrix[pivotRow], matrix[best] = matrix[best], matrix[pivotRow]
+
+		// scale the pivot row so the leading entry becomes 1
+		scale := matrix[pivotRow][col]
+		for j := col; j < shape.Cols; j++ {
+			matrix[pivotRow][j] = f.quo(matrix[pivotRow][j], scale)
+		}
+
+		for r := 0; r < shape.Rows; r++ {
+			if r == pivotRow || f.isZero(matrix[r][col]) {
+				continue
+			}
+			factor := matrix[r][col]
+			for j := col; j < shape.Cols; j++ {
+				matrix[r][j] = f.sub(matrix[r][j], f.mul(factor, matrix[pivotRow][j]))
+			}
+		}
+
+		pivotRow++
+	}
+
+	// clean up the rounding noise left where the exact result is 0
+	if tol > 0 {
+		for i := range matrix {
+			for j := range matrix[i] {
+				if f.abs(matrix[i][j]) <= tol {
+					matrix[i][j] = f.zero()
+				}
+			}
+		}
+	}
+
+	return GenericMatrix[T]{Data: matrix}, nil
+}
+
+// Det returns the determinant of a square matrix by Gaussian elimination,
+// exact for *big.Rat. A 0x0 matrix has determinant 0, like for GetDeterminant.
+func (m GenericMatrix[T]) Det() (T, error) {
+	f := arithmeticFor[T]()
+	shape, err := m.shape("GenericMatrix.Det")
+	if err != nil {
+		return f.zero(), err
+	}
+	if shape.Rows != shape.Cols {
+		return f.zero(), &ShapeError{Op: "GenericMatrix.Det", Shapes: []Shape{shape}, Err: ErrNotSquare}
+	}
+	if shape.Rows == 0 {
+		return f.zero(), nil
+	}
+
+	matrix := NewGenericMatrix(m.Data).Data
+	det := f.one()
+	for col := 0; col < shape.Cols; col++ {
+		best := findPivot(f, matrix, col, col, 0)
+		if best < 0 {
+			return f.zero(), nil
+		}
+		if best != col {
+			matrix[col], matrix[best] = matrix[best], matrix[col]
+			det = f.sub(f.zero(), det)
+		}
+
+		pivot := matrix[col][col]
+		det = f.mul(det, pivot)
+		for r := col + 1; r < shape.Rows; r++ {
+			if f.isZero(matrix[r][col]) {
+				continue
+			}
+			factor := f.quo(matrix[r][col], pivot)
+			for j := col + 1; j < shape.Cols; j++ {
+				matrix[r][j] = f.sub(matrix[r][j], f.mul(factor, matrix[col][j]))
+			}
+		}
+	}
+
+	return det, nil
+}
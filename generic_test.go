@@ -0,0 +1,256 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// hilbert returns the n x n Hilbert matrix H[i][j] = 1/(i+j+1) as exact fractions
+func hilbert(n int) GenericMatrix[*big.Rat] {
+	res := NewGenericZeroMatrix[*big.Rat](n, n)
+	for i := range res.Data {
+		for j := range res.Data[i] {
+			res.Data[i][j] = big.NewRat(1, int64(i+j+1))
+		}
+	}
+
+	return res
+}
+
+// mustParseRat parses a matrix of fractions or fails the test
+func mustParseRat(t *testing.T, rows [][]string) GenericMatrix[*big.Rat] {
+	t.Helper()
+	m, err := ParseRatMatrix(rows)
+	if err != nil {
+		t.Fatalf("ParseRatMatrix() unexpected error: %v", err)
+	}
+	return m
+}
+
+func TestGenericMatrixRREFExact(t *testing.T) {
+	tests := []struct {
+		name  string
+		input [][]string
+		want  [][]string
+	}{
+		{
+			name:  "fractions",
+			input: [][]string{{"2", "1", "1"}, {"1", "3", "2"}},
+			want:  [][]string{{"1", "0", "1/5"}, {"0", "1", "3/5"}},
+		},
+		{
+			name:  "decimals",
+			input: [][]string{{"0.1", "0.2", "0.3"}, {"1", "3", "0"}},
+			want:  [][]string{{"1", "0", "9"}, {"0", "1", "-3"}},
+		},
+		{
+			name:  "rank deficient",
+			input: [][]string{{"1", "2"}, {"2", "4"}, {"3", "6"}},
+			want:  [][]string{{"1", "2"}, {"0", "0"}, {"0", "0"}},
+		},
+		{
+			name:  "zero first column",
+			input: [][]string{{"0", "3", "1/2"}, {"0", "6", "1"}},
+			want:  [][]string{{"0", "1", "1/6"}, {"0", "0", "0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustParseRat(t, tt.input)
+			got, err := m.RREF()
+			if err != nil {
+				t.Fatalf("RREF() unexpected error: %v", err)
+			}
+			if want := mustParseRat(t, tt.want); !got.Equal(want) {
+				t.Errorf("RREF() = %v, want %v", got, want)
+			}
+			// the input is not modified
+			if !m.Equal(mustParseRat(t, tt.input)) {
+				t.Errorf("RREF() modified its input: %v", m)
+			}
+		})
+	}
+}
+
+func TestGenericMatrixFloatRREF(t *testing.T) {
+	matrices := [][][]float64{
+		{{2, 1, 1}, {1, 3, 2}},
+		{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}},
+		{{0, 1}, {1, 0}, {1, 1}},
+		{{4, -2, 1, 3}, {1, 1, 0, 2}, {3, -3, 1, 1}},
+	}
+	for _, matrix := range matrices {
+		want := ToRowReducedEchelonForm(matrix)
+
+		m64, err := GenericMatrixFrom[float64](NewMatrix(matrix))
+		if err != nil {
+			t.Fatalf("GenericMatrixFrom() unexpected error: %v", err)
+		}
+		got64, err := m64.RREF()
+		if err != nil {
+			t.Fatalf("RREF() unexpected error: %v", err)
+		}
+		if !areMatricesEqual(got64.ToMatrix().Data, want) {
+			t.Errorf("float64 RREF() = %v, want %v", got64, want)
+		}
+
+		m32, err := GenericMatrixFrom[float32](NewMatrix(matrix))
+		if err != nil {
+			t.Fatalf("GenericMatrixFrom() unexpected error: %v", err)
+		}
+		got32, err := m32.RREF()
+		if err != nil {
+			t.Fatalf("RREF() unexpected error: %v", err)
+		}
+		for i := range want {
+			for j := range want[i] {
+				if math.Abs(float64(got32.At(i, j))-want[i][j]) > 1e-5 {
+					t.Errorf("float32 RREF() = %v, want %v", got32, want)
+				}
+			}
+		}
+
+		mRat, err := GenericMatrixFrom[*big.Rat](NewMatrix(matrix))
+		if err != nil {
+			t.Fatalf("GenericMatrixFrom() unexpected error: %v", err)
+		}
+		gotRat, err := mRat.RREF()
+		if err != nil {
+			t.Fatalf("RREF() unexpected error: %v", err)
+		}
+		if !areMatricesEqual(gotRat.ToMatrix().Data, want) {
+			t.Errorf("*big.Rat RREF() = %v, want %v", gotRat, want)
+		}
+	}
+}
+
+func TestGenericMatrixDet(t *testing.T) {
+	// det of the 5x5 Hilbert matrix is 1/266716800000
+	H := hilbert(5)
+	det, err := H.Det()
+	if err != nil {
+		t.Fatalf("Det() unexpected error: %v", err)
+	}
+	if want := big.NewRat(1, 266716800000); det.Cmp(want) != 0 {
+		t.Errorf("Det() = %v, want %v", det.RatString(), want.RatString())
+	}
+
+	h64, err := GenericMatrixFrom[float64](H.ToMatrix())
+	if err != nil {
+		t.Fatalf("GenericMatrixFrom() unexpected error: %v", err)
+	}
+	det64, err := h64.Det()
+	if err != nil {
+		t.Fatalf("Det() unexpected error: %v", err)
+	}
+	if want := 1.0 / 266716800000; math.Abs(det64-want) > 1e-6*want {
+		t.Errorf("float64 Det() = %v, want %v", det64, want)
+	}
+
+	tests := []struct {
+		name  string
+		input [][]string
+		want  string
+	}{
+		{name: "needs a row swap", input: [][]string{{"0", "1"}, {"1", "0"}}, want: "-1"},
+		{name: "singular", input: [][]string{{"1", "2"}, {"1/2", "1"}}, want: "0"},
+		{name: "fractions", input: [][]string{{"1/2", "1/3"}, {"1/4", "1/5"}}, want: "1/60"},
+		{name: "empty", input: [][]string{}, want: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mustParseRat(t, tt.input).Det()
+			if err != nil {
+				t.Fatalf("Det() unexpected error: %v", err)
+			}
+			if got.RatString() != tt.want {
+				t.Errorf("Det() = %v, want %v", got.RatString(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGenericMatrixMul(t *testing.T) {
+	A := [][]float64{{1, 2, 3}, {4, 5, 6}}
+	B := [][]float64{{7, 8}, {9, 10}, {11, 12}}
+	want := MultiplyMatrices(A, B)
+
+	got64, err := NewGenericMatrix(A).Mul(NewGenericMatrix(B))
+	if err != nil {
+		t.Fatalf("Mul() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(got64.Data, want) {
+		t.Errorf("float64 Mul() = %v, want %v", got64, want)
+	}
+
+	a32, _ := GenericMatrixFrom[float32](NewMatrix(A))
+	b32, _ := GenericMatrixFrom[float32](NewMatrix(B))
+	got32, err := a32.Mul(b32)
+	if err != nil {
+		t.Fatalf("Mul() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(got32.ToMatrix().Data, want) {
+		t.Errorf("float32 Mul() = %v, want %v", got32, want)
+	}
+
+	// H * H^-1 = I exactly, with the inverse from RREF([H | I])
+	n := 4
+	H := hilbert(n)
+	augmented := NewGenericZeroMatrix[*big.Rat](n, 2*n)
+	for i := 0; i < n; i++ {
+		copy(augmented.Data[i], H.Data[i])
+		augmented.Data[i][n+i] = big.NewRat(1, 1)
+	}
+	reduced, err := augmented.RREF()
+	if err != nil {
+		t.Fatalf("RREF() unexpected error: %v", err)
+	}
+	inverse := NewGenericZeroMatrix[*big.Rat](n, n)
+	for i := range inverse.Data {
+		copy(inverse.Data[i], reduced.Data[i][n:])
+	}
+	product, err := H.Mul(inverse)
+	if err != nil {
+		t.Fatalf("Mul() unexpected error: %v", err)
+	}
+	if !product.Equal(NewGenericIdentityMatrix[*big.Rat](n)) {
+		t.Errorf("H * H^-1 = %v, want I", product)
+	}
+}
+
+func TestGenericMatrixString(t *testing.T) {
+	m := mustParseRat(t, [][]string{{"0.3", "-1/2"}, {"4", "0"}})
+	if got, want := m.String(), "[3/10 -1/2]\n[4 0]\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	f := NewGenericMatrix([][]float32{{0.1, 2}})
+	if got, want := f.String(), "[0.1 2]\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestGenericMatrixErrors(t *testing.T) {
+	if _, err := ParseRatMatrix([][]string{{"1", "x"}}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ParseRatMatrix() error = %v, want %v", err, ErrInvalidFormat)
+	}
+	if _, err := ParseRatMatrix([][]string{{"1", "2"}, {"3"}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ParseRatMatrix() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := GenericMatrixFrom[*big.Rat](NewMatrix([][]float64{{math.NaN()}})); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("GenericMatrixFrom() error = %v, want %v", err, ErrInvalidArgument)
+	}
+
+	m := NewGenericMatrix([][]float32{{1, 2, 3}, {4, 5, 6}})
+	if _, err := m.Mul(m); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Mul() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := m.Det(); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Det() error = %v, want %v", err, ErrNotSquare)
+	}
+	jagged := NewGenericMatrix([][]float64{{1, 2}, {3}})
+	if _, err := jagged.RREF(); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("RREF() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
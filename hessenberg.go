@@ -0,0 +1,264 @@
+package linearalgebra
+
+import "math"
+
+// Hessenberg is the reduction of a square matrix A to upper Hessenberg form
+// A = Q * H * Q^T
+// where Q is orthogonal and H is 0 below the first subdiagonal.
+// H has the same eigenvalues as A.
+type Hessenberg struct {
+	// H is upper Hessenberg, H[i][j] is 0 for i > j+1
+	H Matrix
+	// Q is orthogonal
+	Q Matrix
+}
+
+// NewHessenberg reduces a square matrix to upper Hessenberg form using Householder
+// reflections. Each column is zeroed below the subdiagonal by H = I - beta*v*v^T,
+// applied from both sides so the result is similar to A.
+// Columns that are already 0 below the subdiagonal are left untouched, so a matrix
+// that is already upper Hessenberg is returned exactly as it is.
+func NewHessenberg(m Matrix) (Hessenberg, error) {
+	if err := checkSquare("NewHessenberg", m.Data); err != nil {
+		return Hessenberg{}, err
+	}
+
+	n := len(m.Data)
+	a := CopyMatrix(m.Data)
+	q := GenerateIdentityMatrix(n)
+
+	for step := 0; step < n-2; step++ {
+		// build the reflector that sends a[step+1:, step] to alpha * e1
+		v := make([]float64, n-step-1)
+		for i := step + 1; i < n; i++ {
+			v[i-step-1] = a[i][step]
+		}
+		if norm2(v[1:]) == 0 {
+			continue
+		}
+		norm := norm2(v)
+		alpha := -norm
+		if v[0] < 0 {
+			alpha = norm
+		}
+		v[0] -= alpha
+		vtv := 0.0
+		for _, value := range v {
+			vtv += value * value
+		}
+		beta := 2 / vtv
+
+		// A = H * A
+		for j := step; j < n; j++ {
+			s := 0.0
+			for i := step + 1; i < n; i++ {
+				s += v[i-step-1] * a[i][j]
+			}
+			s *= beta
+			for i := step + 1; i < n; i++ {
+				a[i][j] -= s * v[i-step-1]
+			}
+		}
+		// A = A * H and Q = Q * H
+		for _, rows := range [][][]float64{a, q} {
+			for i := 0; i < n; i++ {
+				s := 0.0
+				for j := step + 1; j < n; j++ {
+					s += rows[i][j] * v[j-step-1]
+				}
+				s *= beta
+				for j := step + 1; j < n; j++ {
+					rows[i][j] -= s * v[j-step-1]
+				}
+			}
+		}
+
+		a[step+1][step] = alpha
+		for i := step + 2; i < n; i++ {
+			a[i][step] = 0
+		}
+	}
+
+	return Hessenberg{H: Matrix{Data: a}, Q: Matrix{Data: q}}, nil
+}
+
+// maxFrancisIterations is the number of QR steps allowed per eigenvalue
+// before hessenbergEigenvalues gives up
+const maxFrancisIterations = 30
+
+// hessenbergEigenvalues returns the eigenvalues of an upper Hessenberg matrix with
+// the implicit double shift QR algorithm of Francis (Golub and Van Loan,
+// Matrix Computations, algorithms 7.5.1 and 7.5.2). Every step chases a bulge
+// down the subdiagonal with 3x3 Householder reflections, shifting by both
+// eigenvalues of the trailing 2x2 block so complex pairs converge in real arithmetic.
+// Once a subdiagonal entry becomes negligible the trailing 1x1 or 2x2 block
+// is deflated and the iteration continues on the rest of the matrix.
+// The eigenvalues are returned in the order they appear on the diagonal, a complex
+// pair with the positive imaginary part first.
+// It returns ErrNoConvergence if an eigenvalue needs more than maxFrancisIterations steps.
+// H is overwritten.
+func hessenbergEigenvalues(H [][]float64) ([]complex128, error) {
+	n := len(H)
+	eigenvalues := make([]complex128, n)
+
+	// the 1-norm of H, the scale of a negligible entry next to a 0 diagonal
+	norm := 0.0
+	for j := 0; j < n; j++ {
+		column := 0.0
+		for i := 0; i <= min(j+1, n-1); i++ {
+			column += math.Abs(H[i][j])
+		}
+		norm = math.Max(norm, column)
+	}
+
+	steps, total := 0, 0
+	for hi := n - 1; hi >= 0; {
+		// the active block is H[lo:hi+1][lo:hi+1], everything below and left
+		// of it is already 0 or deflated
+		lo := hi
+		for ; lo > 0; lo-- {
+			scale := math.Abs(H[lo-1][lo-1]) + math.Abs(H[lo][lo])
+			if scale == 0 {
+				scale = norm
+			}
+			if math.Abs(H[lo][lo-1]) <= machineEpsilon*scale {
+				H[lo][lo-1] = 0
+				break
+			}
+		}
+
+		switch {
+		case lo == hi:
+			eigenvalues[hi] = complex(H[hi][hi], 0)
+			hi--
+			steps = 0
+			continue
+		case lo == hi-1:
+			eigenvalues[hi-1], eigenvalues[hi] = eigenvalues2x2(H[hi-1][hi-1], H[hi-1][hi], H[hi][hi-1], H[hi][hi])
+			hi -= 2
+			steps = 0
+			continue
+		case steps == maxFrancisIterations:
+			return nil, &ConvergenceError{Op: "GetEigenvalues", Iterations: total}
+		}
+
+		// the shifts are the eigenvalues of the trailing 2x2 block, only their
+		// sum and product are needed
+		sum := H[hi-1][hi-1] + H[hi][hi]
+		product := H[hi-1][hi-1]*H[hi][hi] - H[hi-1][hi]*H[hi][hi-1]
+		if steps > 0 && steps%10 == 0 {
+			// the standard shifts can cycle without converging, every 10 steps use
+			// the complex pair h +- e*(1 + i) built from the size e of the last
+			// subdiagonal entries instead
+			h := H[hi][hi]
+			e := math.Abs(H[hi][hi-1]) + math.Abs(H[hi-1][hi-2])
+			sum = 2 * (h + e)
+			product = (h+e)*(h+e) + e*e
+		}
+		francisStep(H, lo, hi, sum, product)
+		steps++
+		total++
+	}
+
+	return eigenvalues, nil
+}
+
+// francisStep applies one implicit double shift QR step to the active block
+// H[lo:hi+1][lo:hi+1], which must be at least 3x3. The shifts are given by
+// their sum and product, the result is similar to
+// Q^T * H * Q with (H - mu1*I) * (H - mu2*I) = Q * R.
+func francisStep(H [][]float64, lo, hi int, sum, product float64) {
+	// the first column of (H - mu1*I) * (H - mu2*I) = H^2 - sum*H + product*I,
+	// only its first 3 entries are not 0 because H is Hessenberg
+	bulge := []float64{
+		H[lo][lo]*H[lo][lo] + H[lo][lo+1]*H[lo+1][lo] - sum*H[lo][lo] + product,
+		H[lo+1][lo] * (H[lo][lo] + H[lo+1][lo+1] - sum),
+		H[lo+1][lo] * H[lo+2][lo+1],
+	}
+
+	for k := lo; k < hi; k++ {
+		// the reflector acts on rows and columns k to k+size-1, 2 at the bottom
+		size := min(3, hi-k+1)
+		v, beta := householderVector(bulge[:size])
+		if beta != 0 {
+			// H = P * H, the columns left of k-1 are 0 in these rows
+			for j := max(lo, k-1); j <= hi; j++ {
+				s := 0.0
+				for i := range v {
+					s += v[i] * H[k+i][j]
+				}
+				s *= beta
+				for i := range v {
+					H[k+i][j] -= s * v[i]
+				}
+			}
+			// H = H * P, the bulge reaches one row further down
+			for i := lo; i <= min(k+3, hi); i++ {
+				s := 0.0
+				for j := range v {
+					s += H[i][k+j] * v[j]
+				}
+				s *= beta
+				for j := range v {
+					H[i][k+j] -= s * v[j]
+				}
+			}
+		}
+		if k > lo {
+			// the reflector moved the bulge out of column k-1
+			for i := k + 1; i < k+size; i++ {
+				H[i][k-1] = 0
+			}
+		}
+
+		if k+1 < hi {
+			bulge[0], bulge[1] = H[k+1][k], H[k+2][k]
+			bulge[2] = 0
+			if k+3 <= hi {
+				bulge[2] = H[k+3][k]
+			}
+		}
+	}
+}
+
+// householderVector returns v and beta with (I - beta*v*v^T) * x = alpha * e1.
+// beta is 0 if x is already a multiple of e1.
+func householderVector(x []float64) ([]float64, float64) {
+	v := make([]float64, len(x))
+	copy(v, x)
+	if norm2(v[1:]) == 0 {
+		return v, 0
+	}
+
+	alpha := -math.Copysign(norm2(x), x[0])
+	v[0] -= alpha
+
+	return v, 2 / dot(v, v)
+}
+
+// eigenvalues2x2 returns the eigenvalues of [[a, b], [c, d]], the one closer to
+// a first for a real pair and the one with the positive imaginary part first for
+// a complex pair
+func eigenvalues2x2(a, b, c, d float64) (complex128, complex128) {
+	mean := (a + d) / 2
+	half := (a - d) / 2
+	discriminant := half*half + b*c
+	if discriminant < 0 {
+		imag := math.Sqrt(-discriminant)
+		return complex(mean, imag), complex(mean, -imag)
+	}
+
+	// the root with the sign of mean is a sum without cancellation, the other
+	// one comes from the determinant, the product of the two
+	root := math.Sqrt(discriminant)
+	first := mean + math.Copysign(root, mean)
+	second := mean - math.Copysign(root, mean)
+	if first != 0 {
+		second = (a*d - b*c) / first
+	}
+	if math.Abs(second-a) < math.Abs(first-a) {
+		first, second = second, first
+	}
+
+	return complex(first, 0), complex(second, 0)
+}
@@ -0,0 +1,222 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// companionMatrix returns a matrix whose eigenvalues are the given roots
+func companionMatrix(roots []float64) [][]float64 {
+	// coefficients of (x - r0)(x - r1)... from the highest power down
+	coefs := []float64{1}
+	for _, r := range roots {
+		next := make([]float64, len(coefs)+1)
+		for i, c := range coefs {
+			next[i] += c
+			next[i+1] -= c * r
+		}
+		coefs = next
+	}
+
+	n := len(roots)
+	res := make([][]float64, n)
+	for i := range res {
+		res[i] = make([]float64, n)
+		if i > 0 {
+			res[i][i-1] = 1
+		}
+	}
+	for i := 0; i < n; i++ {
+		res[0][i] = -coefs[i+1]
+	}
+
+	return res
+}
+
+// sortComplex sorts by real part and then by imaginary part
+func sortComplex(values []complex128) {
+	sort.Slice(values, func(i, j int) bool {
+		if real(values[i]) != real(values[j]) {
+			return real(values[i]) < real(values[j])
+		}
+		return imag(values[i]) < imag(values[j])
+	})
+}
+
+func TestNewHessenberg(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+	}{
+		{
+			name:   "3x3",
+			matrix: [][]float64{{4, 1, -2}, {1, 2, 0}, {-2, 0, 3}},
+		},
+		{
+			name:   "4x4 non symmetric",
+			matrix: [][]float64{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 12, 11}, {13, 15, 14, 16}},
+		},
+		{
+			name:   "5x5 with zeros",
+			matrix: [][]float64{{0, 0, 1, 0, 2}, {3, 0, 0, 1, 0}, {0, 4, 0, 0, 1}, {1, 0, 5, 0, 0}, {0, 1, 0, 6, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hess, err := NewHessenberg(Matrix{Data: tt.matrix})
+			if err != nil {
+				t.Fatalf("NewHessenberg() unexpected error: %v", err)
+			}
+
+			for i := range hess.H.Data {
+				for j := 0; j < i-1; j++ {
+					if hess.H.Data[i][j] != 0 {
+						t.Fatalf("H is not upper Hessenberg: %v", hess.H.Data)
+					}
+				}
+			}
+
+			qtq := MultiplyMatrices(TransposeMatrix(hess.Q.Data), hess.Q.Data)
+			if !areMatricesEqual(qtq, GenerateIdentityMatrix(len(tt.matrix))) {
+				t.Errorf("Q^T * Q = %v, want identity", qtq)
+			}
+
+			got := MultiplyMatrices(MultiplyMatrices(hess.Q.Data, hess.H.Data), TransposeMatrix(hess.Q.Data))
+			if !areMatricesEqual(got, tt.matrix) {
+				t.Errorf("Q * H * Q^T = %v, want %v", got, tt.matrix)
+			}
+		})
+	}
+}
+
+func TestNewHessenbergKeepsHessenbergMatrix(t *testing.T) {
+	A := [][]float64{{1, 2, 3}, {4, 5, 6}, {0, 7, 8}}
+	hess, err := NewHessenberg(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NewHessenberg() unexpected error: %v", err)
+	}
+	for i := range A {
+		for j := range A[i] {
+			if hess.H.Data[i][j] != A[i][j] {
+				t.Fatalf("NewHessenberg() H = %v, want %v", hess.H.Data, A)
+			}
+		}
+	}
+}
+
+func TestTryGetEigenvaluesFrancis(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   []complex128
+	}{
+		{
+			// an orthogonal matrix, unshifted QR leaves it unchanged
+			name:   "cyclic permutation",
+			matrix: [][]float64{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}},
+			want:   []complex128{1, complex(-0.5, math.Sqrt(3)/2), complex(-0.5, -math.Sqrt(3)/2)},
+		},
+		{
+			// the standard shifts of the trailing block are both 0, it needs the
+			// exceptional shifts to start converging
+			name: "cyclic permutation 6x6",
+			matrix: [][]float64{
+				{0, 0, 0, 0, 0, 1}, {1, 0, 0, 0, 0, 0}, {0, 1, 0, 0, 0, 0},
+				{0, 0, 1, 0, 0, 0}, {0, 0, 0, 1, 0, 0}, {0, 0, 0, 0, 1, 0},
+			},
+			want: []complex128{
+				1, -1, complex(0.5, math.Sqrt(3)/2), complex(0.5, -math.Sqrt(3)/2),
+				complex(-0.5, math.Sqrt(3)/2), complex(-0.5, -math.Sqrt(3)/2),
+			},
+		},
+		{
+			name:   "companion matrix with real roots",
+			matrix: companionMatrix([]float64{-4, -3, -2, -1, 1, 2, 3, 4, 5, 6}),
+			want:   []complex128{-4, -3, -2, -1, 1, 2, 3, 4, 5, 6},
+		},
+		{
+			// rotation by 90 degrees in two planes mixed by a similarity
+			name: "two complex pairs",
+			matrix: MultiplyMatrices(
+				MultiplyMatrices(
+					[][]float64{{1, 2, 0, 1}, {0, 1, 3, 0}, {1, 0, 1, 2}, {0, 1, 0, 1}},
+					[][]float64{{1, -2, 0, 0}, {2, 1, 0, 0}, {0, 0, 3, -1}, {0, 0, 1, 3}},
+				),
+				gaussJordanInverse([][]float64{{1, 2, 0, 1}, {0, 1, 3, 0}, {1, 0, 1, 2}, {0, 1, 0, 1}}),
+			),
+			want: []complex128{complex(1, 2), complex(1, -2), complex(3, 1), complex(3, -1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TryGetEigenvalues(tt.matrix)
+			if err != nil {
+				t.Fatalf("TryGetEigenvalues() unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("TryGetEigenvalues() = %v, want %v", got, tt.want)
+			}
+			sortComplex(got)
+			sortComplex(tt.want)
+			for i := range got {
+				if cmplx.Abs(got[i]-tt.want[i]) > 1e-8 {
+					t.Fatalf("TryGetEigenvalues() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTryGetEigenvaluesRandom(t *testing.T) {
+	// the eigenvalues of a real matrix sum to its trace, multiply to its
+	// determinant and come in conjugate pairs
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{3, 10, 40} {
+		A := randomMatrix(rng, n, n)
+		got, err := TryGetEigenvalues(A)
+		if err != nil {
+			t.Fatalf("%dx%d: TryGetEigenvalues() unexpected error: %v", n, n, err)
+		}
+
+		sum, trace := complex(0, 0), 0.0
+		logAbsProduct := 0.0
+		for i, value := range got {
+			sum += value
+			trace += A[i][i]
+			logAbsProduct += math.Log(cmplx.Abs(value))
+			if imag(value) > 0 && (i+1 == n || got[i+1] != cmplx.Conj(value)) {
+				t.Errorf("%dx%d: eigenvalue %d = %v is not followed by its conjugate", n, n, i, value)
+			}
+		}
+		if cmplx.Abs(sum-complex(trace, 0)) > 1e-9*float64(n) {
+			t.Errorf("%dx%d: sum of the eigenvalues = %v, want the trace %v", n, n, sum, trace)
+		}
+		if _, logAbsDet := GetLogDeterminant(A); math.Abs(logAbsProduct-logAbsDet) > 1e-8*float64(n) {
+			t.Errorf("%dx%d: log |product| of the eigenvalues = %v, want log |det| = %v", n, n, logAbsProduct, logAbsDet)
+		}
+	}
+}
+
+func TestTryGetEigenvaluesNoConvergence(t *testing.T) {
+	// a NaN never becomes negligible, so nothing deflates
+	A := [][]float64{{1, 2, 3}, {4, math.NaN(), 6}, {7, 8, 9}}
+	_, err := TryGetEigenvalues(A)
+	if !errors.Is(err, ErrNoConvergence) {
+		t.Fatalf("TryGetEigenvalues() error = %v, want %v", err, ErrNoConvergence)
+	}
+	var convergenceErr *ConvergenceError
+	if !errors.As(err, &convergenceErr) || convergenceErr.Iterations == 0 {
+		t.Errorf("TryGetEigenvalues() error = %#v, want a *ConvergenceError", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("GetEigenvalues() did not panic")
+		}
+	}()
+	GetEigenvalues(A)
+}
@@ -0,0 +1,402 @@
+package linearalgebra
+
+import "math"
+
+// KrylovOptions controls the iterative solvers
+type KrylovOptions struct {
+	// Tol is the relative residual ||b - A*x|| / ||b|| at which the solver stops,
+	// 1e-10 if it is 0
+	Tol float64
+	// MaxIter is the largest number of iterations, 10 * n if it is 0
+	MaxIter int
+	// X0 is the initial guess, the zero vector if it is nil
+	X0 []float64
+	// Preconditioner approximates A^-1, the solvers run unpreconditioned if it is nil
+	Preconditioner Preconditioner
+	// Restart is the number of GMRES iterations between restarts, min(n, 30) if it is 0
+	Restart int
+}
+
+// KrylovResult is the outcome of an iterative solver
+type KrylovResult struct {
+	// X is the last iterate, the solution if Converged is true
+	X []float64
+	// Iterations is the number of iterations that were run
+	Iterations int
+	// ResidualHistory[k] is the relative residual after k iterations,
+	// ResidualHistory[0] is the one of the initial guess
+	ResidualHistory []float64
+	// Converged is true if the relative residual reached Tol
+	Converged bool
+}
+
+// krylovSetup validates the arguments and fills in the defaults.
+// It returns the initial guess, its residual b - A*x and ||b||.
+func krylovSetup(op string, A LinearOperator, b []float64, opts *KrylovOptions) ([]float64, []float64, float64, error) {
+	rows, cols := A.Dims()
+	if rows != cols {
+		return nil, nil, 0, &ShapeError{Op: op, Shapes: []Shape{{Rows: rows, Cols: cols}}, Err: ErrNotSquare}
+	}
+	if len(b) != rows || (opts.X0 != nil && len(opts.X0) != cols) {
+		return nil, nil, 0, &ShapeError{
+			Op:     op,
+			Shapes: []Shape{{Rows: rows, Cols: cols}, {Rows: len(b), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	if opts.Tol <= 0 {
+		opts.Tol = 1e-10
+	}
+	if opts.MaxIter <= 0 {
+		opts.MaxIter = 10 * rows
+	}
+
+	x := make([]float64, rows)
+	if opts.X0 != nil {
+		copy(x, opts.X0)
+	}
+	r, err := residual(A, x, b)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return x, r, norm2(b), nil
+}
+
+// residual returns b - A*x
+func residual(A LinearOperator, x, b []float64) ([]float64, error) {
+	ax, err := A.MulVec(x)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]float64, len(b))
+	for i := range r {
+		r[i] = b[i] - ax[i]
+	}
+
+	return r, nil
+}
+
+// applyPreconditioner returns M^-1 * r, or a copy of r without a preconditioner
+func applyPreconditioner(m Preconditioner, r []float64) ([]float64, error) {
+	if m == nil {
+		z := make([]float64, len(r))
+		copy(z, r)
+		return z, nil
+	}
+
+	return m.Apply(r)
+}
+
+// dot returns the dot product of two vectors of the same length
+func dot(x, y []float64) float64 {
+	res := 0.0
+	for i := range x {
+		res += x[i] * y[i]
+	}
+
+	return res
+}
+
+// axpy sets y = y + alpha*x
+func axpy(alpha float64, x, y []float64) {
+	for i := range y {
+		y[i] += alpha * x[i]
+	}
+}
+
+// ConjugateGradient solves A * x = b for a symmetric positive definite A.
+// Every iteration needs one A * x and the error in the A norm never grows,
+// in exact arithmetic it finds the solution in at most n iterations.
+// A preconditioner must be symmetric positive definite as well, see
+// NewJacobiPreconditioner, NewSSORPreconditioner and NewIncompleteCholesky.
+// If the tolerance is not reached in MaxIter iterations it returns the last
+// iterate together with an error wrapping ErrNoConvergence. It returns
+// ErrNotPositiveDefinite if it finds a direction with p^T * A * p <= 0.
+func ConjugateGradient(A LinearOperator, b []float64, opts KrylovOptions) (KrylovResult, error) {
+	x, r, bNorm, err := krylovSetup("ConjugateGradient", A, b, &opts)
+	if err != nil {
+		return KrylovResult{}, err
+	}
+	res := KrylovResult{X: x}
+	if bNorm == 0 {
+		// the solution of A * x = 0 is 0
+		res.X = make([]float64, len(b))
+		res.ResidualHistory = []float64{0}
+		res.Converged = true
+		return res, nil
+	}
+
+	res.ResidualHistory = append(res.ResidualHistory, norm2(r)/bNorm)
+	if res.ResidualHistory[0] <= opts.Tol {
+		res.Converged = true
+		return res, nil
+	}
+
+	z, err := applyPreconditioner(opts.Preconditioner, r)
+	if err != nil {
+		return KrylovResult{}, err
+	}
+	p := make([]float64, len(z))
+	copy(p, z)
+	rz := dot(r, z)
+
+	for res.Iterations < opts.MaxIter {
+		ap, err := A.MulVec(p)
+		if err != nil {
+			return KrylovResult{}, err
+		}
+		pap := dot(p, ap)
+		if pap <= 0 {
+			return res, &ShapeError{Op: "ConjugateGradient", Shapes: []Shape{{Rows: len(b), Cols: len(b)}}, Err: ErrNotPositiveDefinite}
+		}
+
+		alpha := rz / pap
+		axpy(alpha, p, x)
+		axpy(-alpha, ap, r)
+		res.Iterations++
+
+		relative := norm2(r) / bNorm
+		res.ResidualHistory = append(res.ResidualHistory, relative)
+		if relative <= opts.Tol {
+			res.Converged = true
+			return res, nil
+		}
+
+		if z, err = applyPreconditioner(opts.Preconditioner, r); err != nil {
+			return KrylovResult{}, err
+		}
+		rzNext := dot(r, z)
+		beta := rzNext / rz
+		rz = rzNext
+		for i := range p {
+			p[i] = z[i] + beta*p[i]
+		}
+	}
+
+	return res, &ConvergenceError{Op: "ConjugateGradient", Iterations: res.Iterations}
+}
+
+// GMRES solves A * x = b for any invertible A with the restarted generalized
+// minimal residual method. It builds an orthonormal basis of the Krylov space
+// with Arnoldi and picks the x in it with the smallest residual, the least
+// squares problem is kept triangular with Givens rotations. After Restart
+// iterations the basis is thrown away and it starts again from the current x.
+// The preconditioner is applied on the right, A * M^-1 * u = b with x = M^-1 * u,
+// so the residual history is the one of the original system.
+// If the tolerance is not reached in MaxIter iterations it returns the last
+// iterate together with an error wrapping ErrNoConvergence.
+func GMRES(A LinearOperator, b []float64, opts KrylovOptions) (KrylovResult, error) {
+	x, r, bNorm, err := krylovSetup("GMRES", A, b, &opts)
+	if err != nil {
+		return KrylovResult{}, err
+	}
+	n := len(b)
+	res := KrylovResult{X: x}
+	if bNorm == 0 {
+		res.X = make([]float64, n)
+		res.ResidualHistory = []float64{0}
+		res.Converged = true
+		return res, nil
+	}
+	restart := opts.Restart
+	if restart <= 0 {
+		restart = min(n, 30)
+	}
+
+	beta := norm2(r)
+	res.ResidualHistory = append(res.ResidualHistory, beta/bNorm)
+	if beta/bNorm <= opts.Tol {
+		res.Converged = true
+		return res, nil
+	}
+
+	for res.Iterations < opts.MaxIter {
+		// V holds the Arnoldi basis, Z the preconditioned basis M^-1 * V
+		V := make([][]float64, restart+1)
+		Z := make([][]float64, restart)
+		H := make([][]float64, restart+1)
+		for i := range H {
+			H[i] = make([]float64, restart)
+		}
+		cs := make([]float64, restart)
+		sn := make([]float64, restart)
+		g := make([]float64, restart+1)
+		g[0] = beta
+
+		V[0] = make([]float64, n)
+		for i := range r {
+			V[0][i] = r[i] / beta
+		}
+
+		k := 0
+		for ; k < restart && res.Iterations < opts.MaxIter; k++ {
+			if Z[k], err = applyPreconditioner(opts.Preconditioner, V[k]); err != nil {
+				return KrylovResult{}, err
+			}
+			w, err := A.MulVec(Z[k])
+			if err != nil {
+				return KrylovResult{}, err
+			}
+
+			// modified Gram-Schmidt against the basis
+			for i := 0; i <= k; i++ {
+				H[i][k] = dot(w, V[i])
+				axpy(-H[i][k], V[i], w)
+			}
+			H[k+1][k] = norm2(w)
+			if H[k+1][k] != 0 {
+				V[k+1] = w
+				for i := range w {
+					w[i] /= H[k+1][k]
+				}
+			}
+
+			// apply the previous rotations to the new column and zero H[k+1][k]
+			for i := 0; i < k; i++ {
+				hi, hi1 := H[i][k], H[i+1][k]
+				H[i][k] = cs[i]*hi + sn[i]*hi1
+				H[i+1][k] = -sn[i]*hi + cs[i]*hi1
+			}
+			denom := math.Hypot(H[k][k], H[k+1][k])
+			cs[k], sn[k] = 1, 0
+			if denom != 0 {
+				cs[k], sn[k] = H[k][k]/denom, H[k+1][k]/denom
+			}
+			H[k][k] = denom
+			H[k+1][k] = 0
+			g[k+1] = -sn[k] * g[k]
+			g[k] = cs[k] * g[k]
+
+			res.Iterations++
+			relative := math.Abs(g[k+1]) / bNorm
+			res.ResidualHistory = append(res.ResidualHistory, relative)
+			if relative <= opts.Tol || V[k+1] == nil {
+				k++
+				break
+			}
+		}
+
+		// x += Z * y where H * y = g
+		y := make([]float64, k)
+		for i := k - 1; i >= 0; i-- {
+			y[i] = g[i]
+			for j := i + 1; j < k; j++ {
+				y[i] -= H[i][j] * y[j]
+			}
+			y[i] /= H[i][i]
+		}
+		for j := 0; j < k; j++ {
+			axpy(y[j], Z[j], x)
+		}
+
+		if r, err = residual(A, x, b); err != nil {
+			return KrylovResult{}, err
+		}
+		beta = norm2(r)
+		// replace the estimate of the last iteration with the true residual
+		res.ResidualHistory[len(res.ResidualHistory)-1] = beta / bNorm
+		if beta/bNorm <= opts.Tol {
+			res.Converged = true
+			return res, nil
+		}
+	}
+
+	return res, &ConvergenceError{Op: "GMRES", Iterations: res.Iterations}
+}
+
+// BiCGSTAB solves A * x = b for any invertible A with the biconjugate gradient
+// stabilized method. Like GMRES it works for non symmetric matrices, it needs
+// two A * x per iteration but only a fixed amount of memory.
+// The preconditioner is applied on the right.
+// If the tolerance is not reached in MaxIter iterations it returns the last
+// iterate together with an error wrapping ErrNoConvergence. A breakdown,
+// where rho, omega or rHat . v becomes 0 before convergence, is reported the
+// same way.
+func BiCGSTAB(A LinearOperator, b []float64, opts KrylovOptions) (KrylovResult, error) {
+	x, r, bNorm, err := krylovSetup("BiCGSTAB", A, b, &opts)
+	if err != nil {
+		return KrylovResult{}, err
+	}
+	n := len(b)
+	res := KrylovResult{X: x}
+	if bNorm == 0 {
+		res.X = make([]float64, n)
+		res.ResidualHistory = []float64{0}
+		res.Converged = true
+		return res, nil
+	}
+
+	res.ResidualHistory = append(res.ResidualHistory, norm2(r)/bNorm)
+	if res.ResidualHistory[0] <= opts.Tol {
+		res.Converged = true
+		return res, nil
+	}
+
+	rHat := make([]float64, n)
+	copy(rHat, r)
+	rho, alpha, omega := 1.0, 1.0, 1.0
+	v := make([]float64, n)
+	p := make([]float64, n)
+
+	for res.Iterations < opts.MaxIter {
+		rhoNext := dot(rHat, r)
+		if rhoNext == 0 || omega == 0 {
+			break
+		}
+		beta := (rhoNext / rho) * (alpha / omega)
+		rho = rhoNext
+		for i := range p {
+			p[i] = r[i] + beta*(p[i]-omega*v[i])
+		}
+
+		pHat, err := applyPreconditioner(opts.Preconditioner, p)
+		if err != nil {
+			return KrylovResult{}, err
+		}
+		if v, err = A.MulVec(pHat); err != nil {
+			return KrylovResult{}, err
+		}
+		rHatV := dot(rHat, v)
+		if rHatV == 0 {
+			break
+		}
+		alpha = rho / rHatV
+
+		// s = r - alpha * v, stored in r
+		axpy(-alpha, v, r)
+		axpy(alpha, pHat, x)
+		res.Iterations++
+		if relative := norm2(r) / bNorm; relative <= opts.Tol {
+			res.ResidualHistory = append(res.ResidualHistory, relative)
+			res.Converged = true
+			return res, nil
+		}
+
+		sHat, err := applyPreconditioner(opts.Preconditioner, r)
+		if err != nil {
+			return KrylovResult{}, err
+		}
+		t, err := A.MulVec(sHat)
+		if err != nil {
+			return KrylovResult{}, err
+		}
+		tt := dot(t, t)
+		omega = 0
+		if tt != 0 {
+			omega = dot(t, r) / tt
+		}
+		axpy(omega, sHat, x)
+		axpy(-omega, t, r)
+
+		relative := norm2(r) / bNorm
+		res.ResidualHistory = append(res.ResidualHistory, relative)
+		if relative <= opts.Tol {
+			res.Converged = true
+			return res, nil
+		}
+	}
+
+	return res, &ConvergenceError{Op: "BiCGSTAB", Iterations: res.Iterations}
+}
@@ -0,0 +1,260 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// laplacian2D returns the n^2 x n^2 five point finite difference matrix on an n x n grid
+func laplacian2D(n int) CSR {
+	coo := NewCOO(n*n, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			row := i*n + j
+			coo.Add(row, row, 4)
+			if i > 0 {
+				coo.Add(row, row-n, -1)
+			}
+			if i < n-1 {
+				coo.Add(row, row+n, -1)
+			}
+			if j > 0 {
+				coo.Add(row, row-1, -1)
+			}
+			if j < n-1 {
+				coo.Add(row, row+1, -1)
+			}
+		}
+	}
+
+	return coo.ToCSR()
+}
+
+// convectionDiffusion returns the non symmetric n x n matrix tridiag(-1-c, 3, -1+c)
+func convectionDiffusion(n int, c float64) CSR {
+	coo := NewCOO(n, n)
+	for i := 0; i < n; i++ {
+		coo.Add(i, i, 3)
+		if i > 0 {
+			coo.Add(i, i-1, -1-c)
+		}
+		if i < n-1 {
+			coo.Add(i, i+1, -1+c)
+		}
+	}
+
+	return coo.ToCSR()
+}
+
+// checkSolution fails the test if ||b - A*x|| / ||b|| is larger than tol
+func checkSolution(t *testing.T, A LinearOperator, x, b []float64, tol float64) {
+	t.Helper()
+	r, err := residual(A, x, b)
+	if err != nil {
+		t.Fatalf("residual() unexpected error: %v", err)
+	}
+	if relative := norm2(r) / norm2(b); relative > tol {
+		t.Errorf("relative residual = %v, want <= %v", relative, tol)
+	}
+}
+
+// checkHistory fails the test if the residual history does not match the result
+func checkHistory(t *testing.T, res KrylovResult, tol float64) {
+	t.Helper()
+	if len(res.ResidualHistory) != res.Iterations+1 {
+		t.Errorf("len(ResidualHistory) = %d, want Iterations+1 = %d", len(res.ResidualHistory), res.Iterations+1)
+	}
+	if res.ResidualHistory[0] != 1 {
+		t.Errorf("ResidualHistory[0] = %v, want 1 for a zero initial guess", res.ResidualHistory[0])
+	}
+	if last := res.ResidualHistory[len(res.ResidualHistory)-1]; last > tol {
+		t.Errorf("last residual = %v, want <= %v", last, tol)
+	}
+}
+
+func TestConjugateGradient(t *testing.T) {
+	A := laplacian2D(20)
+	n, _ := A.Dims()
+	b := make([]float64, n)
+	for i := range b {
+		b[i] = math.Sin(float64(i))
+	}
+
+	jacobi, err := NewJacobiPreconditioner(A)
+	if err != nil {
+		t.Fatalf("NewJacobiPreconditioner() unexpected error: %v", err)
+	}
+	ssor, err := NewSSORPreconditioner(A, 1.5)
+	if err != nil {
+		t.Fatalf("NewSSORPreconditioner() unexpected error: %v", err)
+	}
+	ic, err := NewIncompleteCholesky(A)
+	if err != nil {
+		t.Fatalf("NewIncompleteCholesky() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		preconditioner Preconditioner
+	}{
+		{name: "no preconditioner"},
+		{name: "Jacobi", preconditioner: jacobi},
+		{name: "SSOR", preconditioner: ssor},
+		{name: "incomplete Cholesky", preconditioner: ic},
+	}
+	iterations := map[string]int{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ConjugateGradient(A, b, KrylovOptions{Tol: 1e-10, Preconditioner: tt.preconditioner})
+			if err != nil {
+				t.Fatalf("ConjugateGradient() unexpected error: %v", err)
+			}
+			if !res.Converged {
+				t.Errorf("ConjugateGradient() did not converge")
+			}
+			checkHistory(t, res, 1e-10)
+			checkSolution(t, A, res.X, b, 1e-9)
+			iterations[tt.name] = res.Iterations
+		})
+	}
+
+	if iterations["incomplete Cholesky"] >= iterations["no preconditioner"] || iterations["SSOR"] >= iterations["no preconditioner"] {
+		t.Errorf("preconditioning did not reduce the iterations: %v", iterations)
+	}
+}
+
+func TestConjugateGradientDenseMatrix(t *testing.T) {
+	A := Matrix{Data: [][]float64{{4, 1}, {1, 3}}}
+	b := []float64{1, 2}
+	res, err := ConjugateGradient(A, b, KrylovOptions{})
+	if err != nil {
+		t.Fatalf("ConjugateGradient() unexpected error: %v", err)
+	}
+	// exact solution (1/11, 7/11) in at most n = 2 iterations
+	if res.Iterations > 2 || math.Abs(res.X[0]-1.0/11) > 1e-12 || math.Abs(res.X[1]-7.0/11) > 1e-12 {
+		t.Errorf("ConjugateGradient() = %v after %d iterations, want [1/11 7/11]", res.X, res.Iterations)
+	}
+}
+
+func TestNonSymmetricSolvers(t *testing.T) {
+	A := convectionDiffusion(200, 0.5)
+	n, _ := A.Dims()
+	b := make([]float64, n)
+	for i := range b {
+		b[i] = 1 + float64(i%7)
+	}
+	jacobi, err := NewJacobiPreconditioner(A)
+	if err != nil {
+		t.Fatalf("NewJacobiPreconditioner() unexpected error: %v", err)
+	}
+
+	solvers := map[string]func(LinearOperator, []float64, KrylovOptions) (KrylovResult, error){
+		"GMRES":    GMRES,
+		"BiCGSTAB": BiCGSTAB,
+	}
+	for name, solve := range solvers {
+		for _, preconditioner := range []Preconditioner{nil, jacobi} {
+			t.Run(name, func(t *testing.T) {
+				res, err := solve(A, b, KrylovOptions{Tol: 1e-10, Preconditioner: preconditioner})
+				if err != nil {
+					t.Fatalf("%s() unexpected error: %v", name, err)
+				}
+				checkHistory(t, res, 1e-10)
+				checkSolution(t, A, res.X, b, 1e-9)
+			})
+		}
+	}
+
+	// GMRES with a restart shorter than the needed iterations
+	res, err := GMRES(A, b, KrylovOptions{Tol: 1e-10, Restart: 5})
+	if err != nil {
+		t.Fatalf("GMRES() unexpected error: %v", err)
+	}
+	checkSolution(t, A, res.X, b, 1e-9)
+}
+
+func TestKrylovMatchesLU(t *testing.T) {
+	A := [][]float64{{4, -1, 0, 1}, {2, 5, -1, 0}, {0, 1, 6, -2}, {1, 0, 2, 7}}
+	b := []float64{1, -2, 3, 4}
+	lu, err := NewLU(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NewLU() unexpected error: %v", err)
+	}
+	want, err := lu.Solve(b)
+	if err != nil {
+		t.Fatalf("LU.Solve() unexpected error: %v", err)
+	}
+
+	for name, solve := range map[string]func(LinearOperator, []float64, KrylovOptions) (KrylovResult, error){
+		"GMRES":    GMRES,
+		"BiCGSTAB": BiCGSTAB,
+	} {
+		res, err := solve(Matrix{Data: A}, b, KrylovOptions{Tol: 1e-12})
+		if err != nil {
+			t.Fatalf("%s() unexpected error: %v", name, err)
+		}
+		for i := range want {
+			if math.Abs(res.X[i]-want[i]) > 1e-10 {
+				t.Errorf("%s() = %v, want %v", name, res.X, want)
+				break
+			}
+		}
+	}
+}
+
+func TestKrylovErrors(t *testing.T) {
+	A := laplacian2D(10)
+	n, _ := A.Dims()
+	b := make([]float64, n)
+	for i := range b {
+		b[i] = 1
+	}
+
+	for name, solve := range map[string]func(LinearOperator, []float64, KrylovOptions) (KrylovResult, error){
+		"ConjugateGradient": ConjugateGradient,
+		"GMRES":             GMRES,
+		"BiCGSTAB":          BiCGSTAB,
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := solve(A, b, KrylovOptions{MaxIter: 2})
+			if !errors.Is(err, ErrNoConvergence) {
+				t.Errorf("%s() error = %v, want %v", name, err, ErrNoConvergence)
+			}
+			if res.Converged || res.Iterations != 2 || len(res.X) != n {
+				t.Errorf("%s() = %d iterations, converged %v, want the last iterate after 2", name, res.Iterations, res.Converged)
+			}
+
+			if _, err := solve(A, b[:3], KrylovOptions{}); !errors.Is(err, ErrDimensionMismatch) {
+				t.Errorf("%s() error = %v, want %v", name, err, ErrDimensionMismatch)
+			}
+			if _, err := solve(Matrix{Data: [][]float64{{1, 2}}}, []float64{1}, KrylovOptions{}); !errors.Is(err, ErrNotSquare) {
+				t.Errorf("%s() error = %v, want %v", name, err, ErrNotSquare)
+			}
+
+			// A * x = 0 is solved by x = 0 right away
+			res, err = solve(A, make([]float64, n), KrylovOptions{})
+			if err != nil || !res.Converged || res.Iterations != 0 {
+				t.Errorf("%s() with b = 0: %v, converged %v after %d iterations", name, err, res.Converged, res.Iterations)
+			}
+		})
+	}
+
+	// A * b is orthogonal to b, so the first alpha would divide by 0
+	rotation := Matrix{Data: [][]float64{{0, 1}, {-1, 0}}}
+	res, err := BiCGSTAB(rotation, []float64{1, 0}, KrylovOptions{})
+	if !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("BiCGSTAB() error = %v, want %v", err, ErrNoConvergence)
+	}
+	for _, v := range res.X {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Errorf("BiCGSTAB() after a breakdown X = %v, want the last finite iterate", res.X)
+			break
+		}
+	}
+
+	indefinite := Matrix{Data: [][]float64{{1, 0}, {0, -1}}}
+	if _, err := ConjugateGradient(indefinite, []float64{1, 1}, KrylovOptions{}); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("ConjugateGradient() error = %v, want %v", err, ErrNotPositiveDefinite)
+	}
+}
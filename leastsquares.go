@@ -0,0 +1,215 @@
+package linearalgebra
+
+import "math"
+
+// LeastSquaresMethod selects the factorization used by LeastSquares
+type LeastSquaresMethod int
+
+const (
+	// LeastSquaresQR uses the QR factorization with column pivoting.
+	// It is the faster option, the rank comes from the diagonal of R and a
+	// rank deficient A gets the basic solution where the entries for the
+	// dependent columns are 0. The singular values are those of R, which is
+	// at most n x n, instead of the ones of the whole A.
+	LeastSquaresQR LeastSquaresMethod = iota
+	// LeastSquaresSVD uses the singular value decomposition.
+	// It is slower but a rank deficient A gets the minimum norm solution.
+	LeastSquaresSVD
+)
+
+// LeastSquaresResult is the solution of min ||A*x - b||
+type LeastSquaresResult struct {
+	// X is the solution
+	X []float64
+	// ResidualNorm is ||A*X - b||
+	ResidualNorm float64
+	// Rank is the numerical rank of A
+	Rank int
+	// SingularValues of A in descending order
+	SingularValues []float64
+}
+
+// LeastSquares finds the x that minimizes ||A*x - b||.
+// When A is square and invertible this is the solution of A*x = b, when A has
+// more rows than columns it is the best fit of an overdetermined system.
+// Unlike the normal equations (A^T*A)^-1 * A^T * b it never squares the condition number of A.
+func LeastSquares(A Matrix, b []float64, method LeastSquaresMethod) (LeastSquaresResult, error) {
+	if err := checkRectangular("LeastSquares", A.Data); err != nil {
+		return LeastSquaresResult{}, err
+	}
+	shape := GetShape(A.Data)
+	if len(b) != shape.Rows {
+		return LeastSquaresResult{}, &ShapeError{
+			Op:     "LeastSquares",
+			Shapes: []Shape{shape, {Rows: len(b), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	var res LeastSquaresResult
+	switch method {
+	case LeastSquaresSVD:
+		U, sigma, V, err := jacobiSVD(A.Data)
+		if err != nil {
+			return LeastSquaresResult{}, err
+		}
+		res.SingularValues = sigma
+		// x = V * S^+ * U^T * b, the pseudo inverse ignores the singular values below the rank
+		res.Rank = numericalRank(sigma, shape.Rows, shape.Cols)
+		res.X = make([]float64, shape.Cols)
+		for k := 0; k < res.Rank; k++ {
+			utb := 0.0
+			for i := range b {
+				utb += U[i][k] * b[i]
+			}
+			coef := utb / sigma[k]
+			for j := range res.X {
+				res.X[j] += coef * V[j][k]
+			}
+		}
+	default:
+		qr, err := NewQRWithPivoting(A, QRThin)
+		if err != nil {
+			return LeastSquaresResult{}, err
+		}
+		x, err := qr.Solve(b)
+		if err != nil {
+			return LeastSquaresResult{}, err
+		}
+		// A * P = Q * R and Q has orthonormal columns, so A and R have the same singular values
+		_, sigma, _, err := jacobiSVD(qr.R.Data)
+		if err != nil {
+			return LeastSquaresResult{}, err
+		}
+		res.SingularValues = sigma
+		res.X = x
+		res.Rank = qr.Rank
+	}
+
+	residual := make([]float64, len(b))
+	for i := range A.Data {
+		residual[i] = -b[i]
+		for j := range A.Data[i] {
+			residual[i] += A.Data[i][j] * res.X[j]
+		}
+	}
+	res.ResidualNorm = norm2(residual)
+
+	return res, nil
+}
+
+// LinearRegressionResult is an ordinary least squares fit
+// y = Intercept + Coefficients[0]*x0 + Coefficients[1]*x1 + ...
+type LinearRegressionResult struct {
+	Coefficients []float64
+	Intercept    float64
+	// RSquared is the fraction of the variance of y explained by the fit
+	RSquared float64
+	// StandardErrors of the coefficients, in the same order as Coefficients
+	StandardErrors []float64
+	// InterceptStandardError is the standard error of the intercept
+	InterceptStandardError float64
+}
+
+// LinearRegression fits y against the columns of the matrix with an intercept,
+// every row of the matrix is an observation and every column a feature.
+// The standard errors are sqrt(diag(s^2 * (X^T*X)^-1)) with s^2 = RSS / (n - p - 1),
+// they are NaN when the fit is exact because there are as many observations as parameters.
+// It returns ErrSingular when the features are linearly dependent or there are
+// fewer observations than parameters.
+func (m Matrix) LinearRegression(y []float64) (LinearRegressionResult, error) {
+	if err := checkRectangular("LinearRegression", m.Data); err != nil {
+		return LinearRegressionResult{}, err
+	}
+	shape := GetShape(m.Data)
+	if len(y) != shape.Rows {
+		return LinearRegressionResult{}, &ShapeError{
+			Op:     "LinearRegression",
+			Shapes: []Shape{shape, {Rows: len(y), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	// design matrix with a column of 1s for the intercept
+	n, p := shape.Rows, shape.Cols
+	X := make([][]float64, n)
+	for i := range X {
+		X[i] = make([]float64, p+1)
+		X[i][0] = 1
+		copy(X[i][1:], m.Data[i])
+	}
+	if n < p+1 {
+		return LinearRegressionResult{}, newShapeError("LinearRegression", ErrSingular, X)
+	}
+
+	qr, err := NewQR(Matrix{Data: X}, QRThin)
+	if err != nil {
+		return LinearRegressionResult{}, err
+	}
+	beta, err := qr.Solve(y)
+	if err != nil {
+		return LinearRegressionResult{}, err
+	}
+
+	meanY := GetMean(y)
+	rss, tss := 0.0, 0.0
+	for i := range X {
+		fitted := 0.0
+		for j := range X[i] {
+			fitted += X[i][j] * beta[j]
+		}
+		rss += (y[i] - fitted) * (y[i] - fitted)
+		tss += (y[i] - meanY) * (y[i] - meanY)
+	}
+
+	rSquared := 1.0
+	if tss != 0 {
+		rSquared = 1 - rss/tss
+	}
+
+	// (X^T*X)^-1 = R^-1 * R^-T, so the variance of beta_j is s^2 times
+	// the squared norm of row j of R^-1
+	dof := float64(n - p - 1)
+	s2 := math.NaN()
+	if dof > 0 {
+		s2 = rss / dof
+	}
+	rInv := invertUpperTriangular(qr.R.Data)
+	standardErrors := make([]float64, p+1)
+	for j := range standardErrors {
+		standardErrors[j] = math.Sqrt(s2) * norm2(rInv[j])
+	}
+
+	return LinearRegressionResult{
+		Coefficients:           beta[1:],
+		Intercept:              beta[0],
+		RSquared:               rSquared,
+		StandardErrors:         standardErrors[1:],
+		InterceptStandardError: standardErrors[0],
+	}, nil
+}
+
+// invertUpperTriangular returns the inverse of an upper triangular matrix
+// with a non zero diagonal by back substitution on every column of the identity
+func invertUpperTriangular(R [][]float64) [][]float64 {
+	n := len(R)
+	inv := make([][]float64, n)
+	for i := range inv {
+		inv[i] = make([]float64, n)
+	}
+
+	for col := 0; col < n; col++ {
+		for i := col; i >= 0; i-- {
+			sum := 0.0
+			if i == col {
+				sum = 1
+			}
+			for j := i + 1; j <= col; j++ {
+				sum -= R[i][j] * inv[j][col]
+			}
+			inv[i][col] = sum / R[i][i]
+		}
+	}
+
+	return inv
+}
@@ -0,0 +1,281 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLeastSquares(t *testing.T) {
+	tests := []struct {
+		name             string
+		A                [][]float64
+		b                []float64
+		method           LeastSquaresMethod
+		want             []float64
+		wantRank         int
+		wantResidualNorm float64
+	}{
+		{
+			name:     "square system with QR",
+			A:        [][]float64{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}},
+			b:        []float64{8, -11, -3},
+			method:   LeastSquaresQR,
+			want:     []float64{2, 3, -1},
+			wantRank: 3,
+		},
+		{
+			name:     "square system with SVD",
+			A:        [][]float64{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}},
+			b:        []float64{8, -11, -3},
+			method:   LeastSquaresSVD,
+			want:     []float64{2, 3, -1},
+			wantRank: 3,
+		},
+		{
+			// fit y = c0 + c1*x through (0, 1), (1, 3), (2, 4), (3, 4)
+			// residuals are -0.5, 0.5, 0.5, -0.5
+			name:             "line fit with QR",
+			A:                [][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
+			b:                []float64{1, 3, 4, 4},
+			method:           LeastSquaresQR,
+			want:             []float64{1.5, 1},
+			wantRank:         2,
+			wantResidualNorm: 1,
+		},
+		{
+			name:             "line fit with SVD",
+			A:                [][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
+			b:                []float64{1, 3, 4, 4},
+			method:           LeastSquaresSVD,
+			want:             []float64{1.5, 1},
+			wantRank:         2,
+			wantResidualNorm: 1,
+		},
+		{
+			// x + y = 2 has infinitely many solutions, the minimum norm one is (1, 1)
+			name:     "underdetermined minimum norm with SVD",
+			A:        [][]float64{{1, 1}},
+			b:        []float64{2},
+			method:   LeastSquaresSVD,
+			want:     []float64{1, 1},
+			wantRank: 1,
+		},
+		{
+			name:     "rank deficient minimum norm with SVD",
+			A:        [][]float64{{1, 2}, {2, 4}, {3, 6}},
+			b:        []float64{5, 10, 15},
+			method:   LeastSquaresSVD,
+			want:     []float64{1, 2},
+			wantRank: 1,
+		},
+		{
+			name:     "rank deficient basic solution with QR",
+			A:        [][]float64{{1, 2}, {2, 4}, {3, 6}},
+			b:        []float64{5, 10, 15},
+			method:   LeastSquaresQR,
+			want:     []float64{0, 2.5},
+			wantRank: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LeastSquares(Matrix{Data: tt.A}, tt.b, tt.method)
+			if err != nil {
+				t.Fatalf("LeastSquares() unexpected error: %v", err)
+			}
+			for i := range tt.want {
+				if math.Abs(got.X[i]-tt.want[i]) > 1e-10 {
+					t.Errorf("LeastSquares() X = %v, want %v", got.X, tt.want)
+					break
+				}
+			}
+			if got.Rank != tt.wantRank {
+				t.Errorf("LeastSquares() Rank = %d, want %d", got.Rank, tt.wantRank)
+			}
+			if math.Abs(got.ResidualNorm-tt.wantResidualNorm) > 1e-10 {
+				t.Errorf("LeastSquares() ResidualNorm = %v, want %v", got.ResidualNorm, tt.wantResidualNorm)
+			}
+			if len(got.SingularValues) != min(len(tt.A), len(tt.A[0])) {
+				t.Errorf("LeastSquares() returned %d singular values", len(got.SingularValues))
+			}
+			for i := 1; i < len(got.SingularValues); i++ {
+				if got.SingularValues[i] > got.SingularValues[i-1] {
+					t.Errorf("singular values not sorted: %v", got.SingularValues)
+				}
+			}
+		})
+	}
+}
+
+func TestLeastSquaresSingularValuesAgree(t *testing.T) {
+	matrices := [][][]float64{
+		{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}},
+		{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
+		{{1, 2}, {2, 4}, {3, 6}},
+		{{1, 1}},
+	}
+	for _, A := range matrices {
+		b := make([]float64, len(A))
+		qr, err := LeastSquares(Matrix{Data: A}, b, LeastSquaresQR)
+		if err != nil {
+			t.Fatalf("LeastSquares() with QR unexpected error: %v", err)
+		}
+		svd, err := LeastSquares(Matrix{Data: A}, b, LeastSquaresSVD)
+		if err != nil {
+			t.Fatalf("LeastSquares() with SVD unexpected error: %v", err)
+		}
+		if len(qr.SingularValues) != len(svd.SingularValues) {
+			t.Fatalf("singular values of %v: QR %v, SVD %v", A, qr.SingularValues, svd.SingularValues)
+		}
+		for i := range qr.SingularValues {
+			if math.Abs(qr.SingularValues[i]-svd.SingularValues[i]) > 1e-10 {
+				t.Errorf("singular values of %v: QR %v, SVD %v", A, qr.SingularValues, svd.SingularValues)
+				break
+			}
+		}
+	}
+}
+
+func TestLeastSquaresErrors(t *testing.T) {
+	_, err := LeastSquares(Matrix{Data: [][]float64{{1, 2}, {3, 4}}}, []float64{1}, LeastSquaresQR)
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("LeastSquares() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestLeastSquaresMatchesNormalEquations(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	// predict the first column from the next three
+	A := make([][]float64, len(m.Data))
+	b := make([]float64, len(m.Data))
+	for i, row := range m.Data {
+		A[i] = []float64{1, row[1], row[2], row[3]}
+		b[i] = row[0]
+	}
+
+	// x = (A^T*A)^-1 * A^T * b
+	At := TransposeMatrix(A)
+	AtA := MultiplyMatrices(At, A)
+	Atb := MultiplyMatrices(At, RowToColumnVector(b))
+	want := MultiplyMatrices(GetInverseMatrixByDeterminant(AtA), Atb)
+
+	for _, method := range []LeastSquaresMethod{LeastSquaresQR, LeastSquaresSVD} {
+		got, err := LeastSquares(Matrix{Data: A}, b, method)
+		if err != nil {
+			t.Fatalf("LeastSquares() unexpected error: %v", err)
+		}
+		for i := range got.X {
+			if math.Abs(got.X[i]-want[i][0]) > 1e-6*math.Max(1, math.Abs(want[i][0])) {
+				t.Errorf("method %d: LeastSquares() X = %v, want %v", method, got.X, TransposeMatrix(want)[0])
+				break
+			}
+		}
+	}
+}
+
+func TestMatrix_LinearRegression(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6}
+	y := []float64{2.1, 3.9, 6.2, 7.8, 10.1, 12.2}
+
+	// closed form simple regression
+	n := float64(len(x))
+	meanX, meanY := GetMean(x), GetMean(y)
+	sxx, sxy, tss := 0.0, 0.0, 0.0
+	for i := range x {
+		sxx += (x[i] - meanX) * (x[i] - meanX)
+		sxy += (x[i] - meanX) * (y[i] - meanY)
+		tss += (y[i] - meanY) * (y[i] - meanY)
+	}
+	slope := sxy / sxx
+	intercept := meanY - slope*meanX
+	rss := 0.0
+	for i := range x {
+		r := y[i] - intercept - slope*x[i]
+		rss += r * r
+	}
+	s2 := rss / (n - 2)
+
+	got, err := Matrix{Data: RowToColumnVector(x)}.LinearRegression(y)
+	if err != nil {
+		t.Fatalf("LinearRegression() unexpected error: %v", err)
+	}
+
+	checks := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"slope", got.Coefficients[0], slope},
+		{"intercept", got.Intercept, intercept},
+		{"r squared", got.RSquared, 1 - rss/tss},
+		{"slope standard error", got.StandardErrors[0], math.Sqrt(s2 / sxx)},
+		{"intercept standard error", got.InterceptStandardError, math.Sqrt(s2 * (1/n + meanX*meanX/sxx))},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-10 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestMatrix_LinearRegressionMultipleFeatures(t *testing.T) {
+	// y = 1 + 2*x0 - 3*x1 exactly, so the fit is perfect
+	X := [][]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {2, 1}, {3, 5}}
+	y := make([]float64, len(X))
+	for i := range X {
+		y[i] = 1 + 2*X[i][0] - 3*X[i][1]
+	}
+
+	got, err := Matrix{Data: X}.LinearRegression(y)
+	if err != nil {
+		t.Fatalf("LinearRegression() unexpected error: %v", err)
+	}
+	if math.Abs(got.Intercept-1) > 1e-10 || math.Abs(got.Coefficients[0]-2) > 1e-10 || math.Abs(got.Coefficients[1]+3) > 1e-10 {
+		t.Errorf("LinearRegression() = %v + %v, want 1 + [2 -3]", got.Intercept, got.Coefficients)
+	}
+	if math.Abs(got.RSquared-1) > 1e-12 {
+		t.Errorf("LinearRegression() RSquared = %v, want 1", got.RSquared)
+	}
+	for _, se := range got.StandardErrors {
+		if se > 1e-6 {
+			t.Errorf("LinearRegression() StandardErrors = %v, want 0 for a perfect fit", got.StandardErrors)
+		}
+	}
+}
+
+func TestMatrix_LinearRegressionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		X       [][]float64
+		y       []float64
+		wantErr error
+	}{
+		{
+			name:    "y has the wrong length",
+			X:       [][]float64{{1}, {2}, {3}},
+			y:       []float64{1, 2},
+			wantErr: ErrDimensionMismatch,
+		},
+		{
+			name:    "dependent features",
+			X:       [][]float64{{1, 2}, {2, 4}, {3, 6}, {4, 8}},
+			y:       []float64{1, 2, 3, 4},
+			wantErr: ErrSingular,
+		},
+		{
+			name:    "fewer observations than parameters",
+			X:       [][]float64{{1, 2, 3}},
+			y:       []float64{1},
+			wantErr: ErrSingular,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Matrix{Data: tt.X}.LinearRegression(tt.y)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("LinearRegression() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package linearalgebra
+
+import "math"
+
+// LU is the LU factorization with partial pivoting of a square matrix A
+// P * A = L * U
+// where P is a permutation matrix, L is unit lower triangular and U is upper triangular.
+// Factor A once with NewLU and then reuse it to solve against many right hand sides.
+type LU struct {
+	// P is the permutation matrix that records the row swaps
+	P Matrix
+	// L is unit lower triangular, it holds the multipliers used in the elimination
+	L Matrix
+	// U is upper triangular, it is the result of the elimination
+	U Matrix
+	// Pivots[i] is the row of A that ends up in row i of P * A
+	Pivots []int
+	// PivotGrowth is max|U| / max|A|, the growth factor of the elimination.
+	// Values much larger than 1 mean the factorization lost accuracy.
+	PivotGrowth float64
+
+	// lu holds L below the diagonal and U on and above the diagonal
+	lu   [][]float64
+	sign float64
+	// norm1 is the 1-norm of A, used by CondEstimate
+	norm1 float64
+}
+
+// NewLU computes the LU factorization of a square matrix using Gaussian
+// elimination with partial pivoting: at each step the row with the largest
+// absolute value in the pivot column is swapped into the pivot position.
+// A singular matrix can still be factored, but Solve and Inverse will return ErrSingular.
+func NewLU(m Matrix) (LU, error) {
+	if err := checkSquare("NewLU", m.Data); err != nil {
+		return LU{}, err
+	}
+
+	n := len(m.Data)
+	lu := CopyMatrix(m.Data)
+	pivots := make([]int, n)
+	for i := range pivots {
+		pivots[i] = i
+	}
+	sign := 1.0
+	maxA := maxAbsEntry(lu)
+	norm1, _ := m.Norm(NormOne)
+
+	for col := 0; col < n; col++ {
+		// find the row with the largest entry in this column
+		bestRow := col
+		bestVal := math.Abs(lu[col][col])
+		for r := col + 1; r < n; r++ {
+			if v := math.Abs(lu[r][col]); v > bestVal {
+				bestVal = v
+				bestRow = r
+			}
+		}
+
+		if bestRow != col {
+			lu[col], lu[bestRow] = lu[bestRow], lu[col]
+			pivots[col], pivots[bestRow] = pivots[bestRow], pivots[col]
+			sign = -sign
+		}
+
+		pivot := lu[col][col]
+		if pivot == 0 {
+			// the column is already 0 below the diagonal, nothing to eliminate
+			continue
+		}
+
+		for r := col + 1; r < n; r++ {
+			factor := lu[r][col] / pivot
+			lu[r][col] = factor
+			if factor == 0 {
+				continue
+			}
+			for j := col + 1; j < n; j++ {
+				lu[r][j] -= factor * lu[col][j]
+			}
+		}
+	}
+
+	// a pivot is computed as a_ii minus the products l_ik * u_ki, so its rounding
+	// error is bounded by n * eps * (|a_ii| + sum |l_ik| * |u_ki|). A pivot under
+	// that bound is cancellation noise of an exactly singular matrix, make it 0
+	// so Det, LogDet and IsSingular see it. The bound only depends on the entries
+	// that met in the elimination, a matrix like diag(1e20, 1) keeps both pivots.
+	for i := 0; i < n; i++ {
+		scale := math.Abs(lu[i][i])
+		for k := 0; k < i; k++ {
+			scale += math.Abs(lu[i][k]) * math.Abs(lu[k][i])
+		}
+		if math.Abs(lu[i][i]) <= float64(n)*machineEpsilon*scale {
+			lu[i][i] = 0
+		}
+	}
+
+	L := GenerateIdentityMatrix(n)
+	U := make([][]float64, n)
+	P := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		U[i] = make([]float64, n)
+		P[i] = make([]float64, n)
+		P[i][pivots[i]] = 1
+		for j := 0; j < n; j++ {
+			if j < i {
+				L[i][j] = lu[i][j]
+			} else {
+				U[i][j] = lu[i][j]
+			}
+		}
+	}
+
+	growth := 0.0
+	if maxA > 0 {
+		growth = maxAbsEntry(U) / maxA
+	}
+
+	return LU{
+		P:           Matrix{Data: P},
+		L:           Matrix{Data: L},
+		U:           Matrix{Data: U},
+		Pivots:      pivots,
+		PivotGrowth: growth,
+		lu:          lu,
+		sign:        sign,
+		norm1:       norm1,
+	}, nil
+}
+
+// machineEpsilon is the distance between 1 and the next float64
+const machineEpsilon = 2.220446049250313e-16
+
+// maxAbsEntry returns the largest absolute value in the matrix
+func maxAbsEntry(matrix [][]float64) float64 {
+	res := 0.0
+	for i := range matrix {
+		for j := range matrix[i] {
+			if v := math.Abs(matrix[i][j]); v > res {
+				res = v
+			}
+		}
+	}
+
+	return res
+}
+
+// IsSingular returns true if any pivot of U is 0. NewLU sets the pivots that
+// are rounding noise to 0, so IsSingular agrees with Det.
+func (f LU) IsSingular() bool {
+	for i := range f.lu {
+		if f.lu[i][i] == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Det returns the determinant of A, the product of the pivots of U
+// with the sign flipped once for every row swap
+func (f LU) Det() float64 {
+	if len(f.lu) == 0 {
+		return 0
+	}
+
+	det := f.sign
+	for i := range f.lu {
+		det *= f.lu[i][i]
+	}
+
+	return det
+}
+
+// LogDet returns the sign and the natural log of the absolute value of the determinant.
+// Adding the logs of the pivots avoids the overflow or underflow of their product.
+// A matrix with an exactly zero pivot returns sign 0 and logAbsDet -Inf.
+func (f LU) LogDet() (sign float64, logAbsDet float64) {
+	if len(f.lu) == 0 {
+		return 0, math.Inf(-1)
+	}
+
+	sign = f.sign
+	for i := range f.lu {
+		pivot := f.lu[i][i]
+		if pivot == 0 {
+			return 0, math.Inf(-1)
+		}
+		if pivot < 0 {
+			sign = -sign
+		}
+		logAbsDet += math.Log(math.Abs(pivot))
+	}
+
+	return sign, logAbsDet
+}
+
+// Solve solves A * x = b using the factorization.
+// It applies the permutation to b, then solves L * y = P * b by forward
+// substitution and U * x = y by back substitution.
+func (f LU) Solve(b []float64) ([]float64, error) {
+	n := len(f.lu)
+	if len(b) != n {
+		return nil, &ShapeError{
+			Op:     "LU.Solve",
+			Shapes: []Shape{{Rows: n, Cols: n}, {Rows: len(b), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+	if f.IsSingular() {
+		return nil, &ShapeError{Op: "LU.Solve", Shapes: []Shape{{Rows: n, Cols: n}}, Err: ErrSingular}
+	}
+
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = b[f.Pivots[i]]
+	}
+	f.solveInPlace(x)
+
+	return x, nil
+}
+
+// solveInPlace replaces the permuted right hand side x with the solution
+func (f LU) solveInPlace(x []float64) {
+	n := len(f.lu)
+	// forward substitution, L has 1s on the diagonal
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= f.lu[i][j] * x[j]
+		}
+	}
+
+	// back substitution
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= f.lu[i][j] * x[j]
+		}
+		x[i] /= f.lu[i][i]
+	}
+}
+
+// SolveMatrix solves A * X = B for every column of B
+func (f LU) SolveMatrix(B Matrix) (Matrix, error) {
+	n := len(f.lu)
+	if len(B.Data) != n {
+		return Matrix{}, &ShapeError{
+			Op:     "LU.SolveMatrix",
+			Shapes: []Shape{{Rows: n, Cols: n}, GetShape(B.Data)},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+	if err := checkRectangular("LU.SolveMatrix", B.Data); err != nil {
+		return Matrix{}, err
+	}
+	if f.IsSingular() {
+		return Matrix{}, &ShapeError{Op: "LU.SolveMatrix", Shapes: []Shape{{Rows: n, Cols: n}}, Err: ErrSingular}
+	}
+
+	cols := GetShape(B.Data).Cols
+	X := make([][]float64, n)
+	for i := range X {
+		X[i] = make([]float64, cols)
+	}
+
+	column := make([]float64, n)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < n; i++ {
+			column[i] = B.Data[f.Pivots[i]][j]
+		}
+		f.solveInPlace(column)
+		for i := 0; i < n; i++ {
+			X[i][j] = column[i]
+		}
+	}
+
+	return Matrix{Data: X}, nil
+}
+
+// Inverse returns A^-1 by solving A * X = I
+func (f LU) Inverse() (Matrix, error) {
+	return f.SolveMatrix(Matrix{Data: GenerateIdentityMatrix(len(f.lu))})
+}
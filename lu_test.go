@@ -0,0 +1,279 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewLU(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  [][]float64
+		wantDet float64
+	}{
+		{
+			name:    "2x2",
+			matrix:  [][]float64{{1, 2}, {3, 4}},
+			wantDet: -2,
+		},
+		{
+			name:    "3x3 requires pivoting",
+			matrix:  [][]float64{{0, 2, 1}, {1, 1, 0}, {2, 0, 3}},
+			wantDet: -8,
+		},
+		{
+			name:    "3x3",
+			matrix:  [][]float64{{6, 1, 1}, {4, -2, 5}, {2, 8, 7}},
+			wantDet: -306,
+		},
+		{
+			name: "5x5",
+			matrix: [][]float64{
+				{2, 0, 1, 3, 0},
+				{1, -1, 2, 1, 0},
+				{3, 2, 0, -2, 1},
+				{4, 1, -3, 0, 2},
+				{5, 2, 1, 4, 3},
+			},
+			wantDet: 183,
+		},
+		{
+			name:    "singular",
+			matrix:  [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}},
+			wantDet: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := CopyMatrix(tt.matrix)
+			lu, err := NewLU(Matrix{Data: tt.matrix})
+			if err != nil {
+				t.Fatalf("NewLU() unexpected error: %v", err)
+			}
+			if !areMatricesEqual(tt.matrix, original) {
+				t.Errorf("NewLU() modified its input")
+			}
+
+			// P * A = L * U
+			pa := MultiplyMatrices(lu.P.Data, tt.matrix)
+			lu2 := MultiplyMatrices(lu.L.Data, lu.U.Data)
+			if !areMatricesEqual(pa, lu2) {
+				t.Errorf("P*A = %v, L*U = %v", pa, lu2)
+			}
+
+			for i := range lu.L.Data {
+				if lu.L.Data[i][i] != 1 {
+					t.Errorf("L is not unit lower triangular: %v", lu.L.Data)
+				}
+				for j := i + 1; j < len(lu.L.Data); j++ {
+					if lu.L.Data[i][j] != 0 || lu.U.Data[j][i] != 0 {
+						t.Errorf("L or U not triangular: L=%v U=%v", lu.L.Data, lu.U.Data)
+					}
+				}
+			}
+
+			// partial pivoting keeps the multipliers bounded by 1
+			for i := range lu.L.Data {
+				for j := 0; j < i; j++ {
+					if math.Abs(lu.L.Data[i][j]) > 1 {
+						t.Errorf("multiplier L[%d][%d] = %f larger than 1", i, j, lu.L.Data[i][j])
+					}
+				}
+			}
+
+			if math.Abs(lu.Det()-tt.wantDet) > 1e-9 {
+				t.Errorf("LU.Det() = %v, want %v", lu.Det(), tt.wantDet)
+			}
+		})
+	}
+}
+
+func TestNewLUNotSquare(t *testing.T) {
+	_, err := NewLU(Matrix{Data: [][]float64{{1, 2, 3}, {4, 5, 6}}})
+	if !errors.Is(err, ErrNotSquare) {
+		t.Errorf("NewLU() error = %v, want %v", err, ErrNotSquare)
+	}
+}
+
+func TestLU_Solve(t *testing.T) {
+	tests := []struct {
+		name string
+		A    [][]float64
+		b    []float64
+		want []float64
+	}{
+		{
+			name: "2x2 general",
+			A:    [][]float64{{1, 2}, {3, 4}},
+			b:    []float64{5, 11},
+			want: []float64{1, 2},
+		},
+		{
+			name: "3x3 general",
+			A:    [][]float64{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}},
+			b:    []float64{8, -11, -3},
+			want: []float64{2, 3, -1},
+		},
+		{
+			name: "3x3 requires pivoting",
+			A:    [][]float64{{0, 0, 1}, {0, 1, 0}, {1, 0, 0}},
+			b:    []float64{3, 2, 1},
+			want: []float64{1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lu, err := NewLU(Matrix{Data: tt.A})
+			if err != nil {
+				t.Fatalf("NewLU() unexpected error: %v", err)
+			}
+			got, err := lu.Solve(tt.b)
+			if err != nil {
+				t.Fatalf("LU.Solve() unexpected error: %v", err)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-10 {
+					t.Errorf("LU.Solve() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestLU_SolveErrors(t *testing.T) {
+	lu, err := NewLU(Matrix{Data: [][]float64{{1, 2}, {2, 4}}})
+	if err != nil {
+		t.Fatalf("NewLU() unexpected error: %v", err)
+	}
+	if !lu.IsSingular() {
+		t.Errorf("LU.IsSingular() = false for singular matrix")
+	}
+	if _, err := lu.Solve([]float64{1, 2}); !errors.Is(err, ErrSingular) {
+		t.Errorf("LU.Solve() error = %v, want %v", err, ErrSingular)
+	}
+	if _, err := lu.Inverse(); !errors.Is(err, ErrSingular) {
+		t.Errorf("LU.Inverse() error = %v, want %v", err, ErrSingular)
+	}
+
+	lu, _ = NewLU(Matrix{Data: [][]float64{{1, 2}, {3, 4}}})
+	if _, err := lu.Solve([]float64{1, 2, 3}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("LU.Solve() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := lu.SolveMatrix(Matrix{Data: [][]float64{{1}}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("LU.SolveMatrix() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestLU_BadlyScaled(t *testing.T) {
+	// the pivots differ by 20 orders of magnitude but none of them is 0,
+	// Det and Solve must agree that the matrix is invertible
+	A := [][]float64{{1e20, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+	lu, err := NewLU(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NewLU() unexpected error: %v", err)
+	}
+	if lu.IsSingular() {
+		t.Errorf("LU.IsSingular() = true, want false")
+	}
+	if got := lu.Det(); got != 1e20 {
+		t.Errorf("LU.Det() = %v, want 1e20", got)
+	}
+
+	x, err := lu.Solve([]float64{1e20, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("LU.Solve() unexpected error: %v", err)
+	}
+	for i, want := range []float64{1, 2, 3, 4} {
+		if x[i] != want {
+			t.Errorf("LU.Solve() = %v, want [1 2 3 4]", x)
+			break
+		}
+	}
+	if _, err := lu.SolveMatrix(Matrix{Data: GenerateIdentityMatrix(4)}); err != nil {
+		t.Errorf("LU.SolveMatrix() unexpected error: %v", err)
+	}
+	inv, err := lu.Inverse()
+	if err != nil {
+		t.Fatalf("LU.Inverse() unexpected error: %v", err)
+	}
+	if inv.Data[0][0] != 1e-20 || inv.Data[3][3] != 1 {
+		t.Errorf("LU.Inverse() = %v, want diag(1e-20, 1, 1, 1)", inv.Data)
+	}
+	if got := lu.CondEstimate(); got != 1e20 {
+		t.Errorf("LU.CondEstimate() = %v, want 1e20", got)
+	}
+}
+
+func TestLU_SolveMatrix(t *testing.T) {
+	A := [][]float64{{4, -2, 1}, {-2, 4, -2}, {1, -2, 4}}
+	B := [][]float64{{11, 1}, {-16, 0}, {17, 3}}
+	lu, err := NewLU(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NewLU() unexpected error: %v", err)
+	}
+
+	X, err := lu.SolveMatrix(Matrix{Data: B})
+	if err != nil {
+		t.Fatalf("LU.SolveMatrix() unexpected error: %v", err)
+	}
+	if got := MultiplyMatrices(A, X.Data); !areMatricesEqual(got, B) {
+		t.Errorf("A * X = %v, want %v", got, B)
+	}
+
+	// every column must match an individual Solve
+	for j := range B[0] {
+		x, _ := lu.Solve([]float64{B[0][j], B[1][j], B[2][j]})
+		for i := range x {
+			if math.Abs(x[i]-X.Data[i][j]) > 1e-12 {
+				t.Errorf("column %d: SolveMatrix = %v, Solve = %v", j, X.Data, x)
+			}
+		}
+	}
+}
+
+func TestLU_Inverse(t *testing.T) {
+	A := [][]float64{{1, 2, 3}, {0, 4, 5}, {1, 0, 6}}
+	lu, err := NewLU(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NewLU() unexpected error: %v", err)
+	}
+
+	inv, err := lu.Inverse()
+	if err != nil {
+		t.Fatalf("LU.Inverse() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(inv.Data, GetInverseMatrixByDeterminant(CopyMatrix(A))) {
+		t.Errorf("LU.Inverse() = %v, want %v", inv.Data, GetInverseMatrixByDeterminant(A))
+	}
+	if got := MultiplyMatrices(A, inv.Data); !areMatricesEqual(got, GenerateIdentityMatrix(3)) {
+		t.Errorf("A * A^-1 = %v, want identity", got)
+	}
+}
+
+func TestLU_PivotGrowth(t *testing.T) {
+	// Wilkinson's matrix is the worst case for partial pivoting,
+	// the last column doubles on every step so the growth is 2^(n-1)
+	n := 6
+	A := GenerateIdentityMatrix(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			A[i][j] = -1
+		}
+		A[i][n-1] = 1
+	}
+
+	lu, err := NewLU(Matrix{Data: A})
+	if err != nil {
+		t.Fatalf("NewLU() unexpected error: %v", err)
+	}
+	if want := math.Pow(2, float64(n-1)); lu.PivotGrowth != want {
+		t.Errorf("LU.PivotGrowth = %v, want %v", lu.PivotGrowth, want)
+	}
+
+	lu, _ = NewLU(Matrix{Data: GenerateIdentityMatrix(3)})
+	if lu.PivotGrowth != 1 {
+		t.Errorf("LU.PivotGrowth of identity = %v, want 1", lu.PivotGrowth)
+	}
+}
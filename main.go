@@ -2,7 +2,6 @@ package linearalgebra
 
 import (
 	"bufio"
-	"encoding/csv"
 	"fmt"
 	"io"
 	"math"
@@ -25,15 +24,33 @@ import (
 // Swap the rows so that the leading entry of each nonzero row is to the right of the leading entry of the row above it.
 func ToRowReducedEchelonForm(pMatrix [][]float64) [][]float64 {
 	matrix := CopyMatrix(pMatrix)
+	if len(matrix) == 0 {
+		return matrix
+	}
+	gaussJordan(matrix, len(matrix[0]), rrefTolerance.Absolute)
+
+	return matrix
+}
+
+// gaussJordan reduces the first pivotCols columns of matrix to reduced row echelon
+// form in place, applying the same row operations to the columns after them.
+// Entries with an absolute value of at most tol are treated as 0.
+// It returns perm, row i of the result comes from row perm[i] of the input,
+// and the pivot column of every nonzero row of the result.
+func gaussJordan(matrix [][]float64, pivotCols int, tol float64) (perm, pivots []int) {
 	rows := len(matrix)
+	perm = make([]int, rows)
+	for i := range perm {
+		perm[i] = i
+	}
+	pivots = []int{}
 	if rows == 0 {
-		return matrix
+		return perm, pivots
 	}
 	cols := len(matrix[0])
-	const tol = 1e-10
 
 	pivotRow := 0
-	for col := 0; col < cols && pivotRow < rows; col++ {
+	for col := 0; col < pivotCols && pivotRow < rows; col++ {
 		// Partial pivoting: find row with largest absolute value in this column
 		bestRow := pivotRow
 		bestVal := math.Abs(matrix[pivotRow][col])
@@ -44,12 +61,13 @@ func ToRowReducedEchelonForm(pMatrix [][]float64) [][]float64 {
 			}
 		}
 
-		if bestVal < tol {
+		if bestVal <= tol {
 			continue
 		}
 
 		// Swap rows
 		matrix[pivotRow], matrix[bestRow] = matrix[bestRow], matrix[pivotRow]
+		perm[pivotRow], perm[bestRow] = perm[bestRow], perm[pivotRow]
 
 		// Scale pivot row so leading entry becomes 1
 		scale := matrix[pivotRow][col]
@@ -62,7 +80,7 @@ func ToRowReducedEchelonForm(pMatrix [][]float64) [][]float64 {
 			if r == pivotRow {
 				continue
 			}
-			if math.Abs(matrix[r][col]) < tol {
+			if math.Abs(matrix[r][col]) <= tol {
 				continue
 			}
 			factor := matrix[r][col]
@@ -71,24 +89,33 @@ func ToRowReducedEchelonForm(pMatrix [][]float64) [][]float64 {
 			}
 		}
 
+		pivots = append(pivots, col)
 		pivotRow++
 	}
 
 	// Clean up near-zero entries
 	for i := range matrix {
-		for j := range matrix[i] {
-			if math.Abs(matrix[i][j]) < tol {
+		for j := 0; j < pivotCols; j++ {
+			if math.Abs(matrix[i][j]) <= tol {
 				matrix[i][j] = 0
 			}
 		}
 	}
 
-	return matrix
+	return perm, pivots
 }
 
 func CopyMatrix(matrix [][]float64) [][]float64 {
-	newMatrix := make([][]float64, len(matrix))
+	if isRectangular(matrix) {
+		newMatrix, _ := contiguousRows[float64](len(matrix), GetShape(matrix).Cols)
+		for i := range matrix {
+			copy(newMatrix[i], matrix[i])
+		}
+
+		return newMatrix
+	}
 
+	newMatrix := make([][]float64, len(matrix))
 	for i := range matrix {
 		newMatrix[i] = make([]float64, len(matrix[i]))
 		copy(newMatrix[i], matrix[i])
@@ -97,64 +124,20 @@ func CopyMatrix(matrix [][]float64) [][]float64 {
 	return newMatrix
 }
 
+// GetEliminationMatrix returns the invertible matrix M with
+// M * A = ToRowReducedEchelonForm(A), M = E * P of NewEliminationCertificate.
+// For an invertible A it is A^-1, for a zero matrix it is the identity.
 func GetEliminationMatrix(matrix [][]float64) [][]float64 {
 	if len(matrix) == 0 || len(matrix[0]) == 0 {
 		return [][]float64{}
 	}
-	// Swap the rows so that all rows with all zero entries are on the bottom
-	matrix = SwapRows0sToBottom(matrix)
-	changeMatrices := [][][]float64{}
-
-	// Add/subtract multiples of the top row to the other rows so that all other
-	// entries in the column containing the top row's leading entry are all zero.
-	for i := 0; i < len(matrix); i++ {
-		for j := 0; j < len(matrix[i]); j++ {
-			// find non 0
-			if matrix[i][j] != 0 {
-				// make this row pivot row
-				tmp := matrix[i][j]
-				matrix = MultiplyRowByScalar(matrix, i, float64(1/tmp))
-				changeMatrix := MultiplyRowByScalar(GenerateIdentityMatrix(len(matrix)), i, float64(1/tmp))
-				changeMatrices = append(changeMatrices, changeMatrix)
-
-				// turn every column in this pivot to 0
-				for z := 0; z < len(matrix); z++ {
-					if z == i {
-						continue
-					}
-
-					if matrix[z][j] != 0 {
-						tmp := matrix[z][j]
-
-						matrix = MultiplyRowByScalar(matrix, i, -tmp)
-						changeMatrix = MultiplyRowByScalar(GenerateIdentityMatrix(len(matrix)), i, -tmp)
-
-						matrix = AddRowToRow(matrix, matrix[i], z)
-						changeMatrix = AddRowToRow(changeMatrix, changeMatrix[i], z)
-
-						matrix = MultiplyRowByScalar(matrix, i, float64(1/-tmp))
-						changeMatrix = MultiplyRowByScalar(changeMatrix, i, float64(1/-tmp))
-
-						changeMatrices = append(changeMatrices, changeMatrix)
-					}
-				}
-				break
-			}
-		}
-	}
-
-	// turn current row into pivot row by multiplying by the inverse of the leading entry
-	// make every entry in the column of the leading entry 0
-	// find next pivot and do the same
-	_ = SwapLargetsLeftmostNonzeroEntry(matrix)
-	// eliminationMatrix = SwapLargetsLeftmostNonzeroEntry(eliminationMatrix)
 
-	eliminationMatrix := changeMatrices[0]
-	for i := 1; i < len(changeMatrices); i++ {
-		eliminationMatrix = MultiplyMatrices(changeMatrices[i], eliminationMatrix)
+	certificate, err := NewEliminationCertificate(Matrix{Data: matrix})
+	if err != nil {
+		panic(err)
 	}
 
-	return eliminationMatrix
+	return MultiplyMatrices(certificate.E.Data, certificate.P.Data)
 }
 
 func SwapLargetsLeftmostNonzeroEntry(matrix [][]float64) [][]float64 {
@@ -178,11 +161,7 @@ func GenerateIdentityMatrix(n int) [][]float64 {
 		panic("illegal operation")
 	}
 
-	matrix := make([][]float64, n)
-	for i := range matrix {
-		matrix[i] = make([]float64, n)
-	}
-
+	matrix, _ := contiguousRows[float64](n, n)
 	for i := range n {
 		matrix[i][i] = 1
 	}
@@ -310,23 +289,15 @@ func DotProductVectors(vectorA, vectorB []float64) float64 {
 
 // MultiplyMatrices multiply matrices will use dot product to multiply two matrices
 // For MultiplyMatrices with vectors use DotProductVectors instead
+// Large products are computed in cache sized tiles on several goroutines,
+// see SetMultiplyWorkers.
 func MultiplyMatrices(matrixA, matrixB [][]float64) [][]float64 {
 	if !CanMultiplyMatrices(matrixA, matrixB) {
 		panic("invalid multiplication")
 	}
 
-	newMatrix := make([][]float64, len(matrixA))
-	for i := range newMatrix {
-		newMatrix[i] = make([]float64, len(matrixB[0]))
-	}
-
-	for i := range newMatrix {
-		for j := range newMatrix[i] {
-			for z := range matrixB {
-				newMatrix[i][j] += float64(matrixA[i][z]) * float64(matrixB[z][j])
-			}
-		}
-	}
+	newMatrix, _ := contiguousRows[float64](len(matrixA), GetShape(matrixB).Cols)
+	multiplyInto(newMatrix, matrixA, matrixB)
 
 	return newMatrix
 }
@@ -359,7 +330,7 @@ func GetPivotEntries(matrix [][]float64) [][]int {
 	i := 0
 	j := 0
 	for i < len(matrix) && j < len(matrix[i]) {
-		if math.Abs(matrix[i][j]) > 1e-10 {
+		if math.Abs(matrix[i][j]) > rrefTolerance.Absolute {
 			answer = append(answer, []int{i, j})
 			i++
 		}
@@ -497,12 +468,19 @@ func allPivotEntriesAreRightOfPivotbove(matrix [][]float64) bool {
 	return true
 }
 
+// GetVectorLength returns the euclidean norm of a vector
+// If the squares of the entries overflow or underflow their sum is recomputed
+// with the entries scaled by the largest one, see VectorNorm for other norms
 func GetVectorLength(vector []float64) float64 {
 	var powed float64 = 0
 	for i := range vector {
 		powed += math.Pow(vector[i], 2)
 	}
 
+	if math.IsInf(powed, 1) || powed < 1e-290 {
+		return norm2(vector)
+	}
+
 	return math.Sqrt(powed)
 }
 
@@ -609,41 +587,7 @@ func areVectorsLinearlyIndependentByCauchySchwarz(vectorA, vectorB []float64) bo
 // checking if they have the same dimensions and if all their
 // components are equal
 func areMatricesEqual(matrixA, matrixB [][]float64) bool {
-	if len(matrixA) != len(matrixB) {
-		return false
-	}
-
-	if len(matrixA) == 0 {
-		return true
-	}
-
-	cols := len(matrixA[0])
-	if cols != len(matrixB[0]) {
-		return false
-	}
-
-	// ensure matrix A has same number of cols in all rows
-	for i := range matrixA {
-		if len(matrixA[i]) != cols {
-			return false
-		}
-	}
-
-	for i := range matrixB {
-		if len(matrixB[i]) != cols {
-			return false
-		}
-	}
-
-	for i := range matrixA {
-		for j := range matrixA[i] {
-			if !NearlyEqual(matrixA[i][j], matrixB[i][j], 3) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return MatricesNearlyEqual(matrixA, matrixB, equalityTolerance)
 }
 
 // verify if vectors are linearly independant by vector triangular inequality
@@ -825,16 +769,32 @@ func GetDeterminant(matrix [][]float64) float64 {
 		return det
 	}
 
-	// generic case n>3 with laplace expansion
-	// we expand one row only
-	var det float64
-	for col := range matrix[0] {
-		minor := GetMinor(matrix, 0, col)
-		cofactor := math.Pow(-1, float64(col)) * matrix[0][col] * GetDeterminant(minor)
-		det += cofactor
+	// generic case n>3, laplace expansion is n! so we use the LU factorization
+	// det(A) = sign(P) * product of the pivots of U
+	lu, err := NewLU(Matrix{Data: matrix})
+	if err != nil {
+		panic(err)
 	}
 
-	return det
+	return lu.Det()
+}
+
+// GetLogDeterminant returns the sign and the natural log of the absolute value
+// of the determinant, det(A) = sign * exp(logAbsDet).
+// Use it for matrices where the determinant overflows or underflows a float64,
+// for example a 500x500 matrix with entries around 1e-3.
+// A singular matrix returns sign 0 and logAbsDet -Inf.
+func GetLogDeterminant(matrix [][]float64) (sign float64, logAbsDet float64) {
+	if !IsMatrixSquare(matrix) {
+		panic("cannot calculate determinant of non square matrix")
+	}
+
+	lu, err := NewLU(Matrix{Data: matrix})
+	if err != nil {
+		panic(err)
+	}
+
+	return lu.LogDet()
 }
 
 func IsMatrixSquare(matrix [][]float64) bool {
@@ -854,6 +814,7 @@ func IsMatrixSquare(matrix [][]float64) bool {
 
 // IsMatrixInvertible checks if determinant is non 0
 // If the matrix has an inverse then it is invertible
+// Use IsMatrixInvertibleWithCond to also reject nearly singular matrices
 func IsMatrixInvertible(matrix [][]float64) bool {
 	if !IsMatrixSquare(matrix) {
 		return false
@@ -952,13 +913,11 @@ func TransposeMatrix[T any](matrix [][]T) [][]T {
 	if len(matrix) == 0 {
 		return matrix
 	}
-	newmatrix := [][]T{}
-	for col := 0; col < len(matrix[0]); col++ {
-		newRow := []T{}
-		for row := 0; row < len(matrix); row++ {
-			newRow = append(newRow, matrix[row][col])
+	newmatrix, _ := contiguousRows[T](len(matrix[0]), len(matrix))
+	for col := range newmatrix {
+		for row := range matrix {
+			newmatrix[col][row] = matrix[row][col]
 		}
-		newmatrix = append(newmatrix, newRow)
 	}
 	return newmatrix
 }
@@ -1001,6 +960,7 @@ func GetMatrixNullity(matrix [][]float64) int {
 
 // matrix is invertible if there exists an inverse A^-1
 // A^-1 = 1/det(A) * adj(A)
+// for matrices larger than 3x3 the inverse is computed with Gauss-Jordan elimination
 func GetInverseMatrixByDeterminant(matrix [][]float64) [][]float64 {
 	if !IsMatrixSquare(matrix) {
 		panic("cannot calculate inverse of non square matrix")
@@ -1011,12 +971,66 @@ func GetInverseMatrixByDeterminant(matrix [][]float64) [][]float64 {
 		panic("cannot calculate inverse of non invertible matrix")
 	}
 
+	// the adjugate needs n^2 determinants, for larger matrices use Gauss-Jordan
+	if len(matrix) > 3 {
+		return gaussJordanInverse(matrix)
+	}
+
 	adjMatrix := GetAdjugateMatrix(matrix)
 
 	res := MultiplyMatrixByScalar(adjMatrix, 1/det)
 	return res
 }
 
+// gaussJordanInverse inverts a matrix by row reducing [A | I] into [I | A^-1]
+// with partial pivoting. The matrix must be square and invertible.
+func gaussJordanInverse(matrix [][]float64) [][]float64 {
+	n := len(matrix)
+	aug := make([][]float64, n)
+	for i := range aug {
+		aug[i] = make([]float64, 2*n)
+		copy(aug[i], matrix[i])
+		aug[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		// Partial pivoting: find row with largest absolute value in this column
+		bestRow := col
+		bestVal := math.Abs(aug[col][col])
+		for r := col + 1; r < n; r++ {
+			if v := math.Abs(aug[r][col]); v > bestVal {
+				bestVal = v
+				bestRow = r
+			}
+		}
+		aug[col], aug[bestRow] = aug[bestRow], aug[col]
+
+		// Scale pivot row so leading entry becomes 1
+		scale := aug[col][col]
+		for j := col; j < 2*n; j++ {
+			aug[col][j] /= scale
+		}
+
+		// Eliminate all other entries in this column
+		for r := 0; r < n; r++ {
+			if r == col || aug[r][col] == 0 {
+				continue
+			}
+			factor := aug[r][col]
+			for j := col; j < 2*n; j++ {
+				aug[r][j] -= factor * aug[col][j]
+			}
+		}
+	}
+
+	inverse := make([][]float64, n)
+	for i := range inverse {
+		inverse[i] = aug[i][n:]
+	}
+
+	return inverse
+}
+
 // CrossProduct returns a vector that is orthogonal to both A and B
 // we calculate it witha determinant, something like a matrix
 /*
@@ -1061,19 +1075,8 @@ func IsVectorInTheNullSpaceOfMatrix(vector []float64, matrix [][]float64) bool {
 		panic("cannot mulitply this matrix with this vector")
 	}
 
-	// check if we should transpose vector to make it column or row vector
-	resultVector := MultiplyMatrices(matrix, columnVector)
-	if len(resultVector[0]) != 1 {
-		panic("result vector should be a row vector")
-	}
-
-	for i := range resultVector {
-		if !NearlyEqual(resultVector[i][0], 0, 3) {
-			return false
-		}
-	}
-
-	return true
+	inNullSpace, _ := IsVectorInTheNullSpaceOfMatrixWithTolerance(vector, matrix, equalityTolerance)
+	return inNullSpace
 }
 
 // GetNullSpaceOfMatrix returns the null space of a matrix
@@ -1093,17 +1096,30 @@ func GetNullSpaceOfMatrix(matrix [][]float64) [][]float64 {
 	// get the row reduced echelon form of the matrix
 	rref := ToRowReducedEchelonForm(matrix)
 
-	// get the pivots entries
-	pivots := GetPivotEntries(rref)
+	// get the pivots entries, the pivot of row i is in column pivotColumns[i]
+	pivotColumns := []int{}
+	for _, pivot := range GetPivotEntries(rref) {
+		pivotColumns = append(pivotColumns, pivot[1])
+	}
+
+	return nullSpaceFromRREF(rref, pivotColumns)
+}
 
+// nullSpaceFromRREF returns a basis of the null space of a matrix in reduced row
+// echelon form whose row i has its pivot in column pivotColumns[i].
+// There is one basis vector per free column, with 1 in that column and 0 in the others.
+func nullSpaceFromRREF(rref [][]float64, pivotColumns []int) [][]float64 {
 	// create a list of vectors that are in the null space
 	nullSpace := [][]float64{}
+	if len(rref) == 0 {
+		return nullSpace
+	}
 
 	for currentColumnIndex := 0; currentColumnIndex < len(rref[0]); currentColumnIndex++ {
 		// if this column is a pivot column, we skip it
 		isPivotColumn := false
-		for _, currentPivot := range pivots {
-			if currentPivot[1] == currentColumnIndex {
+		for _, pivotColumn := range pivotColumns {
+			if pivotColumn == currentColumnIndex {
 				isPivotColumn = true
 				break
 			}
@@ -1117,8 +1133,8 @@ func GetNullSpaceOfMatrix(matrix [][]float64) [][]float64 {
 		nullSpaceVector := make([]float64, len(rref[0]))
 		nullSpaceVector[currentColumnIndex] = 1
 
-		for _, currentPivot := range pivots {
-			nullSpaceVector[currentPivot[1]] = -rref[currentPivot[0]][currentColumnIndex]
+		for row, pivotColumn := range pivotColumns {
+			nullSpaceVector[pivotColumn] = -rref[row][currentColumnIndex]
 		}
 
 		nullSpace = append(nullSpace, nullSpaceVector)
@@ -1188,182 +1204,27 @@ func HadamardProduct(matrixA, matrixB [][]float64) [][]float64 {
 }
 
 // GetEigenvalues returns the eigenvalues of a square matrix
-// For 2x2 matrices we use the quadratic formula
-// For larger matrices we use the QR algorithm to approximate the eigenvalues
+// The matrix is reduced to upper Hessenberg form and the eigenvalues are found
+// with the Francis double shift QR algorithm, see TryGetEigenvalues.
 // The eigenvalues are returned as a slice of complex128 to account for complex eigenvalues
+// It panics if the matrix is not square or the QR algorithm does not converge.
 func GetEigenvalues(matrix [][]float64) []complex128 {
 	if !IsMatrixSquare(matrix) {
 		panic("cannot calculate eigenvalues of non square matrix")
 	}
 
-	if len(matrix) == 0 {
-		return []complex128{}
-	}
-
-	if len(matrix) == 1 {
-		return []complex128{complex(matrix[0][0], 0)}
-	}
-
-	// For 2x2 matrix we can use the quadratic formula
-	if len(matrix) == 2 {
-		a := 1.0
-		b := -(matrix[0][0] + matrix[1][1])
-		c := matrix[0][0]*matrix[1][1] - matrix[0][1]*matrix[1][0]
-
-		discriminant := b*b - 4*a*c
-		if discriminant >= 0 {
-			root1 := (-b + math.Sqrt(discriminant)) / (2 * a)
-			root2 := (-b - math.Sqrt(discriminant)) / (2 * a)
-			return []complex128{complex(root1, 0), complex(root2, 0)}
-		} else {
-			realPart := -b / (2 * a)
-			imaginaryPart := math.Sqrt(-discriminant) / (2 * a)
-			return []complex128{complex(realPart, imaginaryPart), complex(realPart, -imaginaryPart)}
-		}
-	}
-
-	// QR algorithm with Wilkinson shift and convergence check.
-	// Drives the matrix toward real Schur form (quasi-upper-triangular).
-	A := CopyMatrix(matrix)
-	n := len(A)
-	for iter := 0; iter < 2000; iter++ {
-		// Convergence check: stop when all subdiagonal elements are small
-		converged := true
-		for i := 0; i < n-1; i++ {
-			if math.Abs(A[i+1][i]) > 1e-12*(math.Abs(A[i][i])+math.Abs(A[i+1][i+1])+1e-30) {
-				converged = false
-				break
-			}
-		}
-		if converged {
-			break
-		}
-
-		// Wilkinson shift: use eigenvalue of bottom-right 2x2 block closest to A[n-1][n-1]
-		shift := 0.0
-		if n >= 2 {
-			a11 := A[n-2][n-2]
-			a12 := A[n-2][n-1]
-			a21 := A[n-1][n-2]
-			a22 := A[n-1][n-1]
-			tr := a11 + a22
-			det := a11*a22 - a12*a21
-			disc := tr*tr - 4*det
-			if disc >= 0 {
-				sqrtDisc := math.Sqrt(disc)
-				lambda1 := 0.5 * (tr + sqrtDisc)
-				lambda2 := 0.5 * (tr - sqrtDisc)
-				if math.Abs(lambda1-a22) < math.Abs(lambda2-a22) {
-					shift = lambda1
-				} else {
-					shift = lambda2
-				}
-			} else {
-				shift = a22
-			}
-		}
-
-		// Apply shift
-		for i := 0; i < n; i++ {
-			A[i][i] -= shift
-		}
-		Q, R := qrDecomposition(A)
-		A = MultiplyMatrices(R, Q)
-		for i := 0; i < n; i++ {
-			A[i][i] += shift
-		}
-	}
-
-	// Zero-out tiny subdiagonal elements for robust block detection
-	eps := 1e-9
-	for i := 0; i < n-1; i++ {
-		if math.Abs(A[i+1][i]) < eps {
-			A[i+1][i] = 0
-		}
-	}
-
-	// Extract eigenvalues from 1x1 and 2x2 blocks
-	eigenvalues := make([]complex128, 0, n)
-	i := 0
-	for i < n {
-		if i < n-1 && math.Abs(A[i+1][i]) > 0 { // 2x2 block
-			a, b := A[i][i], A[i][i+1]
-			c, d := A[i+1][i], A[i+1][i+1]
-			// Characteristic: λ^2 - (a+d)λ + (ad - bc) = 0
-			tr := a + d
-			det := a*d - b*c
-			disc := tr*tr - 4*det
-			if disc >= 0 {
-				sqrtDisc := math.Sqrt(disc)
-				lambda1 := 0.5 * (tr + sqrtDisc)
-				lambda2 := 0.5 * (tr - sqrtDisc)
-				eigenvalues = append(eigenvalues, complex(lambda1, 0), complex(lambda2, 0))
-			} else {
-				realPart := 0.5 * tr
-				imagPart := 0.5 * math.Sqrt(-disc)
-				eigenvalues = append(eigenvalues, complex(realPart, imagPart), complex(realPart, -imagPart))
-			}
-			i += 2
-		} else { // 1x1 block
-			eigenvalues = append(eigenvalues, complex(A[i][i], 0))
-			i++
-		}
+	eigenvalues, err := TryGetEigenvalues(matrix)
+	if err != nil {
+		panic(err)
 	}
 
 	return eigenvalues
 }
 
-// qrDecomposition performs QR decomposition of matrix A
-// using Modified Gram-Schmidt for improved numerical stability
-// A = QR where Q is orthogonal and R is upper triangular
-func qrDecomposition(A [][]float64) ([][]float64, [][]float64) {
-	m := len(A)
-	n := len(A[0])
-
-	Q := make([][]float64, m)
-	for i := range Q {
-		Q[i] = make([]float64, n)
-	}
-	R := make([][]float64, n)
-	for i := range R {
-		R[i] = make([]float64, n)
-	}
-
-	// Copy columns of A into Q
-	for j := 0; j < n; j++ {
-		for i := 0; i < m; i++ {
-			Q[i][j] = A[i][j]
-		}
-	}
-
-	for j := 0; j < n; j++ {
-		// Modified Gram-Schmidt: project against already-orthogonalized columns
-		for k := 0; k < j; k++ {
-			var dot float64
-			for i := 0; i < m; i++ {
-				dot += Q[i][k] * Q[i][j]
-			}
-			R[k][j] = dot
-			for i := 0; i < m; i++ {
-				Q[i][j] -= dot * Q[i][k]
-			}
-		}
-		var norm float64
-		for i := 0; i < m; i++ {
-			norm += Q[i][j] * Q[i][j]
-		}
-		norm = math.Sqrt(norm)
-		R[j][j] = norm
-		if norm > 1e-14 {
-			for i := 0; i < m; i++ {
-				Q[i][j] /= norm
-			}
-		}
-	}
-
-	return Q, R
-}
-
+// GetEigenvectors returns a unit eigenvector for every eigenvalue returned by
+// GetEigenvalues, in the same order. The sign follows SignLargestPositive, use
+// GetEigenvectorsWithOptions for another convention.
+// It panics if the matrix is not square.
 func GetEigenvectors(matrix [][]float64) [][]complex128 {
 	if !IsMatrixSquare(matrix) {
 		panic("cannot calculate eigenvectors of non square matrix")
@@ -1373,8 +1234,28 @@ func GetEigenvectors(matrix [][]float64) [][]complex128 {
 		return [][]complex128{}
 	}
 
+	return eigenvectors(matrix, GetEigenvalues(matrix), rrefTolerance, 1, SignLargestPositive)
+}
+
+// eigenvectors returns an eigenvector for every eigenvalue, repeated eigenvalues
+// share the basis of their eigenspace. The rank of A - lambda * I is found
+// with reduceWithTolerance at the given tolerance and scale. The vectors follow
+// the sign convention.
+func eigenvectors(matrix [][]float64, eigenvalues []complex128, tol Tolerance, scale float64, sign SignConvention) [][]complex128 {
 	n := len(matrix)
-	eigenvalues := GetEigenvalues(matrix)
+
+	// A / scale has the same eigenvectors for the eigenvalues divided by scale,
+	// and its entries are the size the constants of inverseIteration are made for
+	if scale > 0 && scale != 1 {
+		matrix = MultiplyMatrixByScalar(CopyMatrix(matrix), 1/scale)
+		scaled := make([]complex128, len(eigenvalues))
+		for i := range eigenvalues {
+			scaled[i] = eigenvalues[i] / complex(scale, 0)
+		}
+		eigenvalues = scaled
+		tol.Absolute /= scale
+		scale = 1
+	}
 
 	// Cache eigenspaces to handle repeated eigenvalues
 	// Key: eigenvalue (as complex), Value: list of eigenvectors for that eigenvalue
@@ -1387,7 +1268,7 @@ func GetEigenvectors(matrix [][]float64) [][]complex128 {
 		// Check if we've already computed the eigenspace for this eigenvalue
 		eigenspace, cached := eigenspaceCache[lambda]
 		if !cached {
-			eigenspace = computeEigenspace(matrix, lambda, n)
+			eigenspace = computeEigenspace(matrix, lambda, n, tol, scale)
 			eigenspaceCache[lambda] = eigenspace
 			vectorIndexCache[lambda] = 0
 		}
@@ -1403,29 +1284,34 @@ func GetEigenvectors(matrix [][]float64) [][]complex128 {
 		}
 	}
 
+	if sign == SignLargestPositive {
+		for _, v := range eigenvectors {
+			canonicalizeComplexSign(v)
+		}
+	}
+
 	return eigenvectors
 }
 
 // computeEigenspace finds all linearly independent eigenvectors for a given eigenvalue.
 // For real eigenvalues, it solves (A - λI)v = 0 and returns the null space basis.
 // For complex eigenvalues, it finds a single eigenvector.
-func computeEigenspace(matrix [][]float64, lambda complex128, n int) [][]complex128 {
+func computeEigenspace(matrix [][]float64, lambda complex128, n int, tol Tolerance, scale float64) [][]complex128 {
 	if imag(lambda) == 0 {
-		return computeRealEigenspace(matrix, real(lambda), n)
+		return computeRealEigenspace(matrix, real(lambda), n, tol, scale)
 	}
 	return computeComplexEigenspace(matrix, lambda, n)
 }
 
 // computeRealEigenspace solves (A - λI)v = 0 for real eigenvalue λ.
 // Uses inverse iteration with deflation to find multiple eigenvectors for repeated eigenvalues.
-func computeRealEigenspace(matrix [][]float64, lambda float64, n int) [][]complex128 {
+func computeRealEigenspace(matrix [][]float64, lambda float64, n int, tol Tolerance, scale float64) [][]complex128 {
 	// Determine geometric multiplicity from rank of (A - λI)
 	AminusLI := CopyMatrix(matrix)
 	for i := 0; i < n; i++ {
 		AminusLI[i][i] -= lambda
 	}
-	rref := ToRowReducedEchelonForm(AminusLI)
-	pivots := GetPivotEntries(rref)
+	_, pivots := reduceWithTolerance(AminusLI, tol, scale)
 	rank := len(pivots)
 	geomMult := n - rank
 	if geomMult < 1 {
@@ -1689,8 +1575,22 @@ func complexToRealVector(vector []complex128) []float64 {
 	return res
 }
 
+// Matrix is a dense matrix of float64.
+// Matrices built with NewMatrix, NewZeroMatrix or NewMatrixFromFlat, and the results
+// of the Matrix methods, keep all their elements in a single []float64 and every
+// row of Data is a window into it. Views from Slice, RowView and ColView share
+// that storage, so writes through Data, a view or the backing array are visible
+// in all of them.
+// A Matrix literal built from a [][]float64 keeps using the rows it was given.
 type Matrix struct {
 	Data [][]float64
+
+	// data is the flat backing array of a rows x cols matrix, element (i, j)
+	// is data[i*stride+j] and row i of Data is a window into it. It is nil for
+	// a matrix built as a literal, which keeps its own rows.
+	data       []float64
+	rows, cols int
+	stride     int
 }
 
 func (m Matrix) ToString(decimals int) string {
@@ -1726,16 +1626,31 @@ func (m Matrix) ToString(decimals int) string {
 	return sb.String()
 }
 
+// NewMatrix copies data into a new matrix backed by a single []float64, the
+// matrix and data share no memory. Rows of different lengths cannot be stored
+// flat, they are copied row by row.
+// Replacing or reordering the rows of Data leaves the flat storage behind,
+// Flat then returns a copy in the new row order.
+// A literal Matrix{Data: data} does not copy and is not flat.
 func NewMatrix(data [][]float64) Matrix {
-	return Matrix{Data: data}
+	if !isRectangular(data) {
+		return Matrix{Data: CopyMatrix(data)}
+	}
+
+	res := newContiguousMatrix(len(data), GetShape(data).Cols)
+	for i := range data {
+		copy(res.Data[i], data[i])
+	}
+
+	return res
 }
 
 func (m *Matrix) Transpose() {
-	m.Data = TransposeMatrix(m.Data)
+	*m = NewMatrix(TransposeMatrix(m.Data))
 }
 
 func (m *Matrix) Center() {
-	m.Data = CenterMatrix(*m).Data
+	*m = CenterMatrix(*m)
 }
 
 // ReadCSVToMatrixFromFile reads a CSV file and returns a Matrix struct
@@ -1752,29 +1667,28 @@ func ReadCSVToMatrixFromFile(filePath string, skipHeader bool) Matrix {
 }
 
 // NewMatrixFromReader reads CSV data from an io.Reader and returns a Matrix struct
+// All records are read into memory, CovarianceAccumulator.AddCSV computes the
+// means and covariance of a CSV stream one row at a time instead
 func NewMatrixFromReader(reader io.Reader, skipHeader bool) Matrix {
-	csvreader := csv.NewReader(reader)
-	records, err := csvreader.ReadAll()
+	m, err := TryNewMatrixFromReader(reader, skipHeader)
 	if err != nil {
 		panic(err)
 	}
-	if skipHeader {
-		records = records[1:]
-	}
 
-	matrixData := make([][]float64, len(records))
-	for i, record := range records {
-		matrixData[i] = make([]float64, len(record))
-		for j, value := range record {
-			floatVal, err := strconv.ParseFloat(value, 64)
-			if err != nil {
-				panic(err)
-			}
-			matrixData[i][j] = floatVal
+	return m
+}
+
+func parseCSVRecord(record []string) ([]float64, error) {
+	row := make([]float64, len(record))
+	for j, value := range record {
+		floatVal, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
 		}
+		row[j] = floatVal
 	}
 
-	return Matrix{Data: matrixData}
+	return row, nil
 }
 
 // GetCovarianceMatrix returns the covariance matrix of the given matrix
@@ -1810,7 +1724,8 @@ func CenterMatrix(m Matrix) Matrix {
 		return m
 	}
 
-	centeredData := CopyMatrix(m.Data)
+	centered := NewMatrix(m.Data)
+	centeredData := centered.Data
 	for col := range centeredData[0] {
 		var sum float64
 		for row := range centeredData {
@@ -1822,7 +1737,7 @@ func CenterMatrix(m Matrix) Matrix {
 		}
 	}
 
-	return Matrix{Data: centeredData}
+	return centered
 }
 
 func (m *Matrix) SetIndex(i, j int, value float64) {
@@ -1834,13 +1749,19 @@ func (m *Matrix) SetIndex(i, j int, value float64) {
 }
 
 func (m *Matrix) Copy() *Matrix {
-	newmatrix := &Matrix{Data: CopyMatrix(m.Data)}
-	return newmatrix
+	newmatrix := NewMatrix(m.Data)
+	return &newmatrix
 }
 
 func (m *Matrix) MultiplyMatrix(matrixB *Matrix) Matrix {
-	res := MultiplyMatrices(m.Data, matrixB.Data)
-	return Matrix{Data: res}
+	if !CanMultiplyMatrices(m.Data, matrixB.Data) {
+		panic("invalid multiplication")
+	}
+
+	res := newContiguousMatrix(len(m.Data), matrixB.Cols())
+	multiplyInto(res.Data, m.Data, matrixB.Data)
+
+	return res
 }
 
 func (m *Matrix) GetColumn(columnIndex int) []float64 {
@@ -1895,72 +1816,22 @@ type SVDResult struct {
 	U Matrix
 	S Matrix
 	V Matrix
+	// SingularValues are the diagonal of S in descending order
+	SingularValues []float64
 }
 
 // SVD performs Singular Value Decomposition on a matrix A
 // It returns matrices U, S, and V such that A = U * S * V^T
+// For an m x n matrix with k = min(m, n), U is m x k, S is k x k and V is n x k.
+// The singular values are sorted in descending order and the columns of U and V
+// are orthonormal, also when A is rank deficient. Every column of V has its entry
+// of largest magnitude positive, see SignLargestPositive. Use SVDWithMode for the
+// full decomposition, SVDWithOptions to turn the sign convention off and
+// TruncatedSVD for the largest singular values only.
+// It panics if the Jacobi sweeps do not converge, which only happens for NaN
+// or infinite entries.
 func SVD(m *Matrix) SVDResult {
-	// compute AtA to find eigenvalues, we need a square matrix
-	At := TransposeMatrix(m.Data)
-	AtA := MultiplyMatrices(At, m.Data)
-	eigenValues := GetEigenvalues(AtA)
-	// build singular values from eigenvalues of AtA
-	singularValues := make([]float64, len(eigenValues))
-	for i, val := range eigenValues {
-		// the singular values are the square root of the eigenvalues of AtA
-		// we only take the real part of the eigenvalues, if the real part is
-		//  negative we set the singular value to 0
-		if real(val) < 0 {
-			singularValues[i] = 0
-		} else {
-			singularValues[i] = math.Sqrt(real(val))
-		}
-	}
-
-	// create diagonal matrix S with singular values
-	// this is also called D or Sigma in some notations
-	Dmatrix := make([][]float64, len(singularValues))
-	// fill with 0s
-	for i := range Dmatrix {
-		Dmatrix[i] = make([]float64, len(singularValues))
-	}
-	for i := range singularValues {
-		Dmatrix[i][i] = singularValues[i]
-	}
-
-	// compute V by finding the eigenvectors of AtA
-	vectors := GetEigenvectors(AtA)
-	V := make([][]float64, len(vectors))
-	for i := range vectors {
-		V[i] = make([]float64, len(vectors))
-		for j := range vectors {
-			V[i][j] = real(vectors[i][j])
-		}
-	}
-
-	// compute U as A * V * S^-1
-	SInv := make([][]float64, len(Dmatrix))
-	for i := range Dmatrix {
-		SInv[i] = make([]float64, len(Dmatrix))
-		for j := range Dmatrix {
-			if Dmatrix[i][j] != 0 {
-				SInv[i][j] = 1 / Dmatrix[i][j]
-			} else {
-				SInv[i][j] = 0
-			}
-		}
-	}
-
-	AV := MultiplyMatrices(m.Data, V)
-	U := MultiplyMatrices(AV, SInv)
-
-	svd := SVDResult{
-		U: Matrix{Data: U},
-		S: Matrix{Data: Dmatrix},
-		V: Matrix{Data: V},
-	}
-
-	return svd
+	return SVDWithMode(m, SVDThin)
 }
 
 // GetMean returns the mean of a slice of float64 numbers
@@ -1981,16 +1852,18 @@ func GetMean[T float64 | int | int64 | float32](nums []T) float64 {
 type PrincipalComponent struct {
 	// the vector is the eigenvector of the covariance matrix,
 	// it represents the direction of maximum variance in the data
-	Vector []float64
+	Vector []float64 `json:"vector"`
 
 	// the variance is the eigenvalue of the covariance matrix,
 	// it represents the amount of variance in the data that is
 	// explained by this principal component
-	Variance float64
+	Variance float64 `json:"variance"`
 }
 
 // GetScore projects a data point onto the principal component vector to
 // get the score of that data point on this principal component
+// The data point must already be centered, PCAModel.Transform centers it with
+// the means of the training data
 func (pc PrincipalComponent) GetScore(data []float64) float64 {
 	if len(data) != len(pc.Vector) {
 		panic("data length must match principal component vector length")
@@ -2019,6 +1892,8 @@ func (pc PrincipalComponent) GetExplainedVarianceRatio(totalVariance float64) fl
 }
 
 // PCA finds the the top principal components
+// The sign of every component vector follows SignLargestPositive.
+// The column means are not kept, use FitPCA for a model that can project new data
 func PCA(m Matrix) []PrincipalComponent {
 	centeredMatrix := m.Copy()
 	// center the data to have mean 0
@@ -2045,7 +1920,7 @@ func PCA(m Matrix) []PrincipalComponent {
 
 		ep := EigenPair{
 			Value:  variance,
-			Vector: svd.V.Data[i],
+			Vector: svd.V.GetColumn(i),
 		}
 		eigenPairs = append(eigenPairs, ep)
 	}
@@ -2065,6 +1940,33 @@ func PCA(m Matrix) []PrincipalComponent {
 	return principalComponents
 }
 
+// ProjectPrincipalComponents returns the n x k matrix of scores of the n rows of
+// data on k principal components, column j holds data * components[j].Vector.
+// Like GetScore it does not center the data. data only has to multiply a vector,
+// so it can be a sparse matrix or an implicit LinearOperator.
+func ProjectPrincipalComponents(data LinearOperator, components []PrincipalComponent) (Matrix, error) {
+	rows, cols := data.Dims()
+	res := NewZeroMatrix(rows, len(components))
+	for j, pc := range components {
+		if len(pc.Vector) != cols {
+			return Matrix{}, &ShapeError{
+				Op:     "ProjectPrincipalComponents",
+				Shapes: []Shape{{Rows: rows, Cols: cols}, {Rows: len(pc.Vector), Cols: 1}},
+				Err:    ErrDimensionMismatch,
+			}
+		}
+		scores, err := data.MulVec(pc.Vector)
+		if err != nil {
+			return Matrix{}, err
+		}
+		for i, score := range scores {
+			res.Data[i][j] = score
+		}
+	}
+
+	return res, nil
+}
+
 // solveLinearSystem solves Ax = b using Gaussian elimination with partial pivoting.
 // Returns the solution vector x.
 // solveLinearSystem is used in inverse iteration to solve (A - σI)y = x at each step.
@@ -3423,6 +3423,176 @@ func TestGetMatrixDeterminant(t *testing.T) {
 	}
 }
 
+// laplaceDeterminant is the cofactor expansion along the first row,
+// it is used as a reference for the LU based determinant of larger matrices
+func laplaceDeterminant(matrix [][]float64) float64 {
+	if len(matrix) == 1 {
+		return matrix[0][0]
+	}
+
+	var det float64
+	for col := range matrix[0] {
+		minor := GetMinor(matrix, 0, col)
+		det += math.Pow(-1, float64(col)) * matrix[0][col] * laplaceDeterminant(minor)
+	}
+
+	return det
+}
+
+func TestGetDeterminantMatchesLaplaceExpansion(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+	}{
+		{
+			name: "4x4 needs pivoting",
+			matrix: [][]float64{
+				{0, 2, 1, 4},
+				{3, 0, 2, 1},
+				{1, 5, 0, 2},
+				{2, 1, 3, 0},
+			},
+		},
+		{
+			name: "6x6 non integer",
+			matrix: [][]float64{
+				{0.5, 1.25, -2, 3, 0.1, 4},
+				{2, -1, 0.75, 1, 5, -3},
+				{1.5, 2.5, 3.5, -4.5, 0, 1},
+				{-2, 0, 1, 2, -1, 0.5},
+				{3, -2.25, 0, 1, 2, 1},
+				{0, 1, -1, 0.5, 3, 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := laplaceDeterminant(tt.matrix)
+			got := GetDeterminant(tt.matrix)
+			if math.Abs(got-want) > 1e-9*math.Max(1, math.Abs(want)) {
+				t.Errorf("GetDeterminant() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestGetDeterminantLargeMatrix(t *testing.T) {
+	// laplace expansion of a 12x12 matrix needs 12! minors, the LU path is instant.
+	// A lower triangular matrix with 2s on the diagonal has determinant 2^n
+	n := 12
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			matrix[i][j] = float64(j + 1)
+		}
+		matrix[i][i] = 2
+	}
+
+	got := GetDeterminant(matrix)
+	if want := math.Pow(2, float64(n)); math.Abs(got-want) > 1e-9*want {
+		t.Errorf("GetDeterminant() = %v, want %v", got, want)
+	}
+
+	// the cofactor matrix needs n^2 determinants of (n-1)x(n-1) minors
+	cofactors := GetCofactorMatrix(matrix)
+	if len(cofactors) != n {
+		t.Fatalf("GetCofactorMatrix() returned %d rows, want %d", len(cofactors), n)
+	}
+}
+
+func TestGetLogDeterminant(t *testing.T) {
+	tests := []struct {
+		name          string
+		matrix        [][]float64
+		wantSign      float64
+		wantLogAbsDet float64
+	}{
+		{
+			name:          "2x2 negative determinant",
+			matrix:        [][]float64{{1, 2}, {3, 4}},
+			wantSign:      -1,
+			wantLogAbsDet: math.Log(2),
+		},
+		{
+			name:          "5x5",
+			matrix:        [][]float64{{2, 0, 1, 3, 0}, {1, -1, 2, 1, 0}, {3, 2, 0, -2, 1}, {4, 1, -3, 0, 2}, {5, 2, 1, 4, 3}},
+			wantSign:      1,
+			wantLogAbsDet: math.Log(183),
+		},
+		{
+			name:          "singular",
+			matrix:        [][]float64{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}, {13, 14, 15, 16}},
+			wantSign:      0,
+			wantLogAbsDet: math.Inf(-1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sign, logAbsDet := GetLogDeterminant(tt.matrix)
+			if sign != tt.wantSign {
+				t.Errorf("GetLogDeterminant() sign = %v, want %v", sign, tt.wantSign)
+			}
+			if math.IsInf(tt.wantLogAbsDet, -1) {
+				if !math.IsInf(logAbsDet, -1) {
+					t.Errorf("GetLogDeterminant() logAbsDet = %v, want -Inf", logAbsDet)
+				}
+				return
+			}
+			if math.Abs(logAbsDet-tt.wantLogAbsDet) > 1e-12 {
+				t.Errorf("GetLogDeterminant() logAbsDet = %v, want %v", logAbsDet, tt.wantLogAbsDet)
+			}
+		})
+	}
+}
+
+func TestGetLogDeterminantIllScaled(t *testing.T) {
+	// det(0.001 * I_400) = 1e-1200 underflows to 0 but its log does not
+	n := 400
+	matrix := MultiplyMatrixByScalar(GenerateIdentityMatrix(n), 1e-3)
+	sign, logAbsDet := GetLogDeterminant(matrix)
+	if sign != 1 {
+		t.Errorf("GetLogDeterminant() sign = %v, want 1", sign)
+	}
+	if want := float64(n) * math.Log(1e-3); math.Abs(logAbsDet-want) > 1e-9 {
+		t.Errorf("GetLogDeterminant() logAbsDet = %v, want %v", logAbsDet, want)
+	}
+}
+
+func TestGetDeterminantBadlyScaled(t *testing.T) {
+	// the pivots are far apart in size but none of them is rounding noise
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   float64
+	}{
+		{
+			name:   "diagonal",
+			matrix: [][]float64{{1e20, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}},
+			want:   1e20,
+		},
+		{
+			name:   "scaled column",
+			matrix: [][]float64{{1e20, 1, 0, 0}, {1e20, 2, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}},
+			want:   1e20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetDeterminant(tt.matrix); math.Abs(got-tt.want) > 1e-12*tt.want {
+				t.Errorf("GetDeterminant() = %v, want %v", got, tt.want)
+			}
+			sign, logAbsDet := GetLogDeterminant(tt.matrix)
+			if want := math.Log(tt.want); sign != 1 || math.Abs(logAbsDet-want) > 1e-12 {
+				t.Errorf("GetLogDeterminant() = (%v, %v), want (1, %v)", sign, logAbsDet, want)
+			}
+			if !IsMatrixInvertible(tt.matrix) {
+				t.Errorf("IsMatrixInvertible() = false, want true")
+			}
+		})
+	}
+}
+
 func TestGetMinor(t *testing.T) {
 	type args struct {
 		matrix [][]float64
@@ -4106,6 +4276,31 @@ func TestGetInverseMatrixByDeterminant(t *testing.T) {
 	}
 }
 
+func TestGetInverseMatrixByDeterminantGaussJordan(t *testing.T) {
+	matrix := [][]float64{
+		{0, 2, 1, 4, 1},
+		{3, 0, 2, 1, 0},
+		{1, 5, 0, 2, 2},
+		{2, 1, 3, 0, 1},
+		{1, 1, 1, 1, 1},
+	}
+	original := CopyMatrix(matrix)
+
+	got := GetInverseMatrixByDeterminant(matrix)
+	if !areMatricesEqual(matrix, original) {
+		t.Errorf("GetInverseMatrixByDeterminant() modified its input")
+	}
+
+	// the gauss jordan inverse must match the adjugate formula
+	want := MultiplyMatrixByScalar(GetAdjugateMatrix(matrix), 1/laplaceDeterminant(matrix))
+	if !areMatricesEqual(got, want) {
+		t.Errorf("GetInverseMatrixByDeterminant() = %v, want %v", got, want)
+	}
+	if product := MultiplyMatrices(matrix, got); !areMatricesEqual(product, GenerateIdentityMatrix(5)) {
+		t.Errorf("A * A^-1 = %v, want identity", product)
+	}
+}
+
 func TestGetMatrixNullity(t *testing.T) {
 	type args struct {
 		matrix [][]float64
@@ -5393,8 +5588,10 @@ func TestSVDAdditionalCases(t *testing.T) {
 			},
 			checkReconstruction: true,
 			checkVOrtho:         true,
-			checkUOrtho:         false,
-			maxMinSigma:         &maxMinSigmaRankDef,
+			// this used to be false because U = A * V * S^-1 had a 0 column for the
+			// 0 singular value, SVD now completes U to an orthonormal basis
+			checkUOrtho: true,
+			maxMinSigma: &maxMinSigmaRankDef,
 		},
 	}
 
@@ -0,0 +1,135 @@
+package linearalgebra
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadMatrixMarket reads a sparse matrix in the MatrixMarket coordinate format
+//
+//	%%MatrixMarket matrix coordinate real general
+//	% comments
+//	rows cols entries
+//	i j value
+//
+// with 1 based indices. The field can be real, integer or pattern, where every
+// listed entry is 1. The symmetry can be general, symmetric or skew-symmetric,
+// only the lower triangle is listed for the last two and it is mirrored here.
+// The entries are streamed into the COO without reading the whole file first.
+// Malformed input returns an error wrapping ErrInvalidFormat.
+func ReadMatrixMarket(reader io.Reader) (*COO, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("ReadMatrixMarket: line %d: %w: %s", line, ErrInvalidFormat, fmt.Sprintf(format, args...))
+	}
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fail("empty input")
+	}
+	line++
+	header := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(header) != 5 || header[0] != "%%matrixmarket" || header[1] != "matrix" {
+		return nil, fail("missing %%%%MatrixMarket matrix header")
+	}
+	if header[2] != "coordinate" {
+		return nil, fail("unsupported format %q, only coordinate is supported", header[2])
+	}
+	field, symmetry := header[3], header[4]
+	switch field {
+	case "real", "integer", "pattern":
+	default:
+		return nil, fail("unsupported field %q", field)
+	}
+	switch symmetry {
+	case "general", "symmetric", "skew-symmetric":
+	default:
+		return nil, fail("unsupported symmetry %q", symmetry)
+	}
+
+	var coo *COO
+	entries, read := 0, 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "%") {
+			continue
+		}
+		fields := strings.Fields(text)
+
+		if coo == nil {
+			// the size line
+			if len(fields) != 3 {
+				return nil, fail("want rows cols entries, got %q", text)
+			}
+			sizes := make([]int, 3)
+			for k, f := range fields {
+				n, err := strconv.Atoi(f)
+				if err != nil || n < 0 {
+					return nil, fail("invalid size %q", f)
+				}
+				sizes[k] = n
+			}
+			coo = NewCOO(sizes[0], sizes[1])
+			entries = sizes[2]
+			continue
+		}
+
+		wantFields := 3
+		if field == "pattern" {
+			wantFields = 2
+		}
+		if len(fields) != wantFields {
+			return nil, fail("want %d fields, got %q", wantFields, text)
+		}
+		i, errI := strconv.Atoi(fields[0])
+		j, errJ := strconv.Atoi(fields[1])
+		if errI != nil || errJ != nil {
+			return nil, fail("invalid index in %q", text)
+		}
+		value := 1.0
+		if field != "pattern" {
+			v, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fail("invalid value %q", fields[2])
+			}
+			value = v
+		}
+
+		if err := coo.Add(i-1, j-1, value); err != nil {
+			return nil, fail("%v", err)
+		}
+		if i != j {
+			switch symmetry {
+			case "symmetric":
+				if err := coo.Add(j-1, i-1, value); err != nil {
+					return nil, fail("%v", err)
+				}
+			case "skew-symmetric":
+				if err := coo.Add(j-1, i-1, -value); err != nil {
+					return nil, fail("%v", err)
+				}
+			}
+		}
+		read++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if coo == nil {
+		return nil, fail("missing size line")
+	}
+	if read != entries {
+		return nil, fail("read %d entries, the size line says %d", read, entries)
+	}
+
+	return coo, nil
+}
@@ -0,0 +1,94 @@
+package linearalgebra
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  [][]float64
+	}{
+		{
+			name: "real general",
+			input: `%%MatrixMarket matrix coordinate real general
+% a comment
+3 3 4
+1 1 1.5
+2 3 -2
+3 1 4e1
+3 3 1
+`,
+			want: [][]float64{{1.5, 0, 0}, {0, 0, -2}, {40, 0, 1}},
+		},
+		{
+			name: "integer symmetric",
+			input: `%%MatrixMarket matrix coordinate integer symmetric
+3 3 4
+1 1 2
+2 1 -1
+2 2 2
+3 2 -1
+`,
+			want: [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 0}},
+		},
+		{
+			name: "real skew-symmetric",
+			input: `%%MatrixMarket matrix coordinate real skew-symmetric
+2 2 1
+2 1 3
+`,
+			want: [][]float64{{0, -3}, {3, 0}},
+		},
+		{
+			name: "pattern rectangular",
+			input: `%%MatrixMarket matrix coordinate pattern general
+
+2 4 3
+1 2
+2 4
+1 3
+`,
+			want: [][]float64{{0, 1, 1, 0}, {0, 0, 0, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coo, err := ReadMatrixMarket(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadMatrixMarket() unexpected error: %v", err)
+			}
+			if got := coo.ToCSR().ToMatrix(); !areMatricesEqual(got.Data, tt.want) {
+				t.Errorf("ReadMatrixMarket() = %v, want %v", got.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMatrixMarketErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "no header", input: "2 2 1\n1 1 1\n"},
+		{name: "array format", input: "%%MatrixMarket matrix array real general\n2 2\n1\n2\n3\n4\n"},
+		{name: "complex field", input: "%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 0\n"},
+		{name: "missing size", input: "%%MatrixMarket matrix coordinate real general\n"},
+		{name: "index out of range", input: "%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n"},
+		{name: "zero based index", input: "%%MatrixMarket matrix coordinate real general\n2 2 1\n0 1 1\n"},
+		{name: "bad value", input: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1 x\n"},
+		{name: "too few entries", input: "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadMatrixMarket(strings.NewReader(tt.input))
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("ReadMatrixMarket() error = %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+}
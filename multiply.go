@@ -0,0 +1,107 @@
+package linearalgebra
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// multiplyBlockSize is the side of the tiles of A and B that are multiplied
+	// together, 64x64 float64 tiles of both fit in a 64KB L1/L2 cache
+	multiplyBlockSize = 64
+	// multiplyParallelMin is the number of multiply-adds under which
+	// starting goroutines costs more than it saves
+	multiplyParallelMin = 64 * 64 * 64
+)
+
+// multiplyWorkers is the number of goroutines MultiplyMatrices uses, 0 means GOMAXPROCS
+var multiplyWorkers atomic.Int64
+
+// SetMultiplyWorkers sets the number of goroutines used by MultiplyMatrices and
+// Matrix.MultiplyMatrix and returns the previous setting.
+// n <= 0 uses runtime.GOMAXPROCS, which is the default. The result does not
+// depend on the number of workers.
+func SetMultiplyWorkers(n int) int {
+	if n < 0 {
+		n = 0
+	}
+
+	return int(multiplyWorkers.Swap(int64(n)))
+}
+
+// MultiplyWorkers returns the number of goroutines used by MultiplyMatrices
+func MultiplyWorkers() int {
+	if n := multiplyWorkers.Load(); n > 0 {
+		return int(n)
+	}
+
+	return runtime.GOMAXPROCS(0)
+}
+
+// multiplyInto adds matrixA * matrixB to dst.
+// The rows of dst are split into bands that are computed in parallel, each
+// goroutine owns its rows so no locking is needed. Inside a band the product is
+// computed tile by tile in i-k-j order: the inner loop walks a row of B and a
+// row of dst, both contiguous, instead of a column of B.
+// Every dst[i][j] still adds the products A[i][z]*B[z][j] in increasing z, the
+// same order as the textbook triple loop, so the result is the same bit for bit.
+func multiplyInto(dst, matrixA, matrixB [][]float64) {
+	rows, inner, cols := len(dst), len(matrixB), GetShape(dst).Cols
+	if rows == 0 || cols == 0 {
+		return
+	}
+
+	workers := MultiplyWorkers()
+	if rows*inner*cols < multiplyParallelMin {
+		workers = 1
+	}
+	bands := (rows + multiplyBlockSize - 1) / multiplyBlockSize
+	workers = min(workers, bands)
+
+	if workers <= 1 {
+		multiplyRows(dst, matrixA, matrixB, 0, rows)
+		return
+	}
+
+	// hand out bands of rows to the workers as they become free
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				band := int(next.Add(1)) - 1
+				if band >= bands {
+					return
+				}
+				i0 := band * multiplyBlockSize
+				multiplyRows(dst, matrixA, matrixB, i0, min(i0+multiplyBlockSize, rows))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// multiplyRows computes the rows [i0, i1) of dst += matrixA * matrixB with tiling
+func multiplyRows(dst, matrixA, matrixB [][]float64, i0, i1 int) {
+	inner, cols := len(matrixB), len(dst[i0])
+	for k0 := 0; k0 < inner; k0 += multiplyBlockSize {
+		k1 := min(k0+multiplyBlockSize, inner)
+		for j0 := 0; j0 < cols; j0 += multiplyBlockSize {
+			j1 := min(j0+multiplyBlockSize, cols)
+			for i := i0; i < i1; i++ {
+				a := matrixA[i]
+				out := dst[i][j0:j1]
+				for k := k0; k < k1; k++ {
+					aik := a[k]
+					b := matrixB[k][j0:j1]
+					for j := range out {
+						out[j] += aik * b[j]
+					}
+				}
+			}
+		}
+	}
+}
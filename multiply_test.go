@@ -0,0 +1,121 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// multiplyMatricesNaive is the textbook triple loop that MultiplyMatrices
+// used before tiling, kept as the reference for tests and benchmarks
+func multiplyMatricesNaive(matrixA, matrixB [][]float64) [][]float64 {
+	newMatrix := make([][]float64, len(matrixA))
+	for i := range newMatrix {
+		newMatrix[i] = make([]float64, len(matrixB[0]))
+	}
+
+	for i := range newMatrix {
+		for j := range newMatrix[i] {
+			for z := range matrixB {
+				newMatrix[i][j] += float64(matrixA[i][z]) * float64(matrixB[z][j])
+			}
+		}
+	}
+
+	return newMatrix
+}
+
+func randomMatrix(rng *rand.Rand, rows, cols int) [][]float64 {
+	res := make([][]float64, rows)
+	for i := range res {
+		res[i] = make([]float64, cols)
+		for j := range res[i] {
+			res[i][j] = rng.NormFloat64()
+		}
+	}
+
+	return res
+}
+
+func TestMultiplyMatricesMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name    string
+		rows    int
+		inner   int
+		cols    int
+		workers int
+	}{
+		{name: "small serial", rows: 3, inner: 4, cols: 5, workers: 4},
+		{name: "one block", rows: 64, inner: 64, cols: 64, workers: 1},
+		{name: "ragged blocks one worker", rows: 130, inner: 97, cols: 150, workers: 1},
+		{name: "ragged blocks four workers", rows: 130, inner: 97, cols: 150, workers: 4},
+		{name: "more workers than bands", rows: 70, inner: 200, cols: 65, workers: 16},
+		{name: "tall times wide", rows: 300, inner: 2, cols: 300, workers: 3},
+		{name: "row vector", rows: 1, inner: 500, cols: 300, workers: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := SetMultiplyWorkers(tt.workers)
+			defer SetMultiplyWorkers(previous)
+
+			A := randomMatrix(rng, tt.rows, tt.inner)
+			B := randomMatrix(rng, tt.inner, tt.cols)
+			want := multiplyMatricesNaive(A, B)
+			got := MultiplyMatrices(A, B)
+			for i := range want {
+				for j := range want[i] {
+					if got[i][j] != want[i][j] {
+						t.Fatalf("MultiplyMatrices()[%d][%d] = %v, want %v", i, j, got[i][j], want[i][j])
+					}
+				}
+			}
+
+			m := Matrix{Data: A}
+			product := m.MultiplyMatrix(&Matrix{Data: B})
+			if !areMatricesEqual(product.Data, want) {
+				t.Errorf("Matrix.MultiplyMatrix() differs from MultiplyMatrices()")
+			}
+		})
+	}
+}
+
+func TestSetMultiplyWorkers(t *testing.T) {
+	previous := SetMultiplyWorkers(3)
+	defer SetMultiplyWorkers(previous)
+
+	if got := MultiplyWorkers(); got != 3 {
+		t.Errorf("MultiplyWorkers() = %d, want 3", got)
+	}
+	if got := SetMultiplyWorkers(-1); got != 3 {
+		t.Errorf("SetMultiplyWorkers() = %d, want 3", got)
+	}
+	if got := MultiplyWorkers(); got < 1 {
+		t.Errorf("MultiplyWorkers() = %d, want GOMAXPROCS", got)
+	}
+}
+
+func BenchmarkMultiplyMatrices(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{64, 128, 256, 512, 1024, 2048} {
+		A := randomMatrix(rng, n, n)
+		B := randomMatrix(rng, n, n)
+		b.Run(fmt.Sprintf("naive_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				multiplyMatricesNaive(A, B)
+			}
+		})
+		b.Run(fmt.Sprintf("tiled_1_worker_%d", n), func(b *testing.B) {
+			previous := SetMultiplyWorkers(1)
+			defer SetMultiplyWorkers(previous)
+			for i := 0; i < b.N; i++ {
+				MultiplyMatrices(A, B)
+			}
+		})
+		b.Run(fmt.Sprintf("tiled_parallel_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				MultiplyMatrices(A, B)
+			}
+		})
+	}
+}
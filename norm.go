@@ -0,0 +1,325 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math"
+)
+
+// VectorNorm returns the p-norm of a vector, (|x_1|^p + ... + |x_n|^p)^(1/p),
+// for p >= 1. p = math.Inf(1) gives the largest absolute entry.
+// The entries are divided by the largest one before they are raised to p,
+// so the norm does not overflow or underflow unless the result itself does.
+// It returns ErrInvalidArgument if p is smaller than 1 or NaN.
+func VectorNorm(vector []float64, p float64) (float64, error) {
+	if !(p >= 1) {
+		return 0, fmt.Errorf("VectorNorm: %w: p = %v, want p >= 1", ErrInvalidArgument, p)
+	}
+
+	switch {
+	case p == 1:
+		sum := 0.0
+		for _, v := range vector {
+			sum += math.Abs(v)
+		}
+		return sum, nil
+	case p == 2:
+		return norm2(vector), nil
+	case math.IsInf(p, 1):
+		return maxAbs(vector), nil
+	}
+
+	scale := maxAbs(vector)
+	if scale == 0 || math.IsInf(scale, 1) {
+		return scale, nil
+	}
+	sum := 0.0
+	for _, v := range vector {
+		sum += math.Pow(math.Abs(v)/scale, p)
+	}
+
+	return scale * math.Pow(sum, 1/p), nil
+}
+
+// maxAbs returns the largest absolute value in the vector
+func maxAbs(vector []float64) float64 {
+	res := 0.0
+	for _, v := range vector {
+		if a := math.Abs(v); a > res {
+			res = a
+		}
+	}
+
+	return res
+}
+
+// MatrixNorm selects the norm computed by Matrix.Norm
+type MatrixNorm int
+
+const (
+	// NormOne is the largest sum of absolute values of a column
+	NormOne MatrixNorm = iota
+	// NormInf is the largest sum of absolute values of a row
+	NormInf
+	// NormFrobenius is the square root of the sum of the squares of all entries
+	NormFrobenius
+	// NormMaxAbs is the largest absolute value of an entry, it is not submultiplicative
+	NormMaxAbs
+	// NormSpectral is the largest singular value, the factor by which A stretches
+	// a vector the most in the euclidean norm
+	NormSpectral
+)
+
+// String returns the name of the norm
+func (n MatrixNorm) String() string {
+	switch n {
+	case NormOne:
+		return "1"
+	case NormInf:
+		return "inf"
+	case NormFrobenius:
+		return "frobenius"
+	case NormMaxAbs:
+		return "max-abs"
+	case NormSpectral:
+		return "spectral"
+	}
+	return fmt.Sprintf("MatrixNorm(%d)", int(n))
+}
+
+// Norm returns the given norm of the matrix, 0 for an empty matrix.
+// The spectral norm needs an SVD, the other ones a single pass over the entries.
+// It returns an error if the rows have different lengths or kind is unknown.
+func (m Matrix) Norm(kind MatrixNorm) (float64, error) {
+	if err := checkRectangular("Matrix.Norm", m.Data); err != nil {
+		return 0, err
+	}
+	rows, cols := m.Dims()
+	if rows == 0 || cols == 0 {
+		return 0, nil
+	}
+
+	switch kind {
+	case NormOne:
+		res := 0.0
+		for j := 0; j < cols; j++ {
+			sum := 0.0
+			for i := 0; i < rows; i++ {
+				sum += math.Abs(m.Data[i][j])
+			}
+			res = math.Max(res, sum)
+		}
+		return res, nil
+	case NormInf:
+		res := 0.0
+		for i := range m.Data {
+			sum := 0.0
+			for _, v := range m.Data[i] {
+				sum += math.Abs(v)
+			}
+			res = math.Max(res, sum)
+		}
+		return res, nil
+	case NormFrobenius:
+		return norm2(m.Flat()), nil
+	case NormMaxAbs:
+		return maxAbsEntry(m.Data), nil
+	case NormSpectral:
+		sigma, scale, err := scaledSingularValues(m.Data)
+		if err != nil {
+			return 0, err
+		}
+		return scale * sigma[0], nil
+	}
+
+	return 0, fmt.Errorf("Matrix.Norm: %w: unknown norm %v", ErrInvalidArgument, kind)
+}
+
+// scaledSingularValues returns the singular values of A / scale in descending
+// order and scale, the largest absolute entry of A. Dividing first keeps the
+// sums of squares of the Jacobi rotations from overflowing or underflowing.
+func scaledSingularValues(matrix [][]float64) ([]float64, float64, error) {
+	scale := maxAbsEntry(matrix)
+	if scale == 0 {
+		return make([]float64, min(len(matrix), len(matrix[0]))), 0, nil
+	}
+
+	scaled := CopyMatrix(matrix)
+	for i := range scaled {
+		for j := range scaled[i] {
+			scaled[i][j] /= scale
+		}
+	}
+	_, sigma, _, err := jacobiSVD(scaled)
+
+	return sigma, scale, err
+}
+
+// Cond returns the condition number of A in the spectral norm, the ratio of
+// its largest to its smallest singular value, computed with an SVD.
+// For an m x n matrix the smallest of the min(m, n) singular values is used.
+// Solving A * x = b can lose up to log10(Cond) digits of accuracy.
+// A singular matrix returns +Inf. See CondEstimate for a cheaper estimate.
+// It returns an error if the matrix is empty or the rows have different lengths.
+func Cond(m Matrix) (float64, error) {
+	if err := checkRectangular("Cond", m.Data); err != nil {
+		return 0, err
+	}
+	if m.Rows() == 0 || m.Cols() == 0 {
+		return 0, fmt.Errorf("Cond: %w: empty matrix", ErrInvalidArgument)
+	}
+
+	sigma, _, err := scaledSingularValues(m.Data)
+	if err != nil {
+		return 0, err
+	}
+	smallest := sigma[len(sigma)-1]
+	if smallest == 0 {
+		return math.Inf(1), nil
+	}
+
+	return sigma[0] / smallest, nil
+}
+
+// CondEstimate estimates the condition number of a square matrix in the
+// 1-norm from its LU factorization, see LU.CondEstimate.
+// It returns an error if the matrix is not square or empty.
+func CondEstimate(m Matrix) (float64, error) {
+	if err := checkSquare("CondEstimate", m.Data); err != nil {
+		return 0, err
+	}
+	if m.Rows() == 0 {
+		return 0, fmt.Errorf("CondEstimate: %w: empty matrix", ErrInvalidArgument)
+	}
+
+	lu, err := NewLU(m)
+	if err != nil {
+		return 0, err
+	}
+
+	return lu.CondEstimate(), nil
+}
+
+// CondEstimate estimates ||A||_1 * ||A^-1||_1 without forming A^-1. The norm of
+// A^-1 is estimated with the method of Hager and Higham, which climbs towards
+// the column of A^-1 with the largest sum by solving a few systems with A and A^T,
+// O(n^2) work on top of the factorization instead of the O(n^3) of an SVD.
+// The estimate is a lower bound of the true value and almost always within a
+// factor of 3 of it. A singular matrix returns +Inf.
+func (f LU) CondEstimate() float64 {
+	n := len(f.lu)
+	if n == 0 {
+		return 0
+	}
+	if f.IsSingular() {
+		return math.Inf(1)
+	}
+
+	solve := func(x []float64) []float64 {
+		permuted := make([]float64, n)
+		for i := range x {
+			permuted[i] = x[f.Pivots[i]]
+		}
+		f.solveInPlace(permuted)
+		return permuted
+	}
+
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 1 / float64(n)
+	}
+	estimate := 0.0
+	for iter := 0; iter < 5; iter++ {
+		y := solve(x)
+		norm, _ := VectorNorm(y, 1)
+		if iter > 0 && norm <= estimate {
+			break
+		}
+		estimate = norm
+
+		signs := make([]float64, n)
+		for i := range y {
+			signs[i] = 1
+			if y[i] < 0 {
+				signs[i] = -1
+			}
+		}
+		z := f.solveTranspose(signs)
+
+		best := 0
+		for i := range z {
+			if math.Abs(z[i]) > math.Abs(z[best]) {
+				best = i
+			}
+		}
+		if iter > 0 && math.Abs(z[best]) <= dot(z, x) {
+			break
+		}
+		for i := range x {
+			x[i] = 0
+		}
+		x[best] = 1
+	}
+
+	// Higham's safeguard against matrices that fool the climb: the alternating
+	// vector b_i = (-1)^i (1 + i / (n - 1)) gives a second lower bound
+	if n > 1 {
+		b := make([]float64, n)
+		for i := range b {
+			b[i] = 1 + float64(i)/float64(n-1)
+			if i%2 == 1 {
+				b[i] = -b[i]
+			}
+		}
+		norm, _ := VectorNorm(solve(b), 1)
+		estimate = math.Max(estimate, 2*norm/(3*float64(n)))
+	}
+
+	return f.norm1 * estimate
+}
+
+// solveTranspose solves A^T * x = b. With P * A = L * U it solves U^T * y = b by
+// forward substitution and L^T * z = y by back substitution, then x = P^T * z.
+func (f LU) solveTranspose(b []float64) []float64 {
+	n := len(f.lu)
+	z := make([]float64, n)
+	copy(z, b)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			z[i] -= f.lu[j][i] * z[j]
+		}
+		z[i] /= f.lu[i][i]
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			z[i] -= f.lu[j][i] * z[j]
+		}
+	}
+
+	x := make([]float64, n)
+	for i := range z {
+		x[f.Pivots[i]] = z[i]
+	}
+
+	return x
+}
+
+// IsMatrixInvertibleWithCond is IsMatrixInvertible that also rejects nearly
+// singular matrices: it returns false if the matrix is not square or if the
+// estimated 1-norm condition number of the matrix is larger than maxCond.
+// If maxCond is 0 or less it is 1 / machine epsilon, the condition number at
+// which a solve with the matrix has no correct digits left.
+func IsMatrixInvertibleWithCond(matrix [][]float64, maxCond float64) bool {
+	if maxCond <= 0 {
+		maxCond = 1 / machineEpsilon
+	}
+	if !IsMatrixSquare(matrix) || len(matrix) == 0 {
+		return false
+	}
+
+	cond, err := CondEstimate(Matrix{Data: matrix})
+	if err != nil {
+		return false
+	}
+
+	return cond <= maxCond
+}
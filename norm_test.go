@@ -0,0 +1,222 @@
+package linearalgebra
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVectorNorm(t *testing.T) {
+	tests := []struct {
+		name   string
+		vector []float64
+		p      float64
+		want   float64
+	}{
+		{name: "1", vector: []float64{3, -4}, p: 1, want: 7},
+		{name: "2", vector: []float64{3, -4}, p: 2, want: 5},
+		{name: "3", vector: []float64{1, 2, 2}, p: 3, want: math.Cbrt(17)},
+		{name: "inf", vector: []float64{3, -4, 1}, p: math.Inf(1), want: 4},
+		{name: "empty", vector: []float64{}, p: 2, want: 0},
+		{name: "zero", vector: []float64{0, 0}, p: 4, want: 0},
+		{name: "2 no overflow", vector: []float64{3e200, 4e200}, p: 2, want: 5e200},
+		{name: "3 no overflow", vector: []float64{1e300, 2e300, 2e300}, p: 3, want: math.Cbrt(17) * 1e300},
+		{name: "2 no underflow", vector: []float64{3e-200, -4e-200}, p: 2, want: 5e-200},
+		{name: "4 no underflow", vector: []float64{1e-200, 1e-200}, p: 4, want: math.Pow(2, 0.25) * 1e-200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VectorNorm(tt.vector, tt.p)
+			if err != nil {
+				t.Fatalf("VectorNorm() unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-14*tt.want {
+				t.Errorf("VectorNorm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, p := range []float64{0.5, 0, -1, math.NaN()} {
+		if _, err := VectorNorm([]float64{1}, p); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("VectorNorm(p = %v) error = %v, want %v", p, err, ErrInvalidArgument)
+		}
+	}
+
+	if got, want := GetVectorLength([]float64{3e200, 4e200}), 5e200; math.Abs(got-want) > 1e-14*want {
+		t.Errorf("GetVectorLength() = %v, want %v", got, want)
+	}
+	if got, want := GetVectorLength([]float64{3e-170, 4e-170}), 5e-170; math.Abs(got-want) > 1e-14*want {
+		t.Errorf("GetVectorLength() = %v, want %v", got, want)
+	}
+}
+
+func TestMatrixNorm(t *testing.T) {
+	A := [][]float64{{1, -2}, {-3, 4}}
+	spectral := math.Sqrt(15 + math.Sqrt(221))
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		kind   MatrixNorm
+		want   float64
+	}{
+		{name: "one", matrix: A, kind: NormOne, want: 6},
+		{name: "inf", matrix: A, kind: NormInf, want: 7},
+		{name: "frobenius", matrix: A, kind: NormFrobenius, want: math.Sqrt(30)},
+		{name: "max abs", matrix: A, kind: NormMaxAbs, want: 4},
+		{name: "spectral", matrix: A, kind: NormSpectral, want: spectral},
+		{name: "spectral huge", matrix: MultiplyMatrixByScalar(CopyMatrix(A), 1e300), kind: NormSpectral, want: spectral * 1e300},
+		{name: "frobenius huge", matrix: MultiplyMatrixByScalar(CopyMatrix(A), 1e300), kind: NormFrobenius, want: math.Sqrt(30) * 1e300},
+		{name: "spectral tiny", matrix: MultiplyMatrixByScalar(CopyMatrix(A), 1e-300), kind: NormSpectral, want: spectral * 1e-300},
+		{name: "wide one", matrix: [][]float64{{1, 2, 3}, {-4, 5, -6}}, kind: NormOne, want: 9},
+		{name: "wide inf", matrix: [][]float64{{1, 2, 3}, {-4, 5, -6}}, kind: NormInf, want: 15},
+		{name: "tall spectral", matrix: [][]float64{{3, 0}, {0, 0}, {4, 0}}, kind: NormSpectral, want: 5},
+		{name: "zero spectral", matrix: [][]float64{{0, 0}}, kind: NormSpectral, want: 0},
+		{name: "empty", matrix: [][]float64{}, kind: NormSpectral, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Matrix{Data: tt.matrix}.Norm(tt.kind)
+			if err != nil {
+				t.Fatalf("Norm(%v) unexpected error: %v", tt.kind, err)
+			}
+			if math.Abs(got-tt.want) > 1e-12*tt.want {
+				t.Errorf("Norm(%v) = %v, want %v", tt.kind, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (Matrix{Data: A}).Norm(MatrixNorm(42)); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Norm() error = %v, want %v", err, ErrInvalidArgument)
+	}
+	if _, err := (Matrix{Data: [][]float64{{1, 2}, {3}}}).Norm(NormOne); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Norm() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestCond(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   float64
+	}{
+		{name: "identity", matrix: GenerateIdentityMatrix(4), want: 1},
+		{name: "diagonal", matrix: [][]float64{{1, 0}, {0, 1e-8}}, want: 1e8},
+		{name: "scaled", matrix: [][]float64{{1e-200, 0}, {0, 1e-192}}, want: 1e8},
+		{name: "rotation", matrix: [][]float64{{0.6, -0.8}, {0.8, 0.6}}, want: 1},
+		{name: "tall", matrix: [][]float64{{1, 0}, {0, 2}, {0, 0}}, want: 2},
+		{name: "singular", matrix: [][]float64{{1, 2}, {2, 4}}, want: math.Inf(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Cond(NewMatrix(tt.matrix))
+			if err != nil {
+				t.Fatalf("Cond() unexpected error: %v", err)
+			}
+			if got != tt.want && math.Abs(got-tt.want) > 1e-10*tt.want {
+				t.Errorf("Cond() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Cond(Matrix{Data: [][]float64{}}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Cond() error = %v, want %v", err, ErrInvalidArgument)
+	}
+}
+
+func TestCondEstimate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	matrices := map[string][][]float64{
+		"random 5":  randomMatrix(rng, 5, 5),
+		"random 20": randomMatrix(rng, 20, 20),
+		"1x1":       {{-3}},
+		"graded":    {{1, 1, 1}, {0, 1e-4, 1e-4}, {0, 0, 1e-8}},
+	}
+	for n := 2; n <= 8; n += 3 {
+		H := hilbert(n).ToMatrix()
+		matrices[fmt.Sprintf("hilbert %d", n)] = H.Data
+	}
+
+	for name, matrix := range matrices {
+		t.Run(name, func(t *testing.T) {
+			lu, err := NewLU(NewMatrix(matrix))
+			if err != nil {
+				t.Fatalf("NewLU() unexpected error: %v", err)
+			}
+			inverse, err := lu.Inverse()
+			if err != nil {
+				t.Fatalf("Inverse() unexpected error: %v", err)
+			}
+			norm, _ := NewMatrix(matrix).Norm(NormOne)
+			inverseNorm, _ := inverse.Norm(NormOne)
+			exact := norm * inverseNorm
+
+			got, err := CondEstimate(NewMatrix(matrix))
+			if err != nil {
+				t.Fatalf("CondEstimate() unexpected error: %v", err)
+			}
+			// a lower bound that is rarely off by more than a factor of 3
+			if got > exact*(1+1e-8) || got < exact/3 {
+				t.Errorf("CondEstimate() = %v, want about %v", got, exact)
+			}
+		})
+	}
+
+	if got, err := CondEstimate(NewMatrix([][]float64{{1, 2}, {2, 4}})); err != nil || !math.IsInf(got, 1) {
+		t.Errorf("CondEstimate() = %v, %v, want +Inf", got, err)
+	}
+	if _, err := CondEstimate(NewMatrix([][]float64{{1, 2}})); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("CondEstimate() error = %v, want %v", err, ErrNotSquare)
+	}
+}
+
+func TestLUSolveTranspose(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	A := randomMatrix(rng, 6, 6)
+	lu, err := NewLU(NewMatrix(A))
+	if err != nil {
+		t.Fatalf("NewLU() unexpected error: %v", err)
+	}
+	b := []float64{1, -2, 3, 0, 5, -1}
+	x := lu.solveTranspose(b)
+	ATx, _ := NewMatrix(A).MulVecTrans(x)
+	for i := range b {
+		if math.Abs(ATx[i]-b[i]) > 1e-10 {
+			t.Errorf("A^T * x = %v, want %v", ATx, b)
+			break
+		}
+	}
+}
+
+func TestIsMatrixInvertibleWithCond(t *testing.T) {
+	// one ulp away from singular, det is 4.4e-16 and the condition number about 5e16
+	nearlySingular := [][]float64{{1, 2}, {1, math.Nextafter(2, 3)}}
+	tests := []struct {
+		name    string
+		matrix  [][]float64
+		maxCond float64
+		want    bool
+	}{
+		{name: "well conditioned", matrix: [][]float64{{2, 1}, {1, 3}}, want: true},
+		{name: "nearly singular", matrix: nearlySingular, want: false},
+		{name: "ill conditioned large maxCond", matrix: [][]float64{{1, 0}, {0, 1e-14}}, maxCond: 1e20, want: true},
+		{name: "under maxCond", matrix: [][]float64{{1, 0}, {0, 1e-3}}, maxCond: 1e4, want: true},
+		{name: "over maxCond", matrix: [][]float64{{1, 0}, {0, 1e-3}}, maxCond: 100, want: false},
+		{name: "singular", matrix: [][]float64{{1, 2}, {2, 4}}, want: false},
+		{name: "not square", matrix: [][]float64{{1, 2}}, want: false},
+		{name: "empty", matrix: [][]float64{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMatrixInvertibleWithCond(tt.matrix, tt.maxCond); got != tt.want {
+				t.Errorf("IsMatrixInvertibleWithCond() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// det != 0, so IsMatrixInvertible accepts it
+	if !IsMatrixInvertible(nearlySingular) {
+		t.Errorf("IsMatrixInvertible() = false, want true")
+	}
+}
@@ -0,0 +1,229 @@
+package linearalgebra
+
+import "fmt"
+
+// LinearOperator is anything that can multiply a vector, a dense Matrix, a sparse
+// CSR or CSC matrix, or an implicit operator that never stores the matrix at all
+// such as KroneckerOperator and ConvolutionOperator.
+// Iterative algorithms only need A * x, so they accept a LinearOperator.
+type LinearOperator interface {
+	// Dims returns the number of rows and columns
+	Dims() (rows, cols int)
+	// MulVec returns A * x
+	MulVec(x []float64) ([]float64, error)
+}
+
+// TransposableOperator is a LinearOperator that can also multiply by its transpose.
+// Matrix, CSR, CSC, KroneckerOperator and ConvolutionOperator implement it.
+type TransposableOperator interface {
+	LinearOperator
+	// MulVecTrans returns A^T * x
+	MulVecTrans(x []float64) ([]float64, error)
+}
+
+// transposedOperator is A^T for a TransposableOperator A
+type transposedOperator struct {
+	a TransposableOperator
+}
+
+// OperatorTranspose returns A^T without building it.
+// It returns ErrInvalidArgument if A does not implement TransposableOperator.
+func OperatorTranspose(a LinearOperator) (TransposableOperator, error) {
+	switch t := a.(type) {
+	case transposedOperator:
+		return t.a, nil
+	case TransposableOperator:
+		return transposedOperator{a: t}, nil
+	}
+
+	return nil, fmt.Errorf("OperatorTranspose: %w: %T has no MulVecTrans", ErrInvalidArgument, a)
+}
+
+// Dims returns the number of rows and columns of A^T
+func (t transposedOperator) Dims() (int, int) {
+	rows, cols := t.a.Dims()
+	return cols, rows
+}
+
+// MulVec returns A^T * x
+func (t transposedOperator) MulVec(x []float64) ([]float64, error) {
+	return t.a.MulVecTrans(x)
+}
+
+// MulVecTrans returns A * x
+func (t transposedOperator) MulVecTrans(x []float64) ([]float64, error) {
+	return t.a.MulVec(x)
+}
+
+// checkOperatorInput returns an error if x does not have cols entries
+func checkOperatorInput(op string, rows, cols int, x []float64) error {
+	if len(x) != cols {
+		return &ShapeError{
+			Op:     op,
+			Shapes: []Shape{{Rows: rows, Cols: cols}, {Rows: len(x), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	return nil
+}
+
+// shiftedOperator is A - sigma*I
+type shiftedOperator struct {
+	a     LinearOperator
+	sigma float64
+}
+
+// Dims returns the dimensions of A
+func (s shiftedOperator) Dims() (int, int) {
+	return s.a.Dims()
+}
+
+// MulVec returns A*x - sigma*x
+func (s shiftedOperator) MulVec(x []float64) ([]float64, error) {
+	res, err := s.a.MulVec(x)
+	if err != nil {
+		return nil, err
+	}
+	axpy(-s.sigma, x, res)
+
+	return res, nil
+}
+
+// KroneckerOperator is the Kronecker product A ⊗ B, the block matrix whose
+// block (i, j) is A[i][j] * B. For an m x n A and a p x q B it is mp x nq,
+// but it only stores A and B.
+type KroneckerOperator struct {
+	A, B LinearOperator
+}
+
+// NewKronecker returns the implicit Kronecker product A ⊗ B
+func NewKronecker(a, b LinearOperator) KroneckerOperator {
+	return KroneckerOperator{A: a, B: b}
+}
+
+// Dims returns the number of rows and columns of A ⊗ B
+func (k KroneckerOperator) Dims() (int, int) {
+	rowsA, colsA := k.A.Dims()
+	rowsB, colsB := k.B.Dims()
+	return rowsA * rowsB, colsA * colsB
+}
+
+// MulVec returns (A ⊗ B) * x. With x split into the rows of an n x q matrix X
+// this is A * X * B^T read row by row, which needs n products with B and p with A.
+func (k KroneckerOperator) MulVec(x []float64) ([]float64, error) {
+	rows, cols := k.Dims()
+	if err := checkOperatorInput("KroneckerOperator.MulVec", rows, cols, x); err != nil {
+		return nil, err
+	}
+
+	return kroneckerMulVec(k.A, k.B, x)
+}
+
+// MulVecTrans returns (A ⊗ B)^T * x = (A^T ⊗ B^T) * x.
+// It returns ErrInvalidArgument if A or B cannot multiply by its transpose.
+func (k KroneckerOperator) MulVecTrans(x []float64) ([]float64, error) {
+	rows, cols := k.Dims()
+	if err := checkOperatorInput("KroneckerOperator.MulVecTrans", cols, rows, x); err != nil {
+		return nil, err
+	}
+	aT, err := OperatorTranspose(k.A)
+	if err != nil {
+		return nil, err
+	}
+	bT, err := OperatorTranspose(k.B)
+	if err != nil {
+		return nil, err
+	}
+
+	return kroneckerMulVec(aT, bT, x)
+}
+
+// kroneckerMulVec returns (A ⊗ B) * x
+func kroneckerMulVec(a, b LinearOperator, x []float64) ([]float64, error) {
+	rowsA, colsA := a.Dims()
+	rowsB, colsB := b.Dims()
+
+	// Z = X * B^T, row j of Z is B times row j of X
+	z := make([][]float64, colsA)
+	for j := range z {
+		row, err := b.MulVec(x[j*colsB : (j+1)*colsB])
+		if err != nil {
+			return nil, err
+		}
+		z[j] = row
+	}
+
+	// Y = A * Z, one column at a time
+	res := make([]float64, rowsA*rowsB)
+	column := make([]float64, colsA)
+	for l := 0; l < rowsB; l++ {
+		for j := range column {
+			column[j] = z[j][l]
+		}
+		y, err := a.MulVec(column)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range y {
+			res[i*rowsB+l] = v
+		}
+	}
+
+	return res, nil
+}
+
+// ConvolutionOperator is the full 1D convolution with a fixed kernel.
+// It maps a signal of length n to one of length n + len(kernel) - 1,
+// the Toeplitz matrix is never built.
+type ConvolutionOperator struct {
+	kernel []float64
+	n      int
+}
+
+// NewConvolution returns the operator x -> kernel * x for signals x of length n.
+// It returns ErrInvalidArgument if the kernel is empty or n is not positive.
+func NewConvolution(kernel []float64, n int) (ConvolutionOperator, error) {
+	if len(kernel) == 0 || n <= 0 {
+		return ConvolutionOperator{}, fmt.Errorf("NewConvolution: %w: kernel length %d and signal length %d must be positive", ErrInvalidArgument, len(kernel), n)
+	}
+
+	k := make([]float64, len(kernel))
+	copy(k, kernel)
+	return ConvolutionOperator{kernel: k, n: n}, nil
+}
+
+// Dims returns n + len(kernel) - 1 rows and n columns
+func (c ConvolutionOperator) Dims() (int, int) {
+	return c.n + len(c.kernel) - 1, c.n
+}
+
+// MulVec returns the convolution y[i] = sum over j of kernel[j] * x[i-j]
+func (c ConvolutionOperator) MulVec(x []float64) ([]float64, error) {
+	rows, cols := c.Dims()
+	if err := checkOperatorInput("ConvolutionOperator.MulVec", rows, cols, x); err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, rows)
+	for i, xi := range x {
+		axpy(xi, c.kernel, res[i:i+len(c.kernel)])
+	}
+
+	return res, nil
+}
+
+// MulVecTrans returns the correlation z[i] = sum over j of kernel[j] * y[i+j]
+func (c ConvolutionOperator) MulVecTrans(y []float64) ([]float64, error) {
+	rows, cols := c.Dims()
+	if err := checkOperatorInput("ConvolutionOperator.MulVecTrans", cols, rows, y); err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, cols)
+	for i := range res {
+		res[i] = dot(c.kernel, y[i:i+len(c.kernel)])
+	}
+
+	return res, nil
+}
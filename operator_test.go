@@ -0,0 +1,269 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var (
+	_ TransposableOperator = Matrix{}
+	_ TransposableOperator = CSR{}
+	_ TransposableOperator = CSC{}
+	_ TransposableOperator = KroneckerOperator{}
+	_ TransposableOperator = ConvolutionOperator{}
+)
+
+// denseOperator builds the matrix of an operator one column at a time
+func denseOperator(t *testing.T, a LinearOperator) [][]float64 {
+	t.Helper()
+	rows, cols := a.Dims()
+	res := NewZeroMatrix(rows, cols)
+	e := make([]float64, cols)
+	for j := 0; j < cols; j++ {
+		e[j] = 1
+		column, err := a.MulVec(e)
+		if err != nil {
+			t.Fatalf("MulVec() unexpected error: %v", err)
+		}
+		for i, v := range column {
+			res.Data[i][j] = v
+		}
+		e[j] = 0
+	}
+
+	return res.Data
+}
+
+// checkAdjoint fails the test if y^T * (A * x) != (A^T * y)^T * x for random x and y
+func checkAdjoint(t *testing.T, a TransposableOperator) {
+	t.Helper()
+	rows, cols := a.Dims()
+	rng := rand.New(rand.NewSource(7))
+	x := randomMatrix(rng, 1, cols)[0]
+	y := randomMatrix(rng, 1, rows)[0]
+	ax, err := a.MulVec(x)
+	if err != nil {
+		t.Fatalf("MulVec() unexpected error: %v", err)
+	}
+	aty, err := a.MulVecTrans(y)
+	if err != nil {
+		t.Fatalf("MulVecTrans() unexpected error: %v", err)
+	}
+	if got, want := dot(aty, x), dot(y, ax); math.Abs(got-want) > 1e-10*math.Max(1, math.Abs(want)) {
+		t.Errorf("(A^T*y)^T*x = %v, want y^T*(A*x) = %v", got, want)
+	}
+}
+
+func TestMatrixMulVecTrans(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2, 3}, {4, 5, 6}})
+	got, err := m.MulVecTrans([]float64{1, -1})
+	if err != nil {
+		t.Fatalf("MulVecTrans() unexpected error: %v", err)
+	}
+	if want := []float64{-3, -3, -3}; !areMatricesEqual([][]float64{got}, [][]float64{want}) {
+		t.Errorf("MulVecTrans() = %v, want %v", got, want)
+	}
+	if _, err := m.MulVecTrans([]float64{1, 2, 3}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("MulVecTrans() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestOperatorTranspose(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2, 3}, {4, 5, 6}})
+	mT, err := OperatorTranspose(m)
+	if err != nil {
+		t.Fatalf("OperatorTranspose() unexpected error: %v", err)
+	}
+	if rows, cols := mT.Dims(); rows != 3 || cols != 2 {
+		t.Errorf("Dims() = %d, %d, want 3, 2", rows, cols)
+	}
+	if got := denseOperator(t, mT); !areMatricesEqual(got, TransposeMatrix(m.Data)) {
+		t.Errorf("OperatorTranspose() = %v, want %v", got, TransposeMatrix(m.Data))
+	}
+	back, err := OperatorTranspose(mT)
+	if err != nil {
+		t.Fatalf("OperatorTranspose() unexpected error: %v", err)
+	}
+	if _, ok := back.(Matrix); !ok {
+		t.Errorf("OperatorTranspose(OperatorTranspose(m)) = %T, want Matrix", back)
+	}
+
+	if _, err := OperatorTranspose(shiftedOperator{a: m}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("OperatorTranspose() error = %v, want %v", err, ErrInvalidArgument)
+	}
+}
+
+func TestKroneckerOperator(t *testing.T) {
+	A := [][]float64{{1, 2}, {3, 4}, {0, -1}}
+	B := [][]float64{{0, 5, 1}, {6, 7, -2}}
+	// block (i, j) of A ⊗ B is A[i][j] * B
+	want := NewZeroMatrix(6, 6).Data
+	for i := range A {
+		for j := range A[i] {
+			for k := range B {
+				for l := range B[k] {
+					want[i*2+k][j*3+l] = A[i][j] * B[k][l]
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		a, b LinearOperator
+	}{
+		{name: "dense", a: NewMatrix(A), b: NewMatrix(B)},
+		{name: "sparse", a: NewCSR(NewMatrix(A)), b: NewCSC(NewMatrix(B))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := NewKronecker(tt.a, tt.b)
+			if got := denseOperator(t, k); !areMatricesEqual(got, want) {
+				t.Errorf("KroneckerOperator = %v, want %v", got, want)
+			}
+			checkAdjoint(t, k)
+			if _, err := k.MulVec(make([]float64, 5)); !errors.Is(err, ErrDimensionMismatch) {
+				t.Errorf("MulVec() error = %v, want %v", err, ErrDimensionMismatch)
+			}
+		})
+	}
+
+	k := NewKronecker(shiftedOperator{a: NewMatrix(A[:2])}, NewMatrix(B))
+	if _, err := k.MulVecTrans(make([]float64, 4)); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("MulVecTrans() error = %v, want %v", err, ErrInvalidArgument)
+	}
+}
+
+func TestKroneckerLaplacian(t *testing.T) {
+	// the 2D Laplacian is I ⊗ T + T ⊗ I, with T the 1D one
+	n := 12
+	T := laplacian1D(n).ToCSR()
+	identity := NewCSR(NewMatrix(GenerateIdentityMatrix(n)))
+	left, right := NewKronecker(identity, T), NewKronecker(T, identity)
+
+	x := make([]float64, n*n)
+	for i := range x {
+		x[i] = math.Cos(float64(i))
+	}
+	got, err := left.MulVec(x)
+	if err != nil {
+		t.Fatalf("MulVec() unexpected error: %v", err)
+	}
+	other, err := right.MulVec(x)
+	if err != nil {
+		t.Fatalf("MulVec() unexpected error: %v", err)
+	}
+	axpy(1, other, got)
+
+	want, err := laplacian2D(n).MulVec(x)
+	if err != nil {
+		t.Fatalf("MulVec() unexpected error: %v", err)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("(I ⊗ T + T ⊗ I) * x = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestConvolutionOperator(t *testing.T) {
+	c, err := NewConvolution([]float64{1, -2, 3}, 4)
+	if err != nil {
+		t.Fatalf("NewConvolution() unexpected error: %v", err)
+	}
+	want := [][]float64{
+		{1, 0, 0, 0},
+		{-2, 1, 0, 0},
+		{3, -2, 1, 0},
+		{0, 3, -2, 1},
+		{0, 0, 3, -2},
+		{0, 0, 0, 3},
+	}
+	if got := denseOperator(t, c); !areMatricesEqual(got, want) {
+		t.Errorf("ConvolutionOperator = %v, want %v", got, want)
+	}
+	checkAdjoint(t, c)
+
+	// deconvolution as the least squares problem C^T * C * x = C^T * y
+	x := []float64{1, 2, -1, 0.5}
+	y, err := c.MulVec(x)
+	if err != nil {
+		t.Fatalf("MulVec() unexpected error: %v", err)
+	}
+	rhs, err := c.MulVecTrans(y)
+	if err != nil {
+		t.Fatalf("MulVecTrans() unexpected error: %v", err)
+	}
+	res, err := ConjugateGradient(normalOperator{c}, rhs, KrylovOptions{Tol: 1e-12})
+	if err != nil {
+		t.Fatalf("ConjugateGradient() unexpected error: %v", err)
+	}
+	for i := range x {
+		if math.Abs(res.X[i]-x[i]) > 1e-9 {
+			t.Errorf("deconvolution = %v, want %v", res.X, x)
+			break
+		}
+	}
+
+	for _, tt := range []struct {
+		kernel []float64
+		n      int
+	}{{nil, 3}, {[]float64{1}, 0}} {
+		if _, err := NewConvolution(tt.kernel, tt.n); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("NewConvolution(%v, %d) error = %v, want %v", tt.kernel, tt.n, err, ErrInvalidArgument)
+		}
+	}
+}
+
+// normalOperator is A^T * A
+type normalOperator struct {
+	a TransposableOperator
+}
+
+func (n normalOperator) Dims() (int, int) {
+	_, cols := n.a.Dims()
+	return cols, cols
+}
+
+func (n normalOperator) MulVec(x []float64) ([]float64, error) {
+	ax, err := n.a.MulVec(x)
+	if err != nil {
+		return nil, err
+	}
+	return n.a.MulVecTrans(ax)
+}
+
+func TestProjectPrincipalComponents(t *testing.T) {
+	data := NewMatrix([][]float64{{1, 2}, {3, 5}, {-2, 0}, {4, 1}})
+	components := PCA(data)
+
+	tests := []struct {
+		name string
+		data LinearOperator
+	}{
+		{name: "dense", data: data},
+		{name: "sparse", data: NewCSR(data)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ProjectPrincipalComponents(tt.data, components)
+			if err != nil {
+				t.Fatalf("ProjectPrincipalComponents() unexpected error: %v", err)
+			}
+			for i, row := range data.Data {
+				for j, pc := range components {
+					if want := pc.GetScore(row); math.Abs(got.At(i, j)-want) > 1e-12 {
+						t.Errorf("score[%d][%d] = %v, want %v", i, j, got.At(i, j), want)
+					}
+				}
+			}
+		})
+	}
+
+	bad := []PrincipalComponent{{Vector: []float64{1, 0, 0}}}
+	if _, err := ProjectPrincipalComponents(data, bad); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ProjectPrincipalComponents() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
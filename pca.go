@@ -0,0 +1,286 @@
+package linearalgebra
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// PCAOptions controls FitPCA
+type PCAOptions struct {
+	// Components is the number of principal components to keep,
+	// all min(samples, features) of them if both Components and VarianceThreshold are 0
+	Components int
+	// VarianceThreshold keeps the fewest components whose explained variance
+	// ratios add up to at least this fraction, it must be in (0, 1].
+	// Only one of Components and VarianceThreshold can be set.
+	VarianceThreshold float64
+	// Standardize divides every centered column by its standard deviation,
+	// so features measured in different units weigh the same and the
+	// components are the eigenvectors of the correlation matrix
+	Standardize bool
+	// Sign is the sign convention of the component vectors, by default the
+	// entry of largest magnitude of every vector is positive
+	Sign SignConvention
+	// Randomized, if it is not nil, finds the Components largest components with
+	// RandomizedSVD instead of a full SVD, which is much faster when only a few
+	// components of a large data set are wanted. Components must be set, and the
+	// Sign of Randomized is replaced by the one above.
+	Randomized *RandomizedSVDOptions
+}
+
+// PCAModel is a principal component analysis fitted to a data set with one
+// sample per row. It keeps what is needed to project new rows the same way
+// the training rows were projected: x -> ((x - Means) / Stds) * V, where the
+// columns of V are the component vectors.
+type PCAModel struct {
+	// Means are the column means of the training data
+	Means []float64 `json:"means"`
+	// Stds are the column standard deviations the centered data was divided by,
+	// nil if the model is not standardized. A constant column has std 1 so it
+	// stays 0 instead of dividing by 0.
+	Stds []float64 `json:"stds,omitempty"`
+	// Components are the kept principal components in descending order of variance
+	Components []PrincipalComponent `json:"components"`
+	// TotalVariance is the variance of the (standardized) training data,
+	// the sum of the variances of all components, also the dropped ones
+	TotalVariance float64 `json:"total_variance"`
+	// Samples is the number of rows the model was fitted to
+	Samples int `json:"samples"`
+}
+
+// FitPCA centers the columns of m, optionally scales them to unit variance,
+// and finds the principal components with an SVD of the result: the right
+// singular vectors are the directions and sigma^2 / (n - 1) their variances.
+// With opts.Randomized the SVD is a RandomizedSVD for the top Components only,
+// the total variance is still exact.
+// It returns an error if m has fewer than 2 rows, no columns or rows of
+// different lengths, or if the options are invalid.
+func FitPCA(m Matrix, opts PCAOptions) (PCAModel, error) {
+	const op = "FitPCA"
+	if err := checkRectangular(op, m.Data); err != nil {
+		return PCAModel{}, err
+	}
+	rows, cols := m.Dims()
+	if rows < 2 || cols == 0 {
+		return PCAModel{}, fmt.Errorf("%s: %w: need at least 2 samples and 1 feature, got %dx%d", op, ErrInvalidArgument, rows, cols)
+	}
+	if err := opts.validate(op, min(rows, cols)); err != nil {
+		return PCAModel{}, err
+	}
+
+	means, stds := columnMeansAndStds(m.Data)
+	model := PCAModel{Means: means, Samples: rows}
+	if opts.Standardize {
+		model.Stds = stds
+	}
+	centered := NewMatrix(m.Data)
+	for i := range centered.Data {
+		for j := range centered.Data[i] {
+			centered.Data[i][j] -= means[j]
+			if opts.Standardize {
+				centered.Data[i][j] /= stds[j]
+			}
+		}
+	}
+
+	var svd SVDResult
+	if opts.Randomized != nil {
+		randomized := *opts.Randomized
+		randomized.Sign = opts.Sign
+		var err error
+		if svd, err = RandomizedSVD(&centered, opts.Components, randomized); err != nil {
+			return PCAModel{}, err
+		}
+	} else {
+		var err error
+		if svd, err = SVDWithOptions(&centered, SVDOptions{Sign: opts.Sign}); err != nil {
+			return PCAModel{}, err
+		}
+	}
+	variances := make([]float64, len(svd.SingularValues))
+	for i, sigma := range svd.SingularValues {
+		variances[i] = sigma * sigma / float64(rows-1)
+		model.TotalVariance += variances[i]
+	}
+	if opts.Randomized != nil {
+		// the variances of the components that were not computed are missing
+		// from the sum, the squared Frobenius norm is the sum of all of them
+		frobenius, err := centered.Norm(NormFrobenius)
+		if err != nil {
+			return PCAModel{}, err
+		}
+		model.TotalVariance = frobenius * frobenius / float64(rows-1)
+	}
+
+	k := opts.componentCount(variances, model.TotalVariance)
+	model.Components = make([]PrincipalComponent, k)
+	for i := range model.Components {
+		model.Components[i] = PrincipalComponent{Vector: svd.V.GetColumn(i), Variance: variances[i]}
+	}
+
+	return model, nil
+}
+
+// validate checks the options against the number of components available
+func (opts PCAOptions) validate(op string, available int) error {
+	if opts.Components < 0 || opts.Components > available {
+		return fmt.Errorf("%s: %w: %d components, want 0 to %d", op, ErrInvalidArgument, opts.Components, available)
+	}
+	if opts.VarianceThreshold < 0 || opts.VarianceThreshold > 1 || math.IsNaN(opts.VarianceThreshold) {
+		return fmt.Errorf("%s: %w: variance threshold %v, want a value in (0, 1]", op, ErrInvalidArgument, opts.VarianceThreshold)
+	}
+	if opts.Components > 0 && opts.VarianceThreshold > 0 {
+		return fmt.Errorf("%s: %w: set either Components or VarianceThreshold, not both", op, ErrInvalidArgument)
+	}
+	if err := opts.Sign.validate(op); err != nil {
+		return err
+	}
+	if opts.Randomized != nil && opts.Components == 0 {
+		return fmt.Errorf("%s: %w: the randomized solver needs Components", op, ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+// componentCount returns the number of components to keep out of the given
+// variances, which are in descending order
+func (opts PCAOptions) componentCount(variances []float64, total float64) int {
+	switch {
+	case opts.Components > 0:
+		return opts.Components
+	case opts.VarianceThreshold > 0 && total > 0:
+		explained := 0.0
+		for i, v := range variances {
+			explained += v
+			// the last few ulps of the sum should not pull in one more component
+			if explained >= (opts.VarianceThreshold-1e-12)*total {
+				return i + 1
+			}
+		}
+	}
+
+	return len(variances)
+}
+
+// NumComponents returns the number of kept components
+func (p PCAModel) NumComponents() int {
+	return len(p.Components)
+}
+
+// NumFeatures returns the number of columns the model was fitted to
+func (p PCAModel) NumFeatures() int {
+	return len(p.Means)
+}
+
+// ExplainedVarianceRatio returns the fraction of the total variance explained
+// by each kept component
+func (p PCAModel) ExplainedVarianceRatio() []float64 {
+	res := make([]float64, len(p.Components))
+	for i, pc := range p.Components {
+		res[i] = pc.GetExplainedVarianceRatio(p.TotalVariance)
+	}
+
+	return res
+}
+
+// Transform returns the n x k scores of the rows of m on the k kept components.
+// The rows are centered with the training means, and scaled with the training
+// standard deviations for a standardized model, before they are projected.
+// It returns an error if m does not have NumFeatures columns.
+func (p PCAModel) Transform(m Matrix) (Matrix, error) {
+	const op = "PCAModel.Transform"
+	if err := checkRectangular(op, m.Data); err != nil {
+		return Matrix{}, err
+	}
+	rows, cols := m.Dims()
+	if rows > 0 && cols != p.NumFeatures() {
+		return Matrix{}, &ShapeError{
+			Op:     op,
+			Shapes: []Shape{{Rows: rows, Cols: cols}, {Rows: p.NumFeatures(), Cols: p.NumComponents()}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	scores := NewZeroMatrix(rows, p.NumComponents())
+	x := make([]float64, p.NumFeatures())
+	for i := 0; i < rows; i++ {
+		for j := range x {
+			x[j] = m.Data[i][j] - p.Means[j]
+			if p.Stds != nil {
+				x[j] /= p.Stds[j]
+			}
+		}
+		for k, pc := range p.Components {
+			scores.Data[i][k] = dot(x, pc.Vector)
+		}
+	}
+
+	return scores, nil
+}
+
+// InverseTransform maps n x k scores back to the original space: the sum of
+// the components weighted by the scores, scaled by the standard deviations and
+// shifted by the means. It undoes Transform when the components span all the
+// features, with fewer it returns the projection onto the kept components.
+// It returns an error if scores does not have NumComponents columns.
+func (p PCAModel) InverseTransform(scores Matrix) (Matrix, error) {
+	const op = "PCAModel.InverseTransform"
+	if err := checkRectangular(op, scores.Data); err != nil {
+		return Matrix{}, err
+	}
+	rows, cols := scores.Dims()
+	if rows > 0 && cols != p.NumComponents() {
+		return Matrix{}, &ShapeError{
+			Op:     op,
+			Shapes: []Shape{{Rows: rows, Cols: cols}, {Rows: p.NumFeatures(), Cols: p.NumComponents()}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := NewZeroMatrix(rows, p.NumFeatures())
+	for i := 0; i < rows; i++ {
+		for k, pc := range p.Components {
+			axpy(scores.Data[i][k], pc.Vector, res.Data[i])
+		}
+		for j := range res.Data[i] {
+			if p.Stds != nil {
+				res.Data[i][j] *= p.Stds[j]
+			}
+			res.Data[i][j] += p.Means[j]
+		}
+	}
+
+	return res, nil
+}
+
+// SavePCAModel writes the model as JSON
+func SavePCAModel(model PCAModel, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(model)
+}
+
+// LoadPCAModel reads a model written by SavePCAModel.
+// It returns an error wrapping ErrInvalidFormat if the JSON cannot be parsed or
+// the lengths of the means, standard deviations and component vectors do not match.
+func LoadPCAModel(input io.Reader) (PCAModel, error) {
+	var model PCAModel
+	if err := json.NewDecoder(input).Decode(&model); err != nil {
+		return PCAModel{}, fmt.Errorf("LoadPCAModel: %w: %v", ErrInvalidFormat, err)
+	}
+
+	features := len(model.Means)
+	if model.Stds != nil && len(model.Stds) != features {
+		return PCAModel{}, fmt.Errorf("LoadPCAModel: %w: %d stds for %d features", ErrInvalidFormat, len(model.Stds), features)
+	}
+	for i, pc := range model.Components {
+		if len(pc.Vector) != features {
+			return PCAModel{}, fmt.Errorf("LoadPCAModel: %w: component %d has %d entries for %d features",
+				ErrInvalidFormat, i, len(pc.Vector), features)
+		}
+	}
+
+	return model, nil
+}
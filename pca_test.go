@@ -0,0 +1,250 @@
+package linearalgebra
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFitPCA(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	model, err := FitPCA(m, PCAOptions{})
+	if err != nil {
+		t.Fatalf("FitPCA() unexpected error: %v", err)
+	}
+	rows, cols := m.Dims()
+	if model.NumFeatures() != cols || model.NumComponents() != cols || model.Samples != rows {
+		t.Fatalf("FitPCA() has %d features, %d components and %d samples, want %d, %d and %d",
+			model.NumFeatures(), model.NumComponents(), model.Samples, cols, cols, rows)
+	}
+
+	for j := 0; j < cols; j++ {
+		if want := GetMean(m.GetColumn(j)); math.Abs(model.Means[j]-want) > 1e-9*math.Abs(want) {
+			t.Errorf("Means[%d] = %v, want %v", j, model.Means[j], want)
+		}
+	}
+
+	pcs := PCA(m)
+	for i, pc := range model.Components {
+		if math.Abs(pc.Variance-pcs[i].Variance) > 1e-9*pcs[i].Variance {
+			t.Errorf("component %d variance = %v, want %v", i, pc.Variance, pcs[i].Variance)
+		}
+		// the same direction up to the sign
+		if d := math.Abs(dot(pc.Vector, pcs[i].Vector)); math.Abs(d-1) > 1e-6 {
+			t.Errorf("component %d |v . PCA()| = %v, want 1", i, d)
+		}
+	}
+
+	ratios := model.ExplainedVarianceRatio()
+	sum := 0.0
+	for _, r := range ratios {
+		sum += r
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("ExplainedVarianceRatio() adds up to %v, want 1", sum)
+	}
+
+	scores, err := model.Transform(m)
+	if err != nil {
+		t.Fatalf("Transform() unexpected error: %v", err)
+	}
+	projected, err := ProjectPrincipalComponents(CenterMatrix(m), model.Components)
+	if err != nil {
+		t.Fatalf("ProjectPrincipalComponents() unexpected error: %v", err)
+	}
+	if !MatricesNearlyEqual(scores.Data, projected.Data, Tolerance{Relative: 1e-12}) {
+		t.Errorf("Transform() differs from projecting the centered data")
+	}
+	// the scores are uncorrelated with the component variances
+	covariance := scores.GetCovarianceMatrix()
+	for i := range covariance.Data {
+		for j := range covariance.Data[i] {
+			want := 0.0
+			if i == j {
+				want = model.Components[i].Variance
+			}
+			if math.Abs(covariance.Data[i][j]-want) > 1e-8*model.TotalVariance {
+				t.Errorf("cov(scores)[%d][%d] = %v, want %v", i, j, covariance.Data[i][j], want)
+			}
+		}
+	}
+
+	restored, err := model.InverseTransform(scores)
+	if err != nil {
+		t.Fatalf("InverseTransform() unexpected error: %v", err)
+	}
+	if !MatricesNearlyEqual(restored.Data, m.Data, Tolerance{Relative: 1e-12}) {
+		t.Errorf("InverseTransform(Transform(m)) != m")
+	}
+
+	// a new row is projected with the training means, not its own
+	row := Matrix{Data: [][]float64{m.GetRow(7)}}
+	single, err := model.Transform(row)
+	if err != nil {
+		t.Fatalf("Transform() unexpected error: %v", err)
+	}
+	if !MatricesNearlyEqual(single.Data, [][]float64{scores.GetRow(7)}, Tolerance{Relative: 1e-12}) {
+		t.Errorf("Transform(row 7) = %v, want %v", single.Data, scores.GetRow(7))
+	}
+}
+
+func TestFitPCAComponentSelection(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	full, err := FitPCA(m, PCAOptions{})
+	if err != nil {
+		t.Fatalf("FitPCA() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      PCAOptions
+		threshold float64
+	}{
+		{name: "count", opts: PCAOptions{Components: 3}},
+		{name: "50%", opts: PCAOptions{VarianceThreshold: 0.5}, threshold: 0.5},
+		{name: "90%", opts: PCAOptions{VarianceThreshold: 0.9}, threshold: 0.9},
+		{name: "100%", opts: PCAOptions{VarianceThreshold: 1}, threshold: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := FitPCA(m, tt.opts)
+			if err != nil {
+				t.Fatalf("FitPCA() unexpected error: %v", err)
+			}
+			want := tt.opts.Components
+			if tt.threshold > 0 {
+				explained := 0.0
+				for want = 0; explained < tt.threshold-1e-12; want++ {
+					explained += full.ExplainedVarianceRatio()[want]
+				}
+			}
+			if model.NumComponents() != want {
+				t.Errorf("NumComponents() = %d, want %d", model.NumComponents(), want)
+			}
+			if model.TotalVariance != full.TotalVariance {
+				t.Errorf("TotalVariance = %v, want %v", model.TotalVariance, full.TotalVariance)
+			}
+
+			// with fewer components InverseTransform is the projection onto them,
+			// projecting that again changes nothing
+			scores, _ := model.Transform(m)
+			projected, err := model.InverseTransform(scores)
+			if err != nil {
+				t.Fatalf("InverseTransform() unexpected error: %v", err)
+			}
+			again, _ := model.Transform(projected)
+			if !MatricesNearlyEqual(again.Data, scores.Data, Tolerance{Relative: 1e-10}) {
+				t.Errorf("Transform(InverseTransform(scores)) != scores")
+			}
+		})
+	}
+}
+
+func TestFitPCAStandardize(t *testing.T) {
+	// the second column is the first one in other units, the third is constant
+	m := NewMatrix([][]float64{{1, 1000, 5}, {2, 2000, 5}, {4, 4000, 5}, {7, 7000, 5}})
+	model, err := FitPCA(m, PCAOptions{Standardize: true})
+	if err != nil {
+		t.Fatalf("FitPCA() unexpected error: %v", err)
+	}
+
+	std := math.Sqrt(7)
+	if want := []float64{std, 1000 * std, 1}; !MatricesNearlyEqual([][]float64{model.Stds}, [][]float64{want}, Tolerance{Relative: 1e-12}) {
+		t.Errorf("Stds = %v, want %v", model.Stds, want)
+	}
+	// both scaled columns have variance 1, the constant one 0
+	if math.Abs(model.TotalVariance-2) > 1e-12 || math.Abs(model.Components[0].Variance-2) > 1e-12 {
+		t.Errorf("TotalVariance = %v, first variance = %v, want 2 and 2", model.TotalVariance, model.Components[0].Variance)
+	}
+	if v := model.Components[0].Vector; math.Abs(math.Abs(v[0])-math.Sqrt(0.5)) > 1e-12 || math.Abs(v[0]-v[1]) > 1e-12 {
+		t.Errorf("first component = %v, want +-[1 1 0] / sqrt(2)", v)
+	}
+
+	scores, _ := model.Transform(m)
+	restored, err := model.InverseTransform(scores)
+	if err != nil {
+		t.Fatalf("InverseTransform() unexpected error: %v", err)
+	}
+	if !MatricesNearlyEqual(restored.Data, m.Data, Tolerance{Relative: 1e-12}) {
+		t.Errorf("InverseTransform(Transform(m)) = %v, want %v", restored.Data, m.Data)
+	}
+}
+
+func TestPCAModelJSON(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	for _, standardize := range []bool{false, true} {
+		model, err := FitPCA(m, PCAOptions{Components: 4, Standardize: standardize})
+		if err != nil {
+			t.Fatalf("FitPCA() unexpected error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := SavePCAModel(model, &buf); err != nil {
+			t.Fatalf("SavePCAModel() unexpected error: %v", err)
+		}
+		if standardize != strings.Contains(buf.String(), `"stds"`) {
+			t.Errorf("SavePCAModel() = %s, stds written = %v, want %v", buf.String(), !standardize, standardize)
+		}
+		loaded, err := LoadPCAModel(&buf)
+		if err != nil {
+			t.Fatalf("LoadPCAModel() unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(loaded, model) {
+			t.Errorf("LoadPCAModel(SavePCAModel(model)) = %+v, want %+v", loaded, model)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "not json", input: "means: 1"},
+		{name: "stds", input: `{"means": [1, 2], "stds": [1], "components": []}`},
+		{name: "component", input: `{"means": [1, 2], "components": [{"vector": [1], "variance": 1}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadPCAModel(strings.NewReader(tt.input)); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("LoadPCAModel() error = %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+}
+
+func TestFitPCAErrors(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2}, {3, 5}, {4, 4}})
+	tests := []struct {
+		name string
+		m    Matrix
+		opts PCAOptions
+		want error
+	}{
+		{name: "one row", m: NewMatrix([][]float64{{1, 2}}), want: ErrInvalidArgument},
+		{name: "jagged", m: Matrix{Data: [][]float64{{1, 2}, {3}}}, want: ErrDimensionMismatch},
+		{name: "negative count", m: m, opts: PCAOptions{Components: -1}, want: ErrInvalidArgument},
+		{name: "too many", m: m, opts: PCAOptions{Components: 3}, want: ErrInvalidArgument},
+		{name: "threshold", m: m, opts: PCAOptions{VarianceThreshold: 1.5}, want: ErrInvalidArgument},
+		{name: "both", m: m, opts: PCAOptions{Components: 1, VarianceThreshold: 0.5}, want: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FitPCA(tt.m, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("FitPCA() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	model, err := FitPCA(m, PCAOptions{Components: 1})
+	if err != nil {
+		t.Fatalf("FitPCA() unexpected error: %v", err)
+	}
+	if _, err := model.Transform(NewMatrix([][]float64{{1, 2, 3}})); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Transform() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := model.InverseTransform(NewMatrix([][]float64{{1, 2}})); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("InverseTransform() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
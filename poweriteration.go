@@ -0,0 +1,158 @@
+package linearalgebra
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// IterationOptions controls PowerIteration and InverseIteration
+type IterationOptions struct {
+	// Tol is the residual ||A*v - lambda*v|| relative to the largest ||A*v||
+	// seen so far at which the iteration stops, 1e-10 if it is 0
+	Tol float64
+	// MaxIter is the largest number of iterations, 1000 if it is 0
+	MaxIter int
+	// X0 is the starting vector, a fixed pseudo random vector if it is nil
+	X0 []float64
+}
+
+// iterationSetup validates the arguments, fills in the defaults and
+// returns the normalized starting vector
+func iterationSetup(op string, a LinearOperator, opts *IterationOptions) ([]float64, error) {
+	rows, cols := a.Dims()
+	if rows != cols {
+		return nil, &ShapeError{Op: op, Shapes: []Shape{{Rows: rows, Cols: cols}}, Err: ErrNotSquare}
+	}
+	if opts.X0 != nil && len(opts.X0) != cols {
+		return nil, &ShapeError{
+			Op:     op,
+			Shapes: []Shape{{Rows: rows, Cols: cols}, {Rows: len(opts.X0), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+	if opts.Tol <= 0 {
+		opts.Tol = 1e-10
+	}
+	if opts.MaxIter <= 0 {
+		opts.MaxIter = 1000
+	}
+
+	x := make([]float64, cols)
+	if opts.X0 != nil {
+		copy(x, opts.X0)
+	} else {
+		// a random start is almost surely not orthogonal to the wanted eigenvector
+		rng := rand.New(rand.NewSource(1))
+		for i := range x {
+			x[i] = rng.Float64() - 0.5
+		}
+	}
+	norm := norm2(x)
+	if norm == 0 {
+		return nil, fmt.Errorf("%s: %w: the starting vector is zero", op, ErrInvalidArgument)
+	}
+	for i := range x {
+		x[i] /= norm
+	}
+
+	return x, nil
+}
+
+// rayleighResidual returns A*x, the Rayleigh quotient x^T*A*x of a unit vector x
+// and the residual ||A*x - lambda*x||
+func rayleighResidual(a LinearOperator, x []float64) ([]float64, float64, float64, error) {
+	ax, err := a.MulVec(x)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	lambda := dot(x, ax)
+	r := make([]float64, len(ax))
+	copy(r, ax)
+	axpy(-lambda, x, r)
+
+	return ax, lambda, norm2(r), nil
+}
+
+// PowerIteration returns the eigenvalue of largest magnitude of a square A and
+// a unit eigenvector for it. It only needs A * x, so A can be a Matrix, a sparse
+// matrix or an implicit LinearOperator. The error shrinks like |lambda2/lambda1|
+// per iteration, so it is slow when the two largest eigenvalues are close, and it
+// does not converge when they have the same magnitude, for example a complex pair.
+// It returns the last estimate and an error wrapping ErrNoConvergence if the
+// tolerance is not reached in MaxIter iterations.
+func PowerIteration(a LinearOperator, opts IterationOptions) (float64, []float64, error) {
+	x, err := iterationSetup("PowerIteration", a, &opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	scale := 0.0
+	lambda := 0.0
+	for k := 0; k < opts.MaxIter; k++ {
+		ax, l, r, err := rayleighResidual(a, x)
+		if err != nil {
+			return 0, nil, err
+		}
+		lambda = l
+		norm := norm2(ax)
+		scale = math.Max(scale, norm)
+		if r <= opts.Tol*scale {
+			return lambda, x, nil
+		}
+		if norm == 0 {
+			// x is in the null space of A, which has a larger eigenvalue elsewhere
+			return lambda, x, &ConvergenceError{Op: "PowerIteration", Iterations: k + 1}
+		}
+		for i := range ax {
+			x[i] = ax[i] / norm
+		}
+	}
+
+	return lambda, x, &ConvergenceError{Op: "PowerIteration", Iterations: opts.MaxIter}
+}
+
+// InverseIteration returns the eigenvalue of a square A closest to the shift
+// sigma and a unit eigenvector for it. Every iteration solves (A - sigma*I) * y = x
+// with GMRES, so it only needs A * x as well. The closer sigma is to the
+// eigenvalue the fewer iterations it takes.
+// It returns the last estimate and an error wrapping ErrNoConvergence if the
+// tolerance is not reached in MaxIter iterations.
+func InverseIteration(a LinearOperator, sigma float64, opts IterationOptions) (float64, []float64, error) {
+	x, err := iterationSetup("InverseIteration", a, &opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	shifted := shiftedOperator{a: a, sigma: sigma}
+	scale := 0.0
+	lambda := sigma
+	for k := 0; k < opts.MaxIter; k++ {
+		ax, l, r, err := rayleighResidual(a, x)
+		if err != nil {
+			return 0, nil, err
+		}
+		lambda = l
+		scale = math.Max(scale, math.Max(norm2(ax), math.Abs(sigma)))
+		if r <= opts.Tol*scale {
+			return lambda, x, nil
+		}
+
+		// an inexact solve still points towards the eigenvector, so a solve
+		// that runs out of iterations is not an error
+		res, err := GMRES(shifted, x, KrylovOptions{Tol: 1e-12})
+		if err != nil && !errors.Is(err, ErrNoConvergence) {
+			return 0, nil, err
+		}
+		norm := norm2(res.X)
+		if norm == 0 || math.IsNaN(norm) {
+			return lambda, x, &ConvergenceError{Op: "InverseIteration", Iterations: k + 1}
+		}
+		for i := range x {
+			x[i] = res.X[i] / norm
+		}
+	}
+
+	return lambda, x, &ConvergenceError{Op: "InverseIteration", Iterations: opts.MaxIter}
+}
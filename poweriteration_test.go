@@ -0,0 +1,129 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// checkEigenpair fails the test if ||A*v - lambda*v|| is larger than tol
+func checkEigenpair(t *testing.T, a LinearOperator, lambda float64, v []float64, tol float64) {
+	t.Helper()
+	av, err := a.MulVec(v)
+	if err != nil {
+		t.Fatalf("MulVec() unexpected error: %v", err)
+	}
+	axpy(-lambda, v, av)
+	if r := norm2(av); r > tol {
+		t.Errorf("||A*v - lambda*v|| = %v, want <= %v", r, tol)
+	}
+	if n := norm2(v); math.Abs(n-1) > 1e-12 {
+		t.Errorf("||v|| = %v, want 1", n)
+	}
+}
+
+func TestPowerIteration(t *testing.T) {
+	symmetric := [][]float64{{4, 1, 0}, {1, 3, 1}, {0, 1, -6}}
+	values, _, err := EigenSym(NewMatrix(symmetric))
+	if err != nil {
+		t.Fatalf("EigenSym() unexpected error: %v", err)
+	}
+	// the 1D Laplacian has eigenvalues 2 - 2cos(k*pi/(n+1))
+	n := 30
+	laplacianMax := 2 - 2*math.Cos(float64(n)*math.Pi/float64(n+1))
+
+	tests := []struct {
+		name string
+		a    LinearOperator
+		want float64
+	}{
+		{name: "dense symmetric with a negative dominant eigenvalue", a: NewMatrix(symmetric), want: values[2]},
+		{name: "non symmetric", a: NewMatrix([][]float64{{2, 1}, {0, 5}}), want: 5},
+		{name: "sparse", a: laplacian1D(n).ToCSR(), want: laplacianMax},
+		{
+			name: "Kronecker product",
+			a:    NewKronecker(NewMatrix([][]float64{{2, 1}, {0, 5}}), NewMatrix(symmetric)),
+			want: 5 * values[2],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lambda, v, err := PowerIteration(tt.a, IterationOptions{MaxIter: 10000})
+			if err != nil {
+				t.Fatalf("PowerIteration() unexpected error: %v", err)
+			}
+			if math.Abs(lambda-tt.want) > 1e-8*math.Abs(tt.want) {
+				t.Errorf("PowerIteration() = %v, want %v", lambda, tt.want)
+			}
+			checkEigenpair(t, tt.a, lambda, v, 1e-8*math.Abs(tt.want))
+		})
+	}
+}
+
+func TestInverseIteration(t *testing.T) {
+	n := 30
+	A := laplacian1D(n).ToCSR()
+	tests := []struct {
+		name  string
+		sigma float64
+		k     int
+	}{
+		{name: "smallest", sigma: 0, k: 1},
+		{name: "interior", sigma: 0.95, k: 10},
+		{name: "largest", sigma: 4, k: n},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := 2 - 2*math.Cos(float64(tt.k)*math.Pi/float64(n+1))
+			lambda, v, err := InverseIteration(A, tt.sigma, IterationOptions{})
+			if err != nil {
+				t.Fatalf("InverseIteration() unexpected error: %v", err)
+			}
+			if math.Abs(lambda-want) > 1e-9 {
+				t.Errorf("InverseIteration() = %v, want %v", lambda, want)
+			}
+			checkEigenpair(t, A, lambda, v, 1e-8)
+		})
+	}
+}
+
+func TestIterationErrors(t *testing.T) {
+	rotation := NewMatrix([][]float64{{0, -1}, {1, 0}})
+	tests := []struct {
+		name    string
+		iterate func(LinearOperator, IterationOptions) (float64, []float64, error)
+		a       LinearOperator
+		opts    IterationOptions
+		want    error
+	}{
+		{name: "PowerIteration not square", iterate: PowerIteration, a: NewMatrix([][]float64{{1, 2}}), want: ErrNotSquare},
+		{name: "PowerIteration bad start", iterate: PowerIteration, a: rotation, opts: IterationOptions{X0: []float64{1}}, want: ErrDimensionMismatch},
+		{name: "PowerIteration zero start", iterate: PowerIteration, a: rotation, opts: IterationOptions{X0: []float64{0, 0}}, want: ErrInvalidArgument},
+		// the eigenvalues ±i have the same magnitude
+		{name: "PowerIteration complex pair", iterate: PowerIteration, a: rotation, opts: IterationOptions{MaxIter: 50}, want: ErrNoConvergence},
+		{
+			name: "InverseIteration not square",
+			iterate: func(a LinearOperator, opts IterationOptions) (float64, []float64, error) {
+				return InverseIteration(a, 0, opts)
+			},
+			a:    NewMatrix([][]float64{{1, 2}}),
+			want: ErrNotSquare,
+		},
+		{
+			name: "InverseIteration complex pair",
+			iterate: func(a LinearOperator, opts IterationOptions) (float64, []float64, error) {
+				return InverseIteration(a, 0.5, opts)
+			},
+			a:    rotation,
+			opts: IterationOptions{MaxIter: 50},
+			want: ErrNoConvergence,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.iterate(tt.a, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
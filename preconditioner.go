@@ -0,0 +1,238 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math"
+)
+
+// Preconditioner approximates A^-1 cheaply. The Krylov solvers converge in
+// fewer iterations on M^-1 * A, which is closer to the identity than A.
+type Preconditioner interface {
+	// Apply returns M^-1 * r
+	Apply(r []float64) ([]float64, error)
+}
+
+// sparseDiagonal returns the diagonal of a square CSR matrix,
+// ErrSingular if an entry of it is 0
+func sparseDiagonal(op string, a CSR) ([]float64, error) {
+	if a.rows != a.cols {
+		return nil, &ShapeError{Op: op, Shapes: []Shape{{Rows: a.rows, Cols: a.cols}}, Err: ErrNotSquare}
+	}
+
+	diag := make([]float64, a.rows)
+	for i := range diag {
+		diag[i] = a.At(i, i)
+		if diag[i] == 0 {
+			return nil, &ShapeError{Op: op, Shapes: []Shape{{Rows: a.rows, Cols: a.cols}}, Err: ErrSingular}
+		}
+	}
+
+	return diag, nil
+}
+
+// checkPreconditionerInput returns an error if r does not have n entries
+func checkPreconditionerInput(op string, n int, r []float64) error {
+	if len(r) != n {
+		return &ShapeError{
+			Op:     op,
+			Shapes: []Shape{{Rows: n, Cols: n}, {Rows: len(r), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	return nil
+}
+
+// JacobiPreconditioner is M = diag(A)
+type JacobiPreconditioner struct {
+	inverseDiagonal []float64
+}
+
+// NewJacobiPreconditioner returns the diagonal preconditioner of A.
+// It is the cheapest option and helps when the rows of A have very different scales.
+// Use NewCSR to build one from a dense Matrix.
+func NewJacobiPreconditioner(a CSR) (JacobiPreconditioner, error) {
+	diag, err := sparseDiagonal("NewJacobiPreconditioner", a)
+	if err != nil {
+		return JacobiPreconditioner{}, err
+	}
+	for i := range diag {
+		diag[i] = 1 / diag[i]
+	}
+
+	return JacobiPreconditioner{inverseDiagonal: diag}, nil
+}
+
+// Apply returns r divided entry by entry by the diagonal of A
+func (p JacobiPreconditioner) Apply(r []float64) ([]float64, error) {
+	if err := checkPreconditionerInput("JacobiPreconditioner.Apply", len(p.inverseDiagonal), r); err != nil {
+		return nil, err
+	}
+
+	z := make([]float64, len(r))
+	for i := range z {
+		z[i] = r[i] * p.inverseDiagonal[i]
+	}
+
+	return z, nil
+}
+
+// SSORPreconditioner is the symmetric successive over relaxation preconditioner
+// M = w/(2-w) * (D/w + L) * (D/w)^-1 * (D/w + U)
+// where D, L and U are the diagonal, strictly lower and strictly upper parts of A
+type SSORPreconditioner struct {
+	a     CSR
+	diag  []float64
+	omega float64
+}
+
+// NewSSORPreconditioner returns the SSOR preconditioner of A with relaxation
+// factor omega in (0, 2), omega = 1 is symmetric Gauss-Seidel.
+// For a symmetric positive definite A it is symmetric positive definite as well,
+// so it can be used with ConjugateGradient.
+func NewSSORPreconditioner(a CSR, omega float64) (SSORPreconditioner, error) {
+	if !(omega > 0 && omega < 2) {
+		return SSORPreconditioner{}, fmt.Errorf("NewSSORPreconditioner: %w: omega %v is not in (0, 2)", ErrInvalidArgument, omega)
+	}
+	diag, err := sparseDiagonal("NewSSORPreconditioner", a)
+	if err != nil {
+		return SSORPreconditioner{}, err
+	}
+
+	return SSORPreconditioner{a: a, diag: diag, omega: omega}, nil
+}
+
+// Apply solves M * z = r with a forward and a backward triangular sweep
+func (p SSORPreconditioner) Apply(r []float64) ([]float64, error) {
+	n := len(p.diag)
+	if err := checkPreconditionerInput("SSORPreconditioner.Apply", n, r); err != nil {
+		return nil, err
+	}
+
+	a, w := p.a, p.omega
+	// (D/w + L) * y = r
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := r[i]
+		for k := a.RowPtr[i]; k < a.RowPtr[i+1] && a.ColIndex[k] < i; k++ {
+			sum -= a.Values[k] * y[a.ColIndex[k]]
+		}
+		y[i] = sum * w / p.diag[i]
+	}
+	// y = D/w * y
+	for i := range y {
+		y[i] *= p.diag[i] / w
+	}
+	// (D/w + U) * z = y
+	z := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := a.RowPtr[i+1] - 1; k >= a.RowPtr[i] && a.ColIndex[k] > i; k-- {
+			sum -= a.Values[k] * z[a.ColIndex[k]]
+		}
+		z[i] = sum * w / p.diag[i]
+	}
+	for i := range z {
+		z[i] *= (2 - w) / w
+	}
+
+	return z, nil
+}
+
+// IncompleteCholesky is M = L * L^T where L is lower triangular and has non zero
+// entries only where A does, the zero fill in factorization IC(0)
+type IncompleteCholesky struct {
+	// L is lower triangular with the sparsity pattern of the lower triangle of A
+	L CSR
+}
+
+// NewIncompleteCholesky computes the IC(0) factorization of a symmetric positive
+// definite A. It is usually the strongest of the three preconditioners for
+// ConjugateGradient and costs as much memory as A.
+// Only the lower triangle of A is read. It returns ErrNotPositiveDefinite if a
+// pivot is not positive, which can happen for some positive definite matrices
+// that are not diagonally dominant.
+func NewIncompleteCholesky(a CSR) (IncompleteCholesky, error) {
+	if a.rows != a.cols {
+		return IncompleteCholesky{}, &ShapeError{Op: "NewIncompleteCholesky", Shapes: []Shape{{Rows: a.rows, Cols: a.cols}}, Err: ErrNotSquare}
+	}
+
+	n := a.rows
+	L := CSR{RowPtr: make([]int, n+1), rows: n, cols: n}
+	for i := 0; i < n; i++ {
+		start := len(L.Values)
+		for k := a.RowPtr[i]; k < a.RowPtr[i+1] && a.ColIndex[k] <= i; k++ {
+			j := a.ColIndex[k]
+			// sum over the columns < j that rows i and j of L have in common
+			sum := a.Values[k]
+			pi, pj, endJ := start, L.RowPtr[j], L.RowPtr[j+1]
+			if j == i {
+				pj, endJ = start, len(L.Values)
+			}
+			for pi < len(L.Values) && pj < endJ {
+				ci, cj := L.ColIndex[pi], L.ColIndex[pj]
+				switch {
+				case ci >= j || cj >= j:
+					pi, pj = len(L.Values), endJ
+				case ci == cj:
+					sum -= L.Values[pi] * L.Values[pj]
+					pi++
+					pj++
+				case ci < cj:
+					pi++
+				default:
+					pj++
+				}
+			}
+
+			if j == i {
+				if !(sum > 0) {
+					return IncompleteCholesky{}, &ShapeError{Op: "NewIncompleteCholesky", Shapes: []Shape{{Rows: n, Cols: n}}, Err: ErrNotPositiveDefinite}
+				}
+				sum = math.Sqrt(sum)
+			} else {
+				diagJ := L.Values[L.RowPtr[j+1]-1]
+				sum /= diagJ
+			}
+			L.ColIndex = append(L.ColIndex, j)
+			L.Values = append(L.Values, sum)
+		}
+		if len(L.Values) == start || L.ColIndex[len(L.ColIndex)-1] != i {
+			// no diagonal entry in A
+			return IncompleteCholesky{}, &ShapeError{Op: "NewIncompleteCholesky", Shapes: []Shape{{Rows: n, Cols: n}}, Err: ErrNotPositiveDefinite}
+		}
+		L.RowPtr[i+1] = len(L.Values)
+	}
+
+	return IncompleteCholesky{L: L}, nil
+}
+
+// Apply solves L * L^T * z = r by forward and back substitution
+func (p IncompleteCholesky) Apply(r []float64) ([]float64, error) {
+	L := p.L
+	n := L.rows
+	if err := checkPreconditionerInput("IncompleteCholesky.Apply", n, r); err != nil {
+		return nil, err
+	}
+
+	// L * y = r, the diagonal is the last entry of every row
+	z := make([]float64, n)
+	copy(z, r)
+	for i := 0; i < n; i++ {
+		last := L.RowPtr[i+1] - 1
+		for k := L.RowPtr[i]; k < last; k++ {
+			z[i] -= L.Values[k] * z[L.ColIndex[k]]
+		}
+		z[i] /= L.Values[last]
+	}
+	// L^T * z = y, the rows of L are the columns of L^T
+	for i := n - 1; i >= 0; i-- {
+		last := L.RowPtr[i+1] - 1
+		z[i] /= L.Values[last]
+		for k := L.RowPtr[i]; k < last; k++ {
+			z[L.ColIndex[k]] -= L.Values[k] * z[i]
+		}
+	}
+
+	return z, nil
+}
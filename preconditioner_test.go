@@ -0,0 +1,134 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPreconditionersApply(t *testing.T) {
+	A := [][]float64{
+		{4, -1, 0, -1},
+		{-1, 4, -1, 0},
+		{0, -1, 4, -1},
+		{-1, 0, -1, 4},
+	}
+	csr := NewCSR(Matrix{Data: A})
+	r := []float64{1, 2, -1, 3}
+
+	// the dense M of each preconditioner, M * Apply(r) must be r
+	D := [][]float64{{4, 0, 0, 0}, {0, 4, 0, 0}, {0, 0, 4, 0}, {0, 0, 0, 4}}
+	omega := 1.2
+	lower := CopyMatrix(A)
+	upper := CopyMatrix(A)
+	for i := range A {
+		for j := range A[i] {
+			if j > i {
+				lower[i][j] = 0
+			}
+			if j < i {
+				upper[i][j] = 0
+			}
+		}
+		lower[i][i] = A[i][i] / omega
+		upper[i][i] = A[i][i] / omega
+	}
+	dInv := MultiplyMatrixByScalar(GenerateIdentityMatrix(4), omega/4)
+	ssorM := MultiplyMatrixByScalar(MultiplyMatrices(MultiplyMatrices(lower, dInv), upper), omega/(2-omega))
+
+	jacobi, err := NewJacobiPreconditioner(csr)
+	if err != nil {
+		t.Fatalf("NewJacobiPreconditioner() unexpected error: %v", err)
+	}
+	ssor, err := NewSSORPreconditioner(csr, omega)
+	if err != nil {
+		t.Fatalf("NewSSORPreconditioner() unexpected error: %v", err)
+	}
+	ic, err := NewIncompleteCholesky(csr)
+	if err != nil {
+		t.Fatalf("NewIncompleteCholesky() unexpected error: %v", err)
+	}
+	L := ic.L.ToMatrix().Data
+	icM := MultiplyMatrices(L, TransposeMatrix(L))
+
+	tests := []struct {
+		name           string
+		preconditioner Preconditioner
+		M              [][]float64
+	}{
+		{name: "Jacobi", preconditioner: jacobi, M: D},
+		{name: "SSOR", preconditioner: ssor, M: ssorM},
+		{name: "incomplete Cholesky", preconditioner: ic, M: icM},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, err := tt.preconditioner.Apply(r)
+			if err != nil {
+				t.Fatalf("Apply() unexpected error: %v", err)
+			}
+			got := TransposeMatrix(MultiplyMatrices(tt.M, RowToColumnVector(z)))[0]
+			for i := range r {
+				if math.Abs(got[i]-r[i]) > 1e-12 {
+					t.Fatalf("M * Apply(r) = %v, want %v", got, r)
+				}
+			}
+
+			if _, err := tt.preconditioner.Apply(r[:2]); !errors.Is(err, ErrDimensionMismatch) {
+				t.Errorf("Apply() error = %v, want %v", err, ErrDimensionMismatch)
+			}
+		})
+	}
+
+	// L keeps the pattern of the lower triangle of A, the (3, 1) fill in is dropped
+	for i := range L {
+		for j := 0; j <= i; j++ {
+			if A[i][j] == 0 && L[i][j] != 0 {
+				t.Errorf("incomplete Cholesky L[%d][%d] = %v, want 0", i, j, L[i][j])
+			}
+		}
+	}
+}
+
+func TestIncompleteCholeskyOfTridiagonalIsExact(t *testing.T) {
+	// a tridiagonal matrix has no fill in, so IC(0) is the Cholesky factorization
+	A := laplacian1D(6).ToCSR()
+	ic, err := NewIncompleteCholesky(A)
+	if err != nil {
+		t.Fatalf("NewIncompleteCholesky() unexpected error: %v", err)
+	}
+	chol, err := NewCholesky(A.ToMatrix())
+	if err != nil {
+		t.Fatalf("NewCholesky() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(ic.L.ToMatrix().Data, chol.L.Data) {
+		t.Errorf("IncompleteCholesky L = %v, want %v", ic.L.ToMatrix().Data, chol.L.Data)
+	}
+}
+
+func TestPreconditionerErrors(t *testing.T) {
+	zeroDiagonal := NewCSR(Matrix{Data: [][]float64{{0, 1}, {1, 2}}})
+	if _, err := NewJacobiPreconditioner(zeroDiagonal); !errors.Is(err, ErrSingular) {
+		t.Errorf("NewJacobiPreconditioner() error = %v, want %v", err, ErrSingular)
+	}
+	if _, err := NewSSORPreconditioner(zeroDiagonal, 1); !errors.Is(err, ErrSingular) {
+		t.Errorf("NewSSORPreconditioner() error = %v, want %v", err, ErrSingular)
+	}
+	if _, err := NewIncompleteCholesky(zeroDiagonal); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("NewIncompleteCholesky() error = %v, want %v", err, ErrNotPositiveDefinite)
+	}
+
+	A := laplacian1D(3).ToCSR()
+	for _, omega := range []float64{0, 2, -1, math.NaN()} {
+		if _, err := NewSSORPreconditioner(A, omega); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("NewSSORPreconditioner(omega = %v) error = %v, want %v", omega, err, ErrInvalidArgument)
+		}
+	}
+
+	indefinite := NewCSR(Matrix{Data: [][]float64{{1, 2}, {2, 1}}})
+	if _, err := NewIncompleteCholesky(indefinite); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("NewIncompleteCholesky() error = %v, want %v", err, ErrNotPositiveDefinite)
+	}
+	if _, err := NewJacobiPreconditioner(NewCSR(Matrix{Data: [][]float64{{1, 2}}})); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("NewJacobiPreconditioner() error = %v, want %v", err, ErrNotSquare)
+	}
+}
@@ -0,0 +1,276 @@
+package linearalgebra
+
+import "math"
+
+// QRMode selects the size of the Q and R factors
+type QRMode int
+
+const (
+	// QRThin returns Q as m x k and R as k x n where k = min(m, n)
+	QRThin QRMode = iota
+	// QRFull returns Q as m x m and R as m x n
+	QRFull
+)
+
+// QR is the QR factorization of an m x n matrix A
+// A * P = Q * R
+// where Q has orthonormal columns, R is upper triangular and P is a permutation
+// of the columns. Without column pivoting P is the identity.
+type QR struct {
+	// Q has orthonormal columns
+	Q Matrix
+	// R is upper triangular, its diagonal is non negative
+	R Matrix
+	// Perm[j] is the column of A that ends up in column j of A * P
+	Perm []int
+	// Rank is the number of diagonal entries of R that are not 0
+	// relative to the largest one. It is only reliable with column pivoting.
+	Rank int
+
+	pivoting bool
+	rows     int
+	cols     int
+}
+
+// NewQR computes the QR factorization of a matrix using Householder reflections.
+// Each column is zeroed below the diagonal by the reflection H = I - beta*v*v^T,
+// which is orthogonal by construction, so Q stays orthonormal even when the
+// columns of A are nearly dependent.
+func NewQR(m Matrix, mode QRMode) (QR, error) {
+	return newQR(m, mode, false)
+}
+
+// NewQRWithPivoting computes the QR factorization with column pivoting.
+// At each step the remaining column with the largest norm is moved into the pivot
+// position, so the diagonal of R is non increasing and the number of non zero
+// entries on it is the numerical rank of A.
+func NewQRWithPivoting(m Matrix, mode QRMode) (QR, error) {
+	return newQR(m, mode, true)
+}
+
+func newQR(m Matrix, mode QRMode, pivoting bool) (QR, error) {
+	if err := checkRectangular("NewQR", m.Data); err != nil {
+		return QR{}, err
+	}
+
+	shape := GetShape(m.Data)
+	rows, cols := shape.Rows, shape.Cols
+	k := min(rows, cols)
+
+	a := CopyMatrix(m.Data)
+	perm := make([]int, cols)
+	for j := range perm {
+		perm[j] = j
+	}
+
+	vs := make([][]float64, k)
+	betas := make([]float64, k)
+	column := make([]float64, rows)
+	for step := 0; step < k; step++ {
+		if pivoting {
+			bestCol := step
+			bestNorm := -1.0
+			for j := step; j < cols; j++ {
+				for i := step; i < rows; i++ {
+					column[i-step] = a[i][j]
+				}
+				if norm := norm2(column[:rows-step]); norm > bestNorm {
+					bestNorm = norm
+					bestCol = j
+				}
+			}
+			if bestCol != step {
+				for i := range a {
+					a[i][step], a[i][bestCol] = a[i][bestCol], a[i][step]
+				}
+				perm[step], perm[bestCol] = perm[bestCol], perm[step]
+			}
+		}
+
+		// build the reflector that sends a[step:, step] to alpha * e1
+		v := make([]float64, rows-step)
+		for i := step; i < rows; i++ {
+			v[i-step] = a[i][step]
+		}
+		norm := norm2(v)
+		if norm == 0 {
+			// the column is already 0 below the diagonal
+			vs[step] = v
+			continue
+		}
+		alpha := -norm
+		if v[0] < 0 {
+			alpha = norm
+		}
+		v[0] -= alpha
+		vtv := 0.0
+		for _, value := range v {
+			vtv += value * value
+		}
+		beta := 2 / vtv
+		vs[step] = v
+		betas[step] = beta
+
+		// apply H to the remaining columns
+		for j := step; j < cols; j++ {
+			s := 0.0
+			for i := step; i < rows; i++ {
+				s += v[i-step] * a[i][j]
+			}
+			s *= beta
+			for i := step; i < rows; i++ {
+				a[i][j] -= s * v[i-step]
+			}
+		}
+		for i := step + 1; i < rows; i++ {
+			a[i][step] = 0
+		}
+	}
+
+	qCols := k
+	rRows := k
+	if mode == QRFull {
+		qCols = rows
+		rRows = rows
+	}
+
+	// Q = H0 * H1 * ... * Hk-1 applied to the first qCols columns of the identity
+	Q := make([][]float64, rows)
+	for i := range Q {
+		Q[i] = make([]float64, qCols)
+		if i < qCols {
+			Q[i][i] = 1
+		}
+	}
+	for step := k - 1; step >= 0; step-- {
+		v, beta := vs[step], betas[step]
+		if beta == 0 {
+			continue
+		}
+		for j := 0; j < qCols; j++ {
+			s := 0.0
+			for i := step; i < rows; i++ {
+				s += v[i-step] * Q[i][j]
+			}
+			s *= beta
+			for i := step; i < rows; i++ {
+				Q[i][j] -= s * v[i-step]
+			}
+		}
+	}
+
+	R := make([][]float64, rRows)
+	for i := range R {
+		R[i] = make([]float64, cols)
+		if i < k {
+			copy(R[i][i:], a[i][i:])
+		}
+	}
+
+	// make the diagonal of R non negative by flipping the sign of
+	// a row of R and the matching column of Q, this makes the factors unique
+	for i := 0; i < k; i++ {
+		if R[i][i] < 0 {
+			for j := i; j < cols; j++ {
+				R[i][j] = -R[i][j]
+			}
+			for r := 0; r < rows; r++ {
+				Q[r][i] = -Q[r][i]
+			}
+		}
+	}
+
+	rank := 0
+	if k > 0 {
+		maxDiag := 0.0
+		for i := 0; i < k; i++ {
+			maxDiag = math.Max(maxDiag, R[i][i])
+		}
+		tol := float64(max(rows, cols)) * machineEpsilon * maxDiag
+		for i := 0; i < k; i++ {
+			if R[i][i] > tol {
+				rank++
+			}
+		}
+	}
+
+	return QR{
+		Q:        Matrix{Data: Q},
+		R:        Matrix{Data: R},
+		Perm:     perm,
+		Rank:     rank,
+		pivoting: pivoting,
+		rows:     rows,
+		cols:     cols,
+	}, nil
+}
+
+// Solve returns the least squares solution x that minimizes ||A*x - b||.
+// It solves R * z = Q^T * b by back substitution and undoes the column permutation.
+// With column pivoting a rank deficient A gets the basic solution, where the
+// entries of x for the dependent columns are 0. Without pivoting a rank deficient
+// A returns ErrSingular.
+func (f QR) Solve(b []float64) ([]float64, error) {
+	if len(b) != f.rows {
+		return nil, &ShapeError{
+			Op:     "QR.Solve",
+			Shapes: []Shape{{Rows: f.rows, Cols: f.cols}, {Rows: len(b), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	k := min(f.rows, f.cols)
+	r := f.Rank
+	if !f.pivoting {
+		if f.Rank < k {
+			return nil, &ShapeError{Op: "QR.Solve", Shapes: []Shape{{Rows: f.rows, Cols: f.cols}}, Err: ErrSingular}
+		}
+		r = k
+	}
+
+	// Q^T * b, only the first r entries are needed
+	qtb := make([]float64, r)
+	for j := 0; j < r; j++ {
+		for i := 0; i < f.rows; i++ {
+			qtb[j] += f.Q.Data[i][j] * b[i]
+		}
+	}
+
+	// back substitution on the leading r x r block of R
+	z := make([]float64, r)
+	for i := r - 1; i >= 0; i-- {
+		sum := qtb[i]
+		for j := i + 1; j < r; j++ {
+			sum -= f.R.Data[i][j] * z[j]
+		}
+		z[i] = sum / f.R.Data[i][i]
+	}
+
+	x := make([]float64, f.cols)
+	for j := 0; j < r; j++ {
+		x[f.Perm[j]] = z[j]
+	}
+
+	return x, nil
+}
+
+// norm2 returns the euclidean norm of a vector, scaling the entries
+// by the largest one so the sum of squares cannot overflow
+func norm2(vector []float64) float64 {
+	scale := 0.0
+	sumSquares := 1.0
+	for _, value := range vector {
+		if value == 0 {
+			continue
+		}
+		absValue := math.Abs(value)
+		if scale < absValue {
+			sumSquares = 1 + sumSquares*(scale/absValue)*(scale/absValue)
+			scale = absValue
+		} else {
+			sumSquares += (absValue / scale) * (absValue / scale)
+		}
+	}
+
+	return scale * math.Sqrt(sumSquares)
+}
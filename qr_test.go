@@ -0,0 +1,302 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// permuteColumns returns A * P where column j of the result is column perm[j] of A
+func permuteColumns(matrix [][]float64, perm []int) [][]float64 {
+	res := make([][]float64, len(matrix))
+	for i := range matrix {
+		res[i] = make([]float64, len(perm))
+		for j := range perm {
+			res[i][j] = matrix[i][perm[j]]
+		}
+	}
+
+	return res
+}
+
+func TestNewQR(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		mode   QRMode
+		wantQ  Shape
+		wantR  Shape
+	}{
+		{
+			name:   "square thin",
+			matrix: [][]float64{{12, -51, 4}, {6, 167, -68}, {-4, 24, -41}},
+			mode:   QRThin,
+			wantQ:  Shape{Rows: 3, Cols: 3},
+			wantR:  Shape{Rows: 3, Cols: 3},
+		},
+		{
+			name:   "tall thin",
+			matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}},
+			mode:   QRThin,
+			wantQ:  Shape{Rows: 4, Cols: 2},
+			wantR:  Shape{Rows: 2, Cols: 2},
+		},
+		{
+			name:   "tall full",
+			matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}},
+			mode:   QRFull,
+			wantQ:  Shape{Rows: 4, Cols: 4},
+			wantR:  Shape{Rows: 4, Cols: 2},
+		},
+		{
+			name:   "wide thin",
+			matrix: [][]float64{{1, 2, 3, 4}, {5, 6, 7, 8}},
+			mode:   QRThin,
+			wantQ:  Shape{Rows: 2, Cols: 2},
+			wantR:  Shape{Rows: 2, Cols: 4},
+		},
+		{
+			name:   "rank deficient full",
+			matrix: [][]float64{{1, 2, 3}, {2, 4, 6}, {1, 0, 1}},
+			mode:   QRFull,
+			wantQ:  Shape{Rows: 3, Cols: 3},
+			wantR:  Shape{Rows: 3, Cols: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qr, err := NewQR(Matrix{Data: tt.matrix}, tt.mode)
+			if err != nil {
+				t.Fatalf("NewQR() unexpected error: %v", err)
+			}
+			if GetShape(qr.Q.Data) != tt.wantQ || GetShape(qr.R.Data) != tt.wantR {
+				t.Fatalf("NewQR() Q is %v and R is %v, want %v and %v",
+					GetShape(qr.Q.Data), GetShape(qr.R.Data), tt.wantQ, tt.wantR)
+			}
+
+			if got := MultiplyMatrices(qr.Q.Data, qr.R.Data); !areMatricesEqual(got, tt.matrix) {
+				t.Errorf("Q * R = %v, want %v", got, tt.matrix)
+			}
+
+			qtq := MultiplyMatrices(TransposeMatrix(qr.Q.Data), qr.Q.Data)
+			if !areMatricesEqual(qtq, GenerateIdentityMatrix(tt.wantQ.Cols)) {
+				t.Errorf("Q^T * Q = %v, want identity", qtq)
+			}
+
+			for i := range qr.R.Data {
+				for j := 0; j < i && j < len(qr.R.Data[i]); j++ {
+					if qr.R.Data[i][j] != 0 {
+						t.Errorf("R is not upper triangular: %v", qr.R.Data)
+					}
+				}
+				if i < len(qr.R.Data[i]) && qr.R.Data[i][i] < 0 {
+					t.Errorf("R has a negative diagonal entry: %v", qr.R.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestNewQRKnownFactors(t *testing.T) {
+	// textbook example, A = Q * R with
+	// R = [[14, 21, -14], [0, 175, -70], [0, 0, 35]]
+	A := [][]float64{{12, -51, 4}, {6, 167, -68}, {-4, 24, -41}}
+	qr, err := NewQR(Matrix{Data: A}, QRThin)
+	if err != nil {
+		t.Fatalf("NewQR() unexpected error: %v", err)
+	}
+
+	wantR := [][]float64{{14, 21, -14}, {0, 175, -70}, {0, 0, 35}}
+	wantQ := [][]float64{
+		{6.0 / 7, -69.0 / 175, -58.0 / 175},
+		{3.0 / 7, 158.0 / 175, 6.0 / 175},
+		{-2.0 / 7, 6.0 / 35, -33.0 / 35},
+	}
+	if !areMatricesEqual(qr.R.Data, wantR) {
+		t.Errorf("NewQR() R = %v, want %v", qr.R.Data, wantR)
+	}
+	if !areMatricesEqual(qr.Q.Data, wantQ) {
+		t.Errorf("NewQR() Q = %v, want %v", qr.Q.Data, wantQ)
+	}
+}
+
+func TestNewQRNearlyDependentColumns(t *testing.T) {
+	// Gram-Schmidt loses orthogonality on the Lauchli matrix,
+	// Householder keeps Q orthonormal to machine precision
+	eps := 1e-8
+	A := [][]float64{
+		{1, 1, 1},
+		{eps, 0, 0},
+		{0, eps, 0},
+		{0, 0, eps},
+	}
+	qr, err := NewQR(Matrix{Data: A}, QRThin)
+	if err != nil {
+		t.Fatalf("NewQR() unexpected error: %v", err)
+	}
+
+	qtq := MultiplyMatrices(TransposeMatrix(qr.Q.Data), qr.Q.Data)
+	for i := range qtq {
+		for j := range qtq[i] {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if math.Abs(qtq[i][j]-want) > 1e-12 {
+				t.Fatalf("Q^T * Q = %v, want identity", qtq)
+			}
+		}
+	}
+}
+
+func TestNewQRWithPivoting(t *testing.T) {
+	tests := []struct {
+		name     string
+		matrix   [][]float64
+		wantRank int
+	}{
+		{
+			name:     "full rank",
+			matrix:   [][]float64{{1, 2}, {3, 4}, {5, 7}},
+			wantRank: 2,
+		},
+		{
+			name:     "dependent columns",
+			matrix:   [][]float64{{1, 2, 3}, {2, 4, 5}, {3, 6, 7}, {4, 8, 9}},
+			wantRank: 2,
+		},
+		{
+			name:     "rank one",
+			matrix:   [][]float64{{1, 2, 3}, {2, 4, 6}, {3, 6, 9}},
+			wantRank: 1,
+		},
+		{
+			name:     "zero matrix",
+			matrix:   [][]float64{{0, 0}, {0, 0}},
+			wantRank: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qr, err := NewQRWithPivoting(Matrix{Data: tt.matrix}, QRThin)
+			if err != nil {
+				t.Fatalf("NewQRWithPivoting() unexpected error: %v", err)
+			}
+			if qr.Rank != tt.wantRank {
+				t.Errorf("NewQRWithPivoting() Rank = %d, want %d", qr.Rank, tt.wantRank)
+			}
+			if qr.Rank != GetMatrixRank(tt.matrix) {
+				t.Errorf("NewQRWithPivoting() Rank = %d, GetMatrixRank = %d", qr.Rank, GetMatrixRank(tt.matrix))
+			}
+
+			// A * P = Q * R
+			ap := permuteColumns(tt.matrix, qr.Perm)
+			if got := MultiplyMatrices(qr.Q.Data, qr.R.Data); !areMatricesEqual(got, ap) {
+				t.Errorf("Q * R = %v, want A * P = %v", got, ap)
+			}
+
+			// the diagonal of R is non increasing
+			for i := 1; i < len(qr.R.Data); i++ {
+				if qr.R.Data[i][i] > qr.R.Data[i-1][i-1]+1e-12 {
+					t.Errorf("diagonal of R is not sorted: %v", qr.R.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestQR_Solve(t *testing.T) {
+	tests := []struct {
+		name     string
+		A        [][]float64
+		b        []float64
+		pivoting bool
+		want     []float64
+		wantErr  error
+	}{
+		{
+			name: "square system",
+			A:    [][]float64{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}},
+			b:    []float64{8, -11, -3},
+			want: []float64{2, 3, -1},
+		},
+		{
+			// fit y = c0 + c1*x through (0, 1), (1, 3), (2, 4), (3, 4)
+			// the normal equations give c0 = 1.5, c1 = 1
+			name: "overdetermined line fit",
+			A:    [][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
+			b:    []float64{1, 3, 4, 4},
+			want: []float64{1.5, 1},
+		},
+		{
+			name:    "rank deficient without pivoting",
+			A:       [][]float64{{1, 2}, {2, 4}, {3, 6}},
+			b:       []float64{1, 2, 3},
+			wantErr: ErrSingular,
+		},
+		{
+			// the second column is twice the first, the basic solution puts
+			// all the weight on the column with the largest norm
+			name:     "rank deficient with pivoting",
+			A:        [][]float64{{1, 2}, {2, 4}, {3, 6}},
+			b:        []float64{2, 4, 6},
+			pivoting: true,
+			want:     []float64{0, 1},
+		},
+		{
+			name:    "wrong length",
+			A:       [][]float64{{1, 0}, {0, 1}},
+			b:       []float64{1, 2, 3},
+			wantErr: ErrDimensionMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var qr QR
+			var err error
+			if tt.pivoting {
+				qr, err = NewQRWithPivoting(Matrix{Data: tt.A}, QRThin)
+			} else {
+				qr, err = NewQR(Matrix{Data: tt.A}, QRThin)
+			}
+			if err != nil {
+				t.Fatalf("NewQR() unexpected error: %v", err)
+			}
+
+			got, err := qr.Solve(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("QR.Solve() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-10 {
+					t.Errorf("QR.Solve() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNorm2(t *testing.T) {
+	tests := []struct {
+		name   string
+		vector []float64
+		want   float64
+	}{
+		{name: "empty", vector: []float64{}, want: 0},
+		{name: "3 4 5", vector: []float64{3, 4}, want: 5},
+		{name: "negative entries", vector: []float64{-3, 0, -4}, want: 5},
+		{name: "would overflow", vector: []float64{3e200, 4e200}, want: 5e200},
+		{name: "would underflow", vector: []float64{3e-200, 4e-200}, want: 5e-200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := norm2(tt.vector); math.Abs(got-tt.want) > 1e-12*tt.want {
+				t.Errorf("norm2() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
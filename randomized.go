@@ -0,0 +1,183 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RandomizedSVDOptions controls RandomizedSVD
+type RandomizedSVDOptions struct {
+	// Oversampling is the number of random directions sampled on top of the k
+	// wanted ones, 10 if it is 0. A few more directions make it very unlikely
+	// that the sample misses part of the top k singular subspace.
+	Oversampling int
+	// PowerIterations is the number of times the sample is multiplied by A * A^T,
+	// 2 if it is 0 and none if it is negative. Every iteration raises the singular
+	// values to a higher power, which sharpens the sample when they decay slowly.
+	PowerIterations int
+	// Seed seeds the random test matrix, the same seed gives the same result
+	Seed int64
+	// Sign is the sign convention of the singular vectors
+	Sign SignConvention
+}
+
+// RandomizedSVD returns the k largest singular values of an m x n matrix A and
+// their singular vectors, like TruncatedSVD, with the randomized range finder of
+// Halko, Martinsson and Tropp:
+//
+//  1. Y = A * Omega for an n x (k + Oversampling) Gaussian matrix Omega
+//  2. Y = (A * A^T)^q * Y for q power iterations, orthonormalized after every step
+//  3. Q is an orthonormal basis of Y, so A is close to Q * Q^T * A
+//  4. the SVD of the small matrix B = Q^T * A = Ub * S * V^T gives U = Q * Ub
+//
+// It passes over A only 2q + 2 times and factors nothing but the m x l sample and
+// the l x n matrix B, with l = k + Oversampling, so for k much smaller than
+// min(m, n) it is much faster than SVD. The result is exact when k + Oversampling >= rank(A), otherwise it is
+// an approximation whose error shrinks with Oversampling and PowerIterations.
+// It returns an error if k is not in [0, min(m, n)], A is not rectangular or the
+// options are invalid.
+func RandomizedSVD(m *Matrix, k int, opts RandomizedSVDOptions) (SVDResult, error) {
+	const op = "RandomizedSVD"
+	if err := checkRectangular(op, m.Data); err != nil {
+		return SVDResult{}, err
+	}
+	rows, cols := m.Dims()
+	if k < 0 || k > min(rows, cols) {
+		return SVDResult{}, &IndexError{Op: op, Index: k, Len: min(rows, cols)}
+	}
+	if opts.Oversampling < 0 {
+		return SVDResult{}, fmt.Errorf("%s: %w: oversampling %d must not be negative", op, ErrInvalidArgument, opts.Oversampling)
+	}
+	if err := opts.Sign.validate(op); err != nil {
+		return SVDResult{}, err
+	}
+	if opts.Oversampling == 0 {
+		opts.Oversampling = 10
+	}
+	if opts.PowerIterations == 0 {
+		opts.PowerIterations = 2
+	}
+	if k == 0 {
+		return SVDResult{
+			U:              NewZeroMatrix(rows, 0),
+			S:              NewZeroMatrix(0, 0),
+			V:              NewZeroMatrix(cols, 0),
+			SingularValues: []float64{},
+		}, nil
+	}
+
+	// sampling more than min(m, n) directions cannot add anything
+	samples := min(k+opts.Oversampling, rows, cols)
+	rng := rand.New(rand.NewSource(opts.Seed))
+	omega := NewZeroMatrix(samples, cols)
+	for i := range omega.Data {
+		for j := range omega.Data[i] {
+			omega.Data[i][j] = rng.NormFloat64()
+		}
+	}
+
+	// the bases are kept transposed, one basis vector per row, so every vector
+	// is contiguous: Qt = Q^T and (A * Omega)^T = Omega^T * A^T
+	Qt := multiplyTranspose(omega.Data, m.Data)
+	orthonormalizeRows(Qt)
+	for q := 0; q < opts.PowerIterations; q++ {
+		// orthonormalize between the multiplications, otherwise the vectors all
+		// turn towards the top singular vector and the rest is lost to rounding
+		Zt := MultiplyMatrices(Qt, m.Data)
+		orthonormalizeRows(Zt)
+		Qt = multiplyTranspose(Zt, m.Data)
+		orthonormalizeRows(Qt)
+	}
+
+	smallU, sigma, V, err := jacobiSVD(MultiplyMatrices(Qt, m.Data))
+	if err != nil {
+		return SVDResult{}, err
+	}
+	U := transposeMultiply(Qt, smallU)
+
+	// keep the k largest, the oversampled directions are the least accurate
+	truncate := func(matrix [][]float64) [][]float64 {
+		res := make([][]float64, len(matrix))
+		for i := range res {
+			res[i] = matrix[i][:k]
+		}
+		return res
+	}
+	U, V = truncate(U), truncate(V)
+	// the vectors of 0 singular values are not determined by A, make them
+	// orthonormal like SVD does
+	rank := numericalRank(sigma, rows, cols)
+	U = completeOrthonormalColumns(U, rank, k)
+	V = completeOrthonormalColumns(V, rank, k)
+	if opts.Sign == SignLargestPositive {
+		canonicalizeSVDSigns(U, V)
+	}
+
+	S := NewZeroMatrix(k, k)
+	for i := 0; i < k; i++ {
+		S.Data[i][i] = sigma[i]
+	}
+
+	return SVDResult{
+		U:              Matrix{Data: U},
+		S:              S,
+		V:              Matrix{Data: V},
+		SingularValues: sigma[:k],
+	}, nil
+}
+
+// orthonormalizeRows makes the rows of a orthonormal with Gram-Schmidt, run
+// twice per row so they stay orthogonal to machine precision. A row that is a
+// combination of the rows before it becomes 0, a 0 basis vector adds nothing to
+// Q * Q^T * A so the result stays correct.
+func orthonormalizeRows(a [][]float64) {
+	for i, row := range a {
+		before := norm2(row)
+		for pass := 0; pass < 2; pass++ {
+			for _, previous := range a[:i] {
+				axpy(-dot(previous, row), previous, row)
+			}
+		}
+		norm := norm2(row)
+		if norm <= 1e-12*before {
+			norm = 0
+		}
+		for j := range row {
+			if norm == 0 {
+				row[j] = 0
+			} else {
+				row[j] /= norm
+			}
+		}
+	}
+}
+
+// multiplyTranspose returns X * A^T without transposing A, every entry is the
+// dot product of two rows
+func multiplyTranspose(x, a [][]float64) [][]float64 {
+	res := NewZeroMatrix(len(x), len(a))
+	for i := range x {
+		for j := range a {
+			res.Data[i][j] = dot(x[i], a[j])
+		}
+	}
+
+	return res.Data
+}
+
+// transposeMultiply returns A^T * B without transposing A. It walks both
+// matrices row by row, so a tall A is read in the order it is stored.
+func transposeMultiply(a, b [][]float64) [][]float64 {
+	cols := 0
+	if len(a) > 0 {
+		cols = len(a[0])
+	}
+	res := NewZeroMatrix(cols, GetShape(b).Cols)
+	for i := range a {
+		for j, aij := range a[i] {
+			axpy(aij, b[i], res.Data[j])
+		}
+	}
+
+	return res.Data
+}
@@ -0,0 +1,226 @@
+package linearalgebra
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// matrixWithSpectrum returns a rows x cols matrix with the given singular
+// values and random singular vectors
+func matrixWithSpectrum(rng *rand.Rand, rows, cols int, sigma []float64) [][]float64 {
+	Ut := randomMatrix(rng, len(sigma), rows)
+	orthonormalizeRows(Ut)
+	U := TransposeMatrix(Ut)
+	Vt := randomMatrix(rng, len(sigma), cols)
+	orthonormalizeRows(Vt)
+	V := TransposeMatrix(Vt)
+	for i := range U {
+		for j := range sigma {
+			U[i][j] *= sigma[j]
+		}
+	}
+	return MultiplyMatrices(U, TransposeMatrix(V))
+}
+
+func TestRandomizedSVD(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	decaying := make([]float64, 30)
+	for i := range decaying {
+		decaying[i] = math.Pow(0.5, float64(i))
+	}
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		k      int
+		opts   RandomizedSVDOptions
+		tol    float64
+	}{
+		{
+			name:   "low rank tall",
+			matrix: MultiplyMatrices(randomMatrix(rng, 200, 4), randomMatrix(rng, 4, 15)),
+			k:      3,
+			tol:    1e-10,
+		},
+		{
+			name:   "low rank wide",
+			matrix: MultiplyMatrices(randomMatrix(rng, 12, 3), randomMatrix(rng, 3, 80)),
+			k:      3,
+			opts:   RandomizedSVDOptions{Oversampling: 2, PowerIterations: -1},
+			tol:    1e-10,
+		},
+		{
+			name:   "all components",
+			matrix: randomMatrix(rng, 40, 6),
+			k:      6,
+			tol:    1e-10,
+		},
+		{
+			name:   "decaying spectrum",
+			matrix: matrixWithSpectrum(rng, 300, 60, decaying),
+			k:      5,
+			tol:    1e-8,
+		},
+		{
+			name:   "decaying spectrum no power iterations",
+			matrix: matrixWithSpectrum(rng, 300, 60, decaying),
+			k:      5,
+			opts:   RandomizedSVDOptions{PowerIterations: -1, Seed: 7},
+			tol:    1e-3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatrix(tt.matrix)
+			got, err := RandomizedSVD(&m, tt.k, tt.opts)
+			if err != nil {
+				t.Fatalf("RandomizedSVD() unexpected error: %v", err)
+			}
+			want, err := TruncatedSVD(&m, tt.k)
+			if err != nil {
+				t.Fatalf("TruncatedSVD() unexpected error: %v", err)
+			}
+
+			if len(got.SingularValues) != tt.k || got.U.Cols() != tt.k || got.V.Cols() != tt.k {
+				t.Fatalf("RandomizedSVD() has %d singular values, U %dx%d, V %dx%d, want %d columns",
+					len(got.SingularValues), got.U.Rows(), got.U.Cols(), got.V.Rows(), got.V.Cols(), tt.k)
+			}
+			for i := range want.SingularValues {
+				if math.Abs(got.SingularValues[i]-want.SingularValues[i]) > tt.tol*want.SingularValues[0] {
+					t.Errorf("sigma[%d] = %v, want %v", i, got.SingularValues[i], want.SingularValues[i])
+				}
+			}
+			// both follow the sign convention, so the vectors match one to one
+			if !MatricesNearlyEqual(got.V.Data, want.V.Data, Tolerance{Absolute: math.Sqrt(tt.tol)}) {
+				t.Errorf("RandomizedSVD().V differs from TruncatedSVD().V")
+			}
+			identity := GenerateIdentityMatrix(tt.k)
+			if !MatricesNearlyEqual(MultiplyMatrices(TransposeMatrix(got.U.Data), got.U.Data), identity, Tolerance{Absolute: 1e-12}) {
+				t.Errorf("the columns of U are not orthonormal")
+			}
+			if !MatricesNearlyEqual(MultiplyMatrices(TransposeMatrix(got.V.Data), got.V.Data), identity, Tolerance{Absolute: 1e-12}) {
+				t.Errorf("the columns of V are not orthonormal")
+			}
+		})
+	}
+}
+
+func TestRandomizedSVDSeed(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := NewMatrix(randomMatrix(rng, 100, 30))
+	first, err := RandomizedSVD(&m, 4, RandomizedSVDOptions{Seed: 42})
+	if err != nil {
+		t.Fatalf("RandomizedSVD() unexpected error: %v", err)
+	}
+	again, _ := RandomizedSVD(&m, 4, RandomizedSVDOptions{Seed: 42})
+	if !reflect.DeepEqual(first, again) {
+		t.Errorf("RandomizedSVD() with the same seed gave different results")
+	}
+	other, _ := RandomizedSVD(&m, 4, RandomizedSVDOptions{Seed: 43})
+	if reflect.DeepEqual(first.SingularValues, other.SingularValues) {
+		t.Errorf("RandomizedSVD() with another seed gave the same singular values bit for bit")
+	}
+
+	empty, err := RandomizedSVD(&m, 0, RandomizedSVDOptions{})
+	if err != nil {
+		t.Fatalf("RandomizedSVD() unexpected error: %v", err)
+	}
+	if empty.U.Rows() != 100 || empty.V.Rows() != 30 || len(empty.SingularValues) != 0 {
+		t.Errorf("RandomizedSVD(k = 0) = U %v, V %v, sigma %v", GetShape(empty.U.Data), GetShape(empty.V.Data), empty.SingularValues)
+	}
+}
+
+func TestRandomizedSVDErrors(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2, 3}, {4, 5, 6}})
+	tests := []struct {
+		name string
+		m    Matrix
+		k    int
+		opts RandomizedSVDOptions
+		want error
+	}{
+		{name: "negative k", m: m, k: -1, want: ErrIndexOutOfRange},
+		{name: "k too large", m: m, k: 3, want: ErrIndexOutOfRange},
+		{name: "oversampling", m: m, k: 1, opts: RandomizedSVDOptions{Oversampling: -1}, want: ErrInvalidArgument},
+		{name: "sign", m: m, k: 1, opts: RandomizedSVDOptions{Sign: SignConvention(9)}, want: ErrInvalidArgument},
+		{name: "jagged", m: Matrix{Data: [][]float64{{1, 2}, {3}}}, k: 1, want: ErrDimensionMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RandomizedSVD(&tt.m, tt.k, tt.opts)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("RandomizedSVD() error = %v, want %v", err, tt.want)
+			}
+			var indexErr *IndexError
+			if errors.As(err, &indexErr) && indexErr.Len != 2 {
+				t.Errorf("RandomizedSVD() IndexError length = %d, want 2", indexErr.Len)
+			}
+		})
+	}
+}
+
+func TestFitPCARandomized(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	for _, standardize := range []bool{false, true} {
+		exact, err := FitPCA(m, PCAOptions{Components: 2, Standardize: standardize})
+		if err != nil {
+			t.Fatalf("FitPCA() unexpected error: %v", err)
+		}
+		randomized, err := FitPCA(m, PCAOptions{Components: 2, Standardize: standardize, Randomized: &RandomizedSVDOptions{}})
+		if err != nil {
+			t.Fatalf("FitPCA() randomized unexpected error: %v", err)
+		}
+
+		if math.Abs(randomized.TotalVariance-exact.TotalVariance) > 1e-10*exact.TotalVariance {
+			t.Errorf("TotalVariance = %v, want %v", randomized.TotalVariance, exact.TotalVariance)
+		}
+		for i, pc := range randomized.Components {
+			want := exact.Components[i]
+			if math.Abs(pc.Variance-want.Variance) > 1e-8*want.Variance {
+				t.Errorf("standardize %v: component %d variance = %v, want %v", standardize, i, pc.Variance, want.Variance)
+			}
+			if !MatricesNearlyEqual([][]float64{pc.Vector}, [][]float64{want.Vector}, Tolerance{Absolute: 1e-6}) {
+				t.Errorf("standardize %v: component %d = %v, want %v", standardize, i, pc.Vector, want.Vector)
+			}
+		}
+	}
+
+	if _, err := FitPCA(m, PCAOptions{VarianceThreshold: 0.9, Randomized: &RandomizedSVDOptions{}}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("FitPCA() error = %v, want %v", err, ErrInvalidArgument)
+	}
+}
+
+func BenchmarkRandomizedSVD(b *testing.B) {
+	m := CenterMatrix(ReadCSVToMatrixFromFile("data/pca_dataset.csv", true))
+	b.Run("SVD", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SVD(&m)
+		}
+	})
+	for _, k := range []int{2, 5} {
+		b.Run(fmt.Sprintf("RandomizedSVD_k%d", k), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := RandomizedSVD(&m, k, RandomizedSVDOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	b.Run("FitPCA_k2", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := FitPCA(m, PCAOptions{Components: 2}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("FitPCA_randomized_k2", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := FitPCA(m, PCAOptions{Components: 2, Randomized: &RandomizedSVDOptions{}}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RowOperationKind is one of the three elementary row operations
+type RowOperationKind int
+
+const (
+	// RowSwap exchanges Row and Source
+	RowSwap RowOperationKind = iota
+	// RowScale multiplies Row by Factor
+	RowScale
+	// RowAddMultiple adds Factor times Source to Row
+	RowAddMultiple
+)
+
+// RowOperation is an elementary row operation. Rows are 0 based,
+// String and LaTeX print them 1 based as R1, R2, ...
+type RowOperation struct {
+	Kind RowOperationKind
+	// Row is the row that changes
+	Row int
+	// Source is the other row of a RowSwap or RowAddMultiple
+	Source int
+	// Factor is the scale of a RowScale or the multiple of a RowAddMultiple
+	Factor *big.Rat
+}
+
+// Apply performs the operation on m in place
+func (op RowOperation) Apply(m GenericMatrix[*big.Rat]) {
+	f := ratArithmetic{}
+	switch op.Kind {
+	case RowSwap:
+		m.Data[op.Row], m.Data[op.Source] = m.Data[op.Source], m.Data[op.Row]
+	case RowScale:
+		for j, v := range m.Data[op.Row] {
+			m.Data[op.Row][j] = f.mul(op.Factor, v)
+		}
+	case RowAddMultiple:
+		for j, v := range m.Data[op.Source] {
+			m.Data[op.Row][j] = f.add(m.Data[op.Row][j], f.mul(op.Factor, v))
+		}
+	}
+}
+
+// ElementaryMatrix returns the n x n matrix E for which E * A applies the operation to A
+func (op RowOperation) ElementaryMatrix(n int) GenericMatrix[*big.Rat] {
+	E := NewGenericIdentityMatrix[*big.Rat](n)
+	op.Apply(E)
+	return E
+}
+
+// String returns the operation as "R1 <-> R2", "R2 -> 1/3 R2" or "R3 -> R3 - 2 R1"
+func (op RowOperation) String() string {
+	switch op.Kind {
+	case RowSwap:
+		return fmt.Sprintf("R%d <-> R%d", op.Row+1, op.Source+1)
+	case RowScale:
+		return fmt.Sprintf("R%d -> %s R%d", op.Row+1, op.Factor.RatString(), op.Row+1)
+	default:
+		sign, abs := "+", new(big.Rat).Abs(op.Factor)
+		if op.Factor.Sign() < 0 {
+			sign = "-"
+		}
+		coefficient := abs.RatString() + " "
+		if abs.Cmp(big.NewRat(1, 1)) == 0 {
+			coefficient = ""
+		}
+		return fmt.Sprintf("R%d -> R%d %s %sR%d", op.Row+1, op.Row+1, sign, coefficient, op.Source+1)
+	}
+}
+
+// LaTeX returns the operation as "R_1 \leftrightarrow R_2", "R_2 \to \frac{1}{3} R_2"
+// or "R_3 \to R_3 - 2 R_1"
+func (op RowOperation) LaTeX() string {
+	switch op.Kind {
+	case RowSwap:
+		return fmt.Sprintf(`R_{%d} \leftrightarrow R_{%d}`, op.Row+1, op.Source+1)
+	case RowScale:
+		return fmt.Sprintf(`R_{%d} \to %s R_{%d}`, op.Row+1, latexRat(op.Factor), op.Row+1)
+	default:
+		sign, abs := "+", new(big.Rat).Abs(op.Factor)
+		if op.Factor.Sign() < 0 {
+			sign = "-"
+		}
+		coefficient := latexRat(abs) + " "
+		if abs.Cmp(big.NewRat(1, 1)) == 0 {
+			coefficient = ""
+		}
+		return fmt.Sprintf(`R_{%d} \to R_{%d} %s %sR_{%d}`, op.Row+1, op.Row+1, sign, coefficient, op.Source+1)
+	}
+}
+
+// latexRat returns r as an integer or as \frac{p}{q}
+func latexRat(r *big.Rat) string {
+	r = rat(r)
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	if r.Sign() < 0 {
+		return fmt.Sprintf(`-\frac{%s}{%s}`, new(big.Int).Neg(r.Num()), r.Denom())
+	}
+
+	return fmt.Sprintf(`\frac{%s}{%s}`, r.Num(), r.Denom())
+}
+
+// latexMatrix returns m as a LaTeX bmatrix
+func latexMatrix(m GenericMatrix[*big.Rat]) string {
+	var sb strings.Builder
+	sb.WriteString(`\begin{bmatrix}`)
+	for i := range m.Data {
+		if i > 0 {
+			sb.WriteString(` \\`)
+		}
+		for j, v := range m.Data[i] {
+			if j > 0 {
+				sb.WriteString(" &")
+			}
+			sb.WriteString(" " + latexRat(v))
+		}
+	}
+	sb.WriteString(` \end{bmatrix}`)
+
+	return sb.String()
+}
+
+// EliminationStep is one row operation and the matrix right after it
+type EliminationStep struct {
+	Operation RowOperation
+	Matrix    GenericMatrix[*big.Rat]
+}
+
+// RREFTrace is the exact reduced row echelon form of a matrix together with
+// every row operation that produced it
+type RREFTrace struct {
+	// Input is a copy of the reduced matrix
+	Input GenericMatrix[*big.Rat]
+	// Steps are the row operations in the order they were applied
+	Steps []EliminationStep
+	// Result is the reduced row echelon form
+	Result GenericMatrix[*big.Rat]
+	// PivotColumns are the columns of the leading 1s, one per non zero row of Result
+	PivotColumns []int
+}
+
+// ExactRREF row reduces m in exact rational arithmetic the way it is done by
+// hand: column by column it swaps the first row with a non zero entry into
+// place, scales it to a leading 1 and clears the rest of the column, and
+// records every one of those operations.
+func ExactRREF(m GenericMatrix[*big.Rat]) (RREFTrace, error) {
+	shape, err := m.shape("ExactRREF")
+	if err != nil {
+		return RREFTrace{}, err
+	}
+
+	f := ratArithmetic{}
+	trace := RREFTrace{Input: NewGenericMatrix(m.Data), PivotColumns: []int{}}
+	matrix := NewGenericMatrix(m.Data)
+	apply := func(op RowOperation) {
+		op.Apply(matrix)
+		trace.Steps = append(trace.Steps, EliminationStep{Operation: op, Matrix: NewGenericMatrix(matrix.Data)})
+	}
+
+	pivotRow := 0
+	for col := 0; col < shape.Cols && pivotRow < shape.Rows; col++ {
+		best := findPivot[*big.Rat](f, matrix.Data, pivotRow, col, 0)
+		if best < 0 {
+			continue
+		}
+		if best != pivotRow {
+			apply(RowOperation{Kind: RowSwap, Row: pivotRow, Source: best})
+		}
+		if pivot := matrix.Data[pivotRow][col]; pivot.Cmp(big.NewRat(1, 1)) != 0 {
+			apply(RowOperation{Kind: RowScale, Row: pivotRow, Factor: new(big.Rat).Inv(pivot)})
+		}
+		for r := 0; r < shape.Rows; r++ {
+			if r == pivotRow || f.isZero(matrix.Data[r][col]) {
+				continue
+			}
+			apply(RowOperation{Kind: RowAddMultiple, Row: r, Source: pivotRow, Factor: new(big.Rat).Neg(matrix.Data[r][col])})
+		}
+
+		trace.PivotColumns = append(trace.PivotColumns, col)
+		pivotRow++
+	}
+	trace.Result = matrix
+
+	return trace, nil
+}
+
+// Rank returns the number of pivots
+func (t RREFTrace) Rank() int {
+	return len(t.PivotColumns)
+}
+
+// EliminationMatrix returns E = E_k * ... * E_1, the product of the elementary
+// matrices of the steps, so that E * Input = Result
+func (t RREFTrace) EliminationMatrix() GenericMatrix[*big.Rat] {
+	E := NewGenericIdentityMatrix[*big.Rat](len(t.Input.Data))
+	for _, step := range t.Steps {
+		step.Operation.Apply(E)
+	}
+
+	return E
+}
+
+// Text renders the input, then every operation followed by the matrix after it
+func (t RREFTrace) Text() string {
+	var sb strings.Builder
+	sb.WriteString(t.Input.String())
+	for _, step := range t.Steps {
+		sb.WriteString("\n" + step.Operation.String() + "\n")
+		sb.WriteString(step.Matrix.String())
+	}
+
+	return sb.String()
+}
+
+// LaTeX renders the reduction as a chain of matrices joined by arrows
+// labelled with the row operations, one step per line of an align* environment
+func (t RREFTrace) LaTeX() string {
+	var sb strings.Builder
+	sb.WriteString(`\begin{align*}` + "\n")
+	sb.WriteString("&" + latexMatrix(t.Input))
+	for _, step := range t.Steps {
+		sb.WriteString(` \\` + "\n")
+		sb.WriteString(`\xrightarrow{` + step.Operation.LaTeX() + `} &` + latexMatrix(step.Matrix))
+	}
+	sb.WriteString("\n" + `\end{align*}` + "\n")
+
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestExactRREF(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  [][]string
+		steps  []string
+		want   [][]string
+		pivots []int
+	}{
+		{
+			name:   "swap scale and add",
+			input:  [][]string{{"0", "2", "4"}, {"1", "1", "1"}},
+			steps:  []string{"R1 <-> R2", "R2 -> 1/2 R2", "R1 -> R1 - R2"},
+			want:   [][]string{{"1", "0", "-1"}, {"0", "1", "2"}},
+			pivots: []int{0, 1},
+		},
+		{
+			name:   "fractions",
+			input:  [][]string{{"2", "1", "1"}, {"1", "3", "2"}},
+			steps:  []string{"R1 -> 1/2 R1", "R2 -> R2 - R1", "R2 -> 2/5 R2", "R1 -> R1 - 1/2 R2"},
+			want:   [][]string{{"1", "0", "1/5"}, {"0", "1", "3/5"}},
+			pivots: []int{0, 1},
+		},
+		{
+			name:   "rank deficient",
+			input:  [][]string{{"1", "2"}, {"-2", "-4"}},
+			steps:  []string{"R2 -> R2 + 2 R1"},
+			want:   [][]string{{"1", "2"}, {"0", "0"}},
+			pivots: []int{0},
+		},
+		{
+			name:   "already reduced",
+			input:  [][]string{{"1", "0"}, {"0", "1"}},
+			want:   [][]string{{"1", "0"}, {"0", "1"}},
+			pivots: []int{0, 1},
+		},
+		{
+			name:   "zero matrix",
+			input:  [][]string{{"0", "0"}, {"0", "0"}},
+			want:   [][]string{{"0", "0"}, {"0", "0"}},
+			pivots: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustParseRat(t, tt.input)
+			trace, err := ExactRREF(m)
+			if err != nil {
+				t.Fatalf("ExactRREF() unexpected error: %v", err)
+			}
+
+			steps := make([]string, len(trace.Steps))
+			for i, step := range trace.Steps {
+				steps[i] = step.Operation.String()
+			}
+			if strings.Join(steps, "; ") != strings.Join(tt.steps, "; ") {
+				t.Errorf("ExactRREF() steps = %q, want %q", steps, tt.steps)
+			}
+			if want := mustParseRat(t, tt.want); !trace.Result.Equal(want) {
+				t.Errorf("ExactRREF() = %v, want %v", trace.Result, want)
+			}
+			if len(trace.PivotColumns) != len(tt.pivots) || trace.Rank() != len(tt.pivots) {
+				t.Errorf("PivotColumns = %v, want %v", trace.PivotColumns, tt.pivots)
+			}
+			for i := range tt.pivots {
+				if trace.PivotColumns[i] != tt.pivots[i] {
+					t.Errorf("PivotColumns = %v, want %v", trace.PivotColumns, tt.pivots)
+				}
+			}
+
+			// replaying the steps from the input gives every intermediate matrix
+			replay := NewGenericMatrix(m.Data)
+			for i, step := range trace.Steps {
+				step.Operation.Apply(replay)
+				if !replay.Equal(step.Matrix) {
+					t.Errorf("step %d: Apply() = %v, want %v", i, replay, step.Matrix)
+				}
+			}
+
+			// E * A = RREF(A), and E is the product of the elementary matrices
+			E := trace.EliminationMatrix()
+			EA, err := E.Mul(m)
+			if err != nil {
+				t.Fatalf("Mul() unexpected error: %v", err)
+			}
+			if !EA.Equal(trace.Result) {
+				t.Errorf("E * A = %v, want %v", EA, trace.Result)
+			}
+			product := NewGenericIdentityMatrix[*big.Rat](len(m.Data))
+			for _, step := range trace.Steps {
+				product, _ = step.Operation.ElementaryMatrix(len(m.Data)).Mul(product)
+			}
+			if !product.Equal(E) {
+				t.Errorf("product of elementary matrices = %v, want %v", product, E)
+			}
+
+			if want, _ := m.RREF(); !trace.Result.Equal(want) {
+				t.Errorf("ExactRREF() = %v, want GenericMatrix.RREF() = %v", trace.Result, want)
+			}
+		})
+	}
+
+	if _, err := ExactRREF(NewGenericMatrix([][]*big.Rat{{big.NewRat(1, 1)}, {}})); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ExactRREF() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestRowOperationFormat(t *testing.T) {
+	tests := []struct {
+		op    RowOperation
+		text  string
+		latex string
+	}{
+		{
+			op:    RowOperation{Kind: RowSwap, Row: 0, Source: 2},
+			text:  "R1 <-> R3",
+			latex: `R_{1} \leftrightarrow R_{3}`,
+		},
+		{
+			op:    RowOperation{Kind: RowScale, Row: 1, Factor: big.NewRat(-1, 3)},
+			text:  "R2 -> -1/3 R2",
+			latex: `R_{2} \to -\frac{1}{3} R_{2}`,
+		},
+		{
+			op:    RowOperation{Kind: RowAddMultiple, Row: 2, Source: 0, Factor: big.NewRat(1, 2)},
+			text:  "R3 -> R3 + 1/2 R1",
+			latex: `R_{3} \to R_{3} + \frac{1}{2} R_{1}`,
+		},
+		{
+			op:    RowOperation{Kind: RowAddMultiple, Row: 0, Source: 1, Factor: big.NewRat(-3, 1)},
+			text:  "R1 -> R1 - 3 R2",
+			latex: `R_{1} \to R_{1} - 3 R_{2}`,
+		},
+		{
+			op:    RowOperation{Kind: RowAddMultiple, Row: 0, Source: 1, Factor: big.NewRat(-1, 1)},
+			text:  "R1 -> R1 - R2",
+			latex: `R_{1} \to R_{1} - R_{2}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := tt.op.String(); got != tt.text {
+				t.Errorf("String() = %q, want %q", got, tt.text)
+			}
+			if got := tt.op.LaTeX(); got != tt.latex {
+				t.Errorf("LaTeX() = %q, want %q", got, tt.latex)
+			}
+		})
+	}
+}
+
+func TestRREFTraceRender(t *testing.T) {
+	trace, err := ExactRREF(mustParseRat(t, [][]string{{"0", "2"}, {"3", "0"}}))
+	if err != nil {
+		t.Fatalf("ExactRREF() unexpected error: %v", err)
+	}
+
+	wantText := `[0 2]
+[3 0]
+
+R1 <-> R2
+[3 0]
+[0 2]
+
+R1 -> 1/3 R1
+[1 0]
+[0 2]
+
+R2 -> 1/2 R2
+[1 0]
+[0 1]
+`
+	if got := trace.Text(); got != wantText {
+		t.Errorf("Text() = \n%s\nwant \n%s", got, wantText)
+	}
+
+	wantLaTeX := `\begin{align*}
+&\begin{bmatrix} 0 & 2 \\ 3 & 0 \end{bmatrix} \\
+\xrightarrow{R_{1} \leftrightarrow R_{2}} &\begin{bmatrix} 3 & 0 \\ 0 & 2 \end{bmatrix} \\
+\xrightarrow{R_{1} \to \frac{1}{3} R_{1}} &\begin{bmatrix} 1 & 0 \\ 0 & 2 \end{bmatrix} \\
+\xrightarrow{R_{2} \to \frac{1}{2} R_{2}} &\begin{bmatrix} 1 & 0 \\ 0 & 1 \end{bmatrix}
+\end{align*}
+`
+	if got := trace.LaTeX(); got != wantLaTeX {
+		t.Errorf("LaTeX() = \n%s\nwant \n%s", got, wantLaTeX)
+	}
+}
@@ -0,0 +1,163 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// SignConvention decides the sign of the vectors returned by GetEigenvectors,
+// SVD and FitPCA. An eigenvector, a pair of singular vectors or a principal
+// component is only determined up to its sign (up to a complex phase for a
+// complex eigenvector), which one comes out depends on the start vectors and
+// rotation order of the algorithm. A convention picks one of them, so the same
+// matrix gives the same vectors from one version of the package to the next.
+type SignConvention int
+
+const (
+	// SignLargestPositive flips every vector so that its entry of largest
+	// magnitude is positive. Entries within signTieTolerance of the largest
+	// magnitude count as ties, the first of them decides. A complex vector is
+	// rotated so that entry is real and positive. This is the default.
+	SignLargestPositive SignConvention = iota
+	// SignAsComputed turns the convention off and returns the vectors the way
+	// the algorithm produced them
+	SignAsComputed
+)
+
+// signTieTolerance is the relative difference under which two magnitudes are
+// equal for SignLargestPositive, so rounding noise between entries like
+// 1/sqrt(2) and -1/sqrt(2) does not decide the sign
+const signTieTolerance = 1e-8
+
+// validate returns an error if s is not one of the conventions
+func (s SignConvention) validate(op string) error {
+	if s != SignLargestPositive && s != SignAsComputed {
+		return fmt.Errorf("%s: %w: unknown sign convention %d", op, ErrInvalidArgument, s)
+	}
+
+	return nil
+}
+
+// signPivot returns the index of the entry that decides the sign: the first
+// one whose magnitude is within signTieTolerance of the largest, or -1 if all
+// magnitudes are 0
+func signPivot(magnitudes []float64) int {
+	largest := 0.0
+	for _, m := range magnitudes {
+		largest = math.Max(largest, m)
+	}
+	if largest == 0 {
+		return -1
+	}
+	for i, m := range magnitudes {
+		if m >= (1-signTieTolerance)*largest {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// canonicalSignFlip reports whether v has to be negated to follow SignLargestPositive
+func canonicalSignFlip(v []float64) bool {
+	magnitudes := make([]float64, len(v))
+	for i := range v {
+		magnitudes[i] = math.Abs(v[i])
+	}
+	k := signPivot(magnitudes)
+
+	return k >= 0 && v[k] < 0
+}
+
+// canonicalizeComplexSign multiplies v by the unit complex number that makes
+// its largest entry real and positive
+func canonicalizeComplexSign(v []complex128) {
+	magnitudes := make([]float64, len(v))
+	for i := range v {
+		magnitudes[i] = cmplx.Abs(v[i])
+	}
+	k := signPivot(magnitudes)
+	if k < 0 {
+		return
+	}
+
+	if imag(v[k]) == 0 {
+		// a real entry only needs a sign flip, which is exact
+		if real(v[k]) < 0 {
+			for i := range v {
+				v[i] = -v[i]
+			}
+		}
+		return
+	}
+	phase := cmplx.Conj(v[k]) / complex(magnitudes[k], 0)
+	for i := range v {
+		v[i] *= phase
+	}
+	v[k] = complex(magnitudes[k], 0)
+}
+
+// canonicalizeSVDSigns applies SignLargestPositive to the columns of U and V
+// in place. For the columns that pair up through a singular value the right
+// singular vector (the principal component in PCA) decides and the left one is
+// flipped along with it, so A = U * S * V^T still holds. Columns without a
+// partner, in the full decomposition of a non square matrix, are flipped on
+// their own.
+func canonicalizeSVDSigns(U, V [][]float64) {
+	uCols, vCols := 0, 0
+	if len(U) > 0 {
+		uCols = len(U[0])
+	}
+	if len(V) > 0 {
+		vCols = len(V[0])
+	}
+
+	column := func(Q [][]float64, j int) []float64 {
+		res := make([]float64, len(Q))
+		for i := range Q {
+			res[i] = Q[i][j]
+		}
+		return res
+	}
+	negate := func(Q [][]float64, j int) {
+		for i := range Q {
+			Q[i][j] = -Q[i][j]
+		}
+	}
+
+	for j := 0; j < max(uCols, vCols); j++ {
+		var flip bool
+		if j < vCols {
+			flip = canonicalSignFlip(column(V, j))
+		} else {
+			flip = canonicalSignFlip(column(U, j))
+		}
+		if !flip {
+			continue
+		}
+		if j < uCols {
+			negate(U, j)
+		}
+		if j < vCols {
+			negate(V, j)
+		}
+	}
+}
+
+// EigenvectorOptions controls GetEigenvectorsWithOptions
+type EigenvectorOptions struct {
+	// Tolerance makes the numerical decisions, see GetEigenvectorsWithTolerance
+	Tolerance Tolerance
+	// Sign is the sign convention of the returned vectors
+	Sign SignConvention
+}
+
+// GetEigenvectorsWithOptions is GetEigenvectorsWithTolerance with a choice of
+// sign convention. SignAsComputed gives the vectors before the convention was
+// introduced: unit length with the first non zero entry having a positive real part.
+// It returns an error if the matrix is not square, the options are invalid or
+// the eigenvalues do not converge.
+func GetEigenvectorsWithOptions(matrix [][]float64, opts EigenvectorOptions) ([][]complex128, error) {
+	return eigenvectorsWithTolerance("GetEigenvectorsWithOptions", matrix, opts.Tolerance, opts.Sign)
+}
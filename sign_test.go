@@ -0,0 +1,203 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+func TestCanonicalizeComplexSign(t *testing.T) {
+	s := 1 / math.Sqrt(2)
+	tests := []struct {
+		name string
+		v    []complex128
+		want []complex128
+	}{
+		{name: "largest negative", v: []complex128{0.6, -0.8}, want: []complex128{-0.6, 0.8}},
+		{name: "largest positive", v: []complex128{-0.6, 0.8}, want: []complex128{-0.6, 0.8}},
+		{name: "tie takes the first", v: []complex128{complex(-s, 0), complex(s, 0)}, want: []complex128{complex(s, 0), complex(-s, 0)}},
+		{name: "rounding is a tie", v: []complex128{-0.7071067811865475, 0.7071067811865477}, want: []complex128{0.7071067811865475, -0.7071067811865477}},
+		{name: "complex", v: []complex128{complex(0, s), complex(s, 0)}, want: []complex128{complex(s, 0), complex(0, -s)}},
+		{name: "zero", v: []complex128{0, 0}, want: []complex128{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonicalizeComplexSign(tt.v)
+			for i := range tt.v {
+				if cmplx.Abs(tt.v[i]-tt.want[i]) > 1e-15 {
+					t.Errorf("canonicalizeComplexSign() = %v, want %v", tt.v, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// checkLargestPositive fails the test if the entry of largest magnitude of v is not positive
+func checkLargestPositive(t *testing.T, name string, v []float64) {
+	t.Helper()
+	k := 0
+	for i := range v {
+		if math.Abs(v[i]) > math.Abs(v[k])*(1+signTieTolerance) {
+			k = i
+		}
+	}
+	if v[k] < 0 {
+		t.Errorf("%s = %v, want its largest entry positive", name, v)
+	}
+}
+
+func TestGetEigenvectorsSign(t *testing.T) {
+	s := 1 / math.Sqrt(2)
+	got := GetEigenvectors([][]float64{{2, 1}, {1, 2}})
+	want := [][]complex128{{complex(s, 0), complex(s, 0)}, {complex(s, 0), complex(-s, 0)}}
+	for i := range want {
+		for j := range want[i] {
+			if cmplx.Abs(got[i][j]-want[i][j]) > 1e-12 {
+				t.Errorf("GetEigenvectors() = %v, want %v", got, want)
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	matrix := randomMatrix(rng, 5, 5)
+	canonical, err := GetEigenvectorsWithOptions(matrix, EigenvectorOptions{})
+	if err != nil {
+		t.Fatalf("GetEigenvectorsWithOptions() unexpected error: %v", err)
+	}
+	computed, err := GetEigenvectorsWithOptions(matrix, EigenvectorOptions{Sign: SignAsComputed})
+	if err != nil {
+		t.Fatalf("GetEigenvectorsWithOptions() unexpected error: %v", err)
+	}
+	for i := range canonical {
+		// the largest entry is real and positive
+		k := signPivot(complexMagnitudes(canonical[i]))
+		if v := canonical[i][k]; real(v) <= 0 || imag(v) != 0 {
+			t.Errorf("vector %d has largest entry %v, want it real and positive", i, v)
+		}
+		// and the vector is the computed one times a unit complex number
+		phase := canonical[i][k] / computed[i][k]
+		if math.Abs(cmplx.Abs(phase)-1) > 1e-12 {
+			t.Fatalf("vector %d: |phase| = %v, want 1", i, cmplx.Abs(phase))
+		}
+		for j := range canonical[i] {
+			if cmplx.Abs(canonical[i][j]-phase*computed[i][j]) > 1e-12 {
+				t.Errorf("vector %d = %v, want %v times %v", i, canonical[i], phase, computed[i])
+				break
+			}
+		}
+	}
+
+	if _, err := GetEigenvectorsWithOptions(matrix, EigenvectorOptions{Sign: SignConvention(5)}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("GetEigenvectorsWithOptions() error = %v, want %v", err, ErrInvalidArgument)
+	}
+}
+
+func complexMagnitudes(v []complex128) []float64 {
+	res := make([]float64, len(v))
+	for i := range v {
+		res[i] = cmplx.Abs(v[i])
+	}
+	return res
+}
+
+func TestSVDSign(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name string
+		rows int
+		cols int
+		mode SVDMode
+	}{
+		{name: "tall thin", rows: 6, cols: 4, mode: SVDThin},
+		{name: "wide thin", rows: 3, cols: 5, mode: SVDThin},
+		{name: "tall full", rows: 5, cols: 3, mode: SVDFull},
+		{name: "wide full", rows: 3, cols: 5, mode: SVDFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewMatrix(randomMatrix(rng, tt.rows, tt.cols))
+			svd, err := SVDWithOptions(&a, SVDOptions{Mode: tt.mode})
+			if err != nil {
+				t.Fatalf("SVDWithOptions() unexpected error: %v", err)
+			}
+			product := MultiplyMatrices(MultiplyMatrices(svd.U.Data, svd.S.Data), TransposeMatrix(svd.V.Data))
+			if !MatricesNearlyEqual(product, a.Data, Tolerance{Absolute: 1e-12}) {
+				t.Errorf("U * S * V^T != A")
+			}
+			for j := 0; j < svd.V.Cols(); j++ {
+				checkLargestPositive(t, "V column", svd.V.GetColumn(j))
+			}
+			for j := svd.V.Cols(); j < svd.U.Cols(); j++ {
+				checkLargestPositive(t, "unpaired U column", svd.U.GetColumn(j))
+			}
+
+			// -A has the same right singular vectors, only U changes sign
+			negated := NewMatrix(MultiplyMatrixByScalar(CopyMatrix(a.Data), -1))
+			other, err := SVDWithOptions(&negated, SVDOptions{Mode: tt.mode})
+			if err != nil {
+				t.Fatalf("SVDWithOptions() unexpected error: %v", err)
+			}
+			if !MatricesNearlyEqual(other.V.Data, svd.V.Data, Tolerance{Absolute: 1e-12}) {
+				t.Errorf("SVD(-A).V != SVD(A).V")
+			}
+			k := min(tt.rows, tt.cols)
+			for j := 0; j < k; j++ {
+				for i := 0; i < tt.rows; i++ {
+					if math.Abs(other.U.Data[i][j]+svd.U.Data[i][j]) > 1e-12 {
+						t.Fatalf("SVD(-A).U column %d != -SVD(A).U column %d", j, j)
+					}
+				}
+			}
+
+			computed, err := SVDWithOptions(&a, SVDOptions{Mode: tt.mode, Sign: SignAsComputed})
+			if err != nil {
+				t.Fatalf("SVDWithOptions() unexpected error: %v", err)
+			}
+			for j := 0; j < k; j++ {
+				if d := math.Abs(dot(computed.V.GetColumn(j), svd.V.GetColumn(j))); math.Abs(d-1) > 1e-12 {
+					t.Errorf("column %d |v . v_computed| = %v, want 1", j, d)
+				}
+			}
+		})
+	}
+}
+
+func TestFitPCASign(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	model, err := FitPCA(m, PCAOptions{})
+	if err != nil {
+		t.Fatalf("FitPCA() unexpected error: %v", err)
+	}
+	for i, pc := range model.Components {
+		checkLargestPositive(t, "component", pc.Vector)
+		if d := math.Abs(dot(pc.Vector, PCA(m)[i].Vector)); math.Abs(d-1) > 1e-9 {
+			t.Errorf("component %d |v . PCA()| = %v, want 1", i, d)
+		}
+	}
+
+	// the rows in another order give the same components with the same signs
+	reversed := NewZeroMatrix(m.Rows(), m.Cols())
+	for i := range reversed.Data {
+		copy(reversed.Data[i], m.Data[m.Rows()-1-i])
+	}
+	other, err := FitPCA(reversed, PCAOptions{})
+	if err != nil {
+		t.Fatalf("FitPCA() unexpected error: %v", err)
+	}
+	for i := range model.Components {
+		// the last components have tiny variances and are not well determined
+		if model.ExplainedVarianceRatio()[i] < 1e-6 {
+			break
+		}
+		if !MatricesNearlyEqual([][]float64{other.Components[i].Vector}, [][]float64{model.Components[i].Vector}, Tolerance{Absolute: 1e-8}) {
+			t.Errorf("component %d of the reversed rows = %v, want %v", i, other.Components[i].Vector, model.Components[i].Vector)
+		}
+	}
+
+	if _, err := FitPCA(m, PCAOptions{Sign: SignConvention(-1)}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("FitPCA() error = %v, want %v", err, ErrInvalidArgument)
+	}
+}
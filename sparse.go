@@ -0,0 +1,454 @@
+package linearalgebra
+
+import "sort"
+
+// COO is a sparse matrix in coordinate format, a list of (row, column, value)
+// triplets. It is the easy format to build a matrix in, convert it to CSR or CSC
+// for arithmetic. Duplicate entries are summed by the conversion.
+type COO struct {
+	RowIndex []int
+	ColIndex []int
+	Values   []float64
+
+	rows int
+	cols int
+}
+
+// NewCOO returns an empty rows x cols sparse matrix
+func NewCOO(rows, cols int) *COO {
+	if rows < 0 || cols < 0 {
+		panic("illegal operation")
+	}
+
+	return &COO{rows: rows, cols: cols}
+}
+
+// Dims returns the number of rows and columns
+func (c *COO) Dims() (int, int) {
+	return c.rows, c.cols
+}
+
+// NNZ returns the number of stored entries, duplicates included
+func (c *COO) NNZ() int {
+	return len(c.Values)
+}
+
+// Add adds value to the entry in row i and column j
+func (c *COO) Add(i, j int, value float64) error {
+	if i < 0 || i >= c.rows {
+		return &IndexError{Op: "COO.Add", Index: i, Len: c.rows}
+	}
+	if j < 0 || j >= c.cols {
+		return &IndexError{Op: "COO.Add", Index: j, Len: c.cols}
+	}
+
+	c.RowIndex = append(c.RowIndex, i)
+	c.ColIndex = append(c.ColIndex, j)
+	c.Values = append(c.Values, value)
+
+	return nil
+}
+
+// ToCSR converts to compressed sparse row format, summing duplicate entries
+func (c *COO) ToCSR() CSR {
+	ptr, idx, values := compress(c.rows, c.RowIndex, c.ColIndex, c.Values)
+	return CSR{RowPtr: ptr, ColIndex: idx, Values: values, rows: c.rows, cols: c.cols}
+}
+
+// ToCSC converts to compressed sparse column format, summing duplicate entries
+func (c *COO) ToCSC() CSC {
+	ptr, idx, values := compress(c.cols, c.ColIndex, c.RowIndex, c.Values)
+	return CSC{ColPtr: ptr, RowIndex: idx, Values: values, rows: c.rows, cols: c.cols}
+}
+
+// ToMatrix returns the matrix as a dense Matrix
+func (c *COO) ToMatrix() Matrix {
+	res := NewZeroMatrix(c.rows, c.cols)
+	for k, v := range c.Values {
+		res.Data[c.RowIndex[k]][c.ColIndex[k]] += v
+	}
+
+	return res
+}
+
+// CSR is a sparse matrix in compressed sparse row format.
+// The column indices of row i are ColIndex[RowPtr[i]:RowPtr[i+1]] in increasing
+// order and their values are in the same positions of Values.
+// Multiplying by a vector or a dense matrix walks every row once.
+type CSR struct {
+	RowPtr   []int
+	ColIndex []int
+	Values   []float64
+
+	rows int
+	cols int
+}
+
+// NewCSR returns the non zero entries of a dense matrix in CSR format
+func NewCSR(m Matrix) CSR {
+	shape := GetShape(m.Data)
+	res := CSR{RowPtr: make([]int, shape.Rows+1), rows: shape.Rows, cols: shape.Cols}
+	for i, row := range m.Data {
+		for j, v := range row {
+			if v != 0 {
+				res.ColIndex = append(res.ColIndex, j)
+				res.Values = append(res.Values, v)
+			}
+		}
+		res.RowPtr[i+1] = len(res.Values)
+	}
+
+	return res
+}
+
+// Dims returns the number of rows and columns
+func (s CSR) Dims() (int, int) {
+	return s.rows, s.cols
+}
+
+// NNZ returns the number of stored entries
+func (s CSR) NNZ() int {
+	return len(s.Values)
+}
+
+// At returns the entry in row i and column j, 0 if it is not stored
+func (s CSR) At(i, j int) float64 {
+	if i < 0 || i >= s.rows || j < 0 || j >= s.cols {
+		panic("index out of bounds")
+	}
+
+	return findCompressed(s.RowPtr, s.ColIndex, s.Values, i, j)
+}
+
+// ToMatrix returns the matrix as a dense Matrix
+func (s CSR) ToMatrix() Matrix {
+	res := NewZeroMatrix(s.rows, s.cols)
+	for i := 0; i < s.rows; i++ {
+		for k := s.RowPtr[i]; k < s.RowPtr[i+1]; k++ {
+			res.Data[i][s.ColIndex[k]] = s.Values[k]
+		}
+	}
+
+	return res
+}
+
+// ToCSC converts to compressed sparse column format
+func (s CSR) ToCSC() CSC {
+	ptr, idx, values := transposeCompressed(s.rows, s.cols, s.RowPtr, s.ColIndex, s.Values)
+	return CSC{ColPtr: ptr, RowIndex: idx, Values: values, rows: s.rows, cols: s.cols}
+}
+
+// T returns the transpose. The CSR arrays of A are the CSC arrays of A^T,
+// so nothing is copied.
+func (s CSR) T() CSC {
+	return CSC{ColPtr: s.RowPtr, RowIndex: s.ColIndex, Values: s.Values, rows: s.cols, cols: s.rows}
+}
+
+// Transpose returns the transpose in CSR format
+func (s CSR) Transpose() CSR {
+	return s.T().ToCSR()
+}
+
+// MulVec returns A * x
+func (s CSR) MulVec(x []float64) ([]float64, error) {
+	if len(x) != s.cols {
+		return nil, &ShapeError{
+			Op:     "CSR.MulVec",
+			Shapes: []Shape{{Rows: s.rows, Cols: s.cols}, {Rows: len(x), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := make([]float64, s.rows)
+	for i := range res {
+		sum := 0.0
+		for k := s.RowPtr[i]; k < s.RowPtr[i+1]; k++ {
+			sum += s.Values[k] * x[s.ColIndex[k]]
+		}
+		res[i] = sum
+	}
+
+	return res, nil
+}
+
+// MulVecTrans returns A^T * x
+func (s CSR) MulVecTrans(x []float64) ([]float64, error) {
+	return s.T().MulVec(x)
+}
+
+// MulMatrix returns A * B for a dense B
+func (s CSR) MulMatrix(B Matrix) (Matrix, error) {
+	shape := GetShape(B.Data)
+	if shape.Rows != s.cols {
+		return Matrix{}, &ShapeError{
+			Op:     "CSR.MulMatrix",
+			Shapes: []Shape{{Rows: s.rows, Cols: s.cols}, shape},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := NewZeroMatrix(s.rows, shape.Cols)
+	for i := 0; i < s.rows; i++ {
+		out := res.Data[i]
+		for k := s.RowPtr[i]; k < s.RowPtr[i+1]; k++ {
+			v := s.Values[k]
+			for j, b := range B.Data[s.ColIndex[k]] {
+				out[j] += v * b
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Mul returns the sparse product A * B.
+// Every row of the result is accumulated in a dense scratch row and only the
+// columns that were touched are written out, so the cost is proportional to the
+// number of multiplications and not to the size of the result.
+func (s CSR) Mul(B CSR) (CSR, error) {
+	if s.cols != B.rows {
+		return CSR{}, &ShapeError{
+			Op:     "CSR.Mul",
+			Shapes: []Shape{{Rows: s.rows, Cols: s.cols}, {Rows: B.rows, Cols: B.cols}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := CSR{RowPtr: make([]int, s.rows+1), rows: s.rows, cols: B.cols}
+	accumulator := make([]float64, B.cols)
+	// marker[j] is the last row that touched column j, -1 if none yet
+	marker := make([]int, B.cols)
+	for j := range marker {
+		marker[j] = -1
+	}
+	touched := []int{}
+
+	for i := 0; i < s.rows; i++ {
+		touched = touched[:0]
+		for k := s.RowPtr[i]; k < s.RowPtr[i+1]; k++ {
+			a := s.Values[k]
+			row := s.ColIndex[k]
+			for kb := B.RowPtr[row]; kb < B.RowPtr[row+1]; kb++ {
+				j := B.ColIndex[kb]
+				if marker[j] != i {
+					marker[j] = i
+					accumulator[j] = 0
+					touched = append(touched, j)
+				}
+				accumulator[j] += a * B.Values[kb]
+			}
+		}
+
+		sort.Ints(touched)
+		for _, j := range touched {
+			res.ColIndex = append(res.ColIndex, j)
+			res.Values = append(res.Values, accumulator[j])
+		}
+		res.RowPtr[i+1] = len(res.Values)
+	}
+
+	return res, nil
+}
+
+// CSC is a sparse matrix in compressed sparse column format.
+// The row indices of column j are RowIndex[ColPtr[j]:ColPtr[j+1]] in increasing
+// order and their values are in the same positions of Values.
+type CSC struct {
+	ColPtr   []int
+	RowIndex []int
+	Values   []float64
+
+	rows int
+	cols int
+}
+
+// NewCSC returns the non zero entries of a dense matrix in CSC format
+func NewCSC(m Matrix) CSC {
+	return NewCSR(m).ToCSC()
+}
+
+// Dims returns the number of rows and columns
+func (s CSC) Dims() (int, int) {
+	return s.rows, s.cols
+}
+
+// NNZ returns the number of stored entries
+func (s CSC) NNZ() int {
+	return len(s.Values)
+}
+
+// At returns the entry in row i and column j, 0 if it is not stored
+func (s CSC) At(i, j int) float64 {
+	if i < 0 || i >= s.rows || j < 0 || j >= s.cols {
+		panic("index out of bounds")
+	}
+
+	return findCompressed(s.ColPtr, s.RowIndex, s.Values, j, i)
+}
+
+// ToMatrix returns the matrix as a dense Matrix
+func (s CSC) ToMatrix() Matrix {
+	res := NewZeroMatrix(s.rows, s.cols)
+	for j := 0; j < s.cols; j++ {
+		for k := s.ColPtr[j]; k < s.ColPtr[j+1]; k++ {
+			res.Data[s.RowIndex[k]][j] = s.Values[k]
+		}
+	}
+
+	return res
+}
+
+// ToCSR converts to compressed sparse row format
+func (s CSC) ToCSR() CSR {
+	ptr, idx, values := transposeCompressed(s.cols, s.rows, s.ColPtr, s.RowIndex, s.Values)
+	return CSR{RowPtr: ptr, ColIndex: idx, Values: values, rows: s.rows, cols: s.cols}
+}
+
+// T returns the transpose, the CSC arrays of A are the CSR arrays of A^T
+func (s CSC) T() CSR {
+	return CSR{RowPtr: s.ColPtr, ColIndex: s.RowIndex, Values: s.Values, rows: s.cols, cols: s.rows}
+}
+
+// MulVec returns A * x, adding every column scaled by its entry of x
+func (s CSC) MulVec(x []float64) ([]float64, error) {
+	if len(x) != s.cols {
+		return nil, &ShapeError{
+			Op:     "CSC.MulVec",
+			Shapes: []Shape{{Rows: s.rows, Cols: s.cols}, {Rows: len(x), Cols: 1}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	res := make([]float64, s.rows)
+	for j := 0; j < s.cols; j++ {
+		for k := s.ColPtr[j]; k < s.ColPtr[j+1]; k++ {
+			res[s.RowIndex[k]] += s.Values[k] * x[j]
+		}
+	}
+
+	return res, nil
+}
+
+// MulVecTrans returns A^T * x
+func (s CSC) MulVecTrans(x []float64) ([]float64, error) {
+	return s.T().MulVec(x)
+}
+
+// MulMatrix returns A * B for a dense B
+func (s CSC) MulMatrix(B Matrix) (Matrix, error) {
+	return s.ToCSR().MulMatrix(B)
+}
+
+// Mul returns the sparse product A * B, computed as (B^T * A^T)^T
+// so the CSC arrays are used as they are
+func (s CSC) Mul(B CSC) (CSC, error) {
+	if s.cols != B.rows {
+		return CSC{}, &ShapeError{
+			Op:     "CSC.Mul",
+			Shapes: []Shape{{Rows: s.rows, Cols: s.cols}, {Rows: B.rows, Cols: B.cols}},
+			Err:    ErrDimensionMismatch,
+		}
+	}
+
+	product, err := B.T().Mul(s.T())
+	if err != nil {
+		return CSC{}, err
+	}
+
+	return product.T(), nil
+}
+
+// findCompressed returns the value at (major, minor) in a compressed format
+// with sorted minor indices, 0 if it is not stored
+func findCompressed(ptr, idx []int, values []float64, major, minor int) float64 {
+	lo, hi := ptr[major], ptr[major+1]
+	k := lo + sort.SearchInts(idx[lo:hi], minor)
+	if k < hi && idx[k] == minor {
+		return values[k]
+	}
+
+	return 0
+}
+
+// compress builds the compressed arrays of a list of triplets grouped by their
+// major index, with the minor indices sorted and duplicates summed
+func compress(majors int, majorIdx, minorIdx []int, values []float64) ([]int, []int, []float64) {
+	// counting sort by major index
+	ptr := make([]int, majors+1)
+	for _, i := range majorIdx {
+		ptr[i+1]++
+	}
+	for i := 0; i < majors; i++ {
+		ptr[i+1] += ptr[i]
+	}
+	next := make([]int, majors)
+	copy(next, ptr)
+	idx := make([]int, len(values))
+	vals := make([]float64, len(values))
+	for k, i := range majorIdx {
+		idx[next[i]] = minorIdx[k]
+		vals[next[i]] = values[k]
+		next[i]++
+	}
+
+	// sort every group and sum the duplicates
+	outPtr := make([]int, majors+1)
+	out := 0
+	for i := 0; i < majors; i++ {
+		group := compressedGroup{idx: idx[ptr[i]:ptr[i+1]], values: vals[ptr[i]:ptr[i+1]]}
+		sort.Sort(group)
+		for k := range group.idx {
+			if out > outPtr[i] && idx[out-1] == group.idx[k] {
+				vals[out-1] += group.values[k]
+				continue
+			}
+			idx[out] = group.idx[k]
+			vals[out] = group.values[k]
+			out++
+		}
+		outPtr[i+1] = out
+	}
+
+	return outPtr, idx[:out], vals[:out]
+}
+
+// compressedGroup sorts the minor indices of one row or column with their values
+type compressedGroup struct {
+	idx    []int
+	values []float64
+}
+
+func (g compressedGroup) Len() int           { return len(g.idx) }
+func (g compressedGroup) Less(i, j int) bool { return g.idx[i] < g.idx[j] }
+func (g compressedGroup) Swap(i, j int) {
+	g.idx[i], g.idx[j] = g.idx[j], g.idx[i]
+	g.values[i], g.values[j] = g.values[j], g.values[i]
+}
+
+// transposeCompressed converts compressed arrays grouped by the major index to
+// arrays grouped by the minor index, it turns CSR into CSC and the other way around.
+// Walking the majors in order leaves the new minor indices sorted.
+func transposeCompressed(majors, minors int, ptr, idx []int, values []float64) ([]int, []int, []float64) {
+	outPtr := make([]int, minors+1)
+	for _, j := range idx {
+		outPtr[j+1]++
+	}
+	for j := 0; j < minors; j++ {
+		outPtr[j+1] += outPtr[j]
+	}
+
+	next := make([]int, minors)
+	copy(next, outPtr)
+	outIdx := make([]int, len(idx))
+	outValues := make([]float64, len(values))
+	for i := 0; i < majors; i++ {
+		for k := ptr[i]; k < ptr[i+1]; k++ {
+			j := idx[k]
+			outIdx[next[j]] = i
+			outValues[next[j]] = values[k]
+			next[j]++
+		}
+	}
+
+	return outPtr, outIdx, outValues
+}
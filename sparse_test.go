@@ -0,0 +1,256 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// laplacian1D returns the n x n finite difference matrix tridiag(-1, 2, -1)
+func laplacian1D(n int) *COO {
+	coo := NewCOO(n, n)
+	for i := 0; i < n; i++ {
+		coo.Add(i, i, 2)
+		if i > 0 {
+			coo.Add(i, i-1, -1)
+		}
+		if i < n-1 {
+			coo.Add(i, i+1, -1)
+		}
+	}
+
+	return coo
+}
+
+// randomSparse returns a dense matrix where about density of the entries are not 0
+func randomSparse(rng *rand.Rand, rows, cols int, density float64) [][]float64 {
+	res := make([][]float64, rows)
+	for i := range res {
+		res[i] = make([]float64, cols)
+		for j := range res[i] {
+			if rng.Float64() < density {
+				res[i][j] = float64(rng.Intn(9) + 1)
+			}
+		}
+	}
+
+	return res
+}
+
+func TestCOO(t *testing.T) {
+	coo := NewCOO(3, 4)
+	entries := []struct {
+		i, j  int
+		value float64
+	}{
+		{2, 3, 5},
+		{0, 1, 1},
+		{2, 0, 4},
+		{0, 1, 2}, // duplicate, summed
+		{1, 2, 3},
+		{0, 0, 7},
+	}
+	for _, e := range entries {
+		if err := coo.Add(e.i, e.j, e.value); err != nil {
+			t.Fatalf("COO.Add() unexpected error: %v", err)
+		}
+	}
+
+	want := [][]float64{
+		{7, 3, 0, 0},
+		{0, 0, 3, 0},
+		{4, 0, 0, 5},
+	}
+	if got := coo.ToMatrix(); !areMatricesEqual(got.Data, want) {
+		t.Errorf("COO.ToMatrix() = %v, want %v", got.Data, want)
+	}
+
+	csr := coo.ToCSR()
+	if got := csr.ToMatrix(); !areMatricesEqual(got.Data, want) {
+		t.Errorf("CSR.ToMatrix() = %v, want %v", got.Data, want)
+	}
+	if csr.NNZ() != 5 {
+		t.Errorf("CSR.NNZ() = %d, want 5", csr.NNZ())
+	}
+	wantPtr := []int{0, 2, 3, 5}
+	wantIdx := []int{0, 1, 2, 0, 3}
+	for i := range wantPtr {
+		if csr.RowPtr[i] != wantPtr[i] {
+			t.Fatalf("CSR.RowPtr = %v, want %v", csr.RowPtr, wantPtr)
+		}
+	}
+	for i := range wantIdx {
+		if csr.ColIndex[i] != wantIdx[i] {
+			t.Fatalf("CSR.ColIndex = %v, want %v", csr.ColIndex, wantIdx)
+		}
+	}
+
+	csc := coo.ToCSC()
+	if got := csc.ToMatrix(); !areMatricesEqual(got.Data, want) {
+		t.Errorf("CSC.ToMatrix() = %v, want %v", got.Data, want)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if csr.At(i, j) != want[i][j] || csc.At(i, j) != want[i][j] {
+				t.Fatalf("At(%d, %d) = %v and %v, want %v", i, j, csr.At(i, j), csc.At(i, j), want[i][j])
+			}
+		}
+	}
+
+	var indexErr *IndexError
+	if err := coo.Add(3, 0, 1); !errors.As(err, &indexErr) {
+		t.Errorf("COO.Add() error = %v, want an *IndexError", err)
+	}
+}
+
+func TestSparseConversions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	dense := randomSparse(rng, 7, 5, 0.3)
+	m := Matrix{Data: dense}
+
+	csr := NewCSR(m)
+	csc := NewCSC(m)
+	tests := []struct {
+		name string
+		got  Matrix
+		want [][]float64
+	}{
+		{name: "CSR", got: csr.ToMatrix(), want: dense},
+		{name: "CSC", got: csc.ToMatrix(), want: dense},
+		{name: "CSR to CSC", got: csr.ToCSC().ToMatrix(), want: dense},
+		{name: "CSC to CSR", got: csc.ToCSR().ToMatrix(), want: dense},
+		{name: "CSR transpose", got: csr.Transpose().ToMatrix(), want: TransposeMatrix(dense)},
+		{name: "CSR T", got: csr.T().ToMatrix(), want: TransposeMatrix(dense)},
+		{name: "CSC T", got: csc.T().ToMatrix(), want: TransposeMatrix(dense)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !areMatricesEqual(tt.got.Data, tt.want) {
+				t.Errorf("got %v, want %v", tt.got.Data, tt.want)
+			}
+		})
+	}
+
+	if rows, cols := csr.Transpose().Dims(); rows != 5 || cols != 7 {
+		t.Errorf("CSR.Transpose().Dims() = %d, %d, want 5, 7", rows, cols)
+	}
+}
+
+func TestSparseMultiply(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	A := randomSparse(rng, 20, 15, 0.2)
+	B := randomSparse(rng, 15, 12, 0.2)
+	x := make([]float64, 15)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+	}
+	y := make([]float64, 20)
+	for i := range y {
+		y[i] = rng.NormFloat64()
+	}
+
+	csrA, cscA := NewCSR(Matrix{Data: A}), NewCSC(Matrix{Data: A})
+	want := MultiplyMatrices(A, B)
+
+	gotCSR, err := csrA.Mul(NewCSR(Matrix{Data: B}))
+	if err != nil {
+		t.Fatalf("CSR.Mul() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(gotCSR.ToMatrix().Data, want) {
+		t.Errorf("CSR.Mul() = %v, want %v", gotCSR.ToMatrix().Data, want)
+	}
+	gotCSC, err := cscA.Mul(NewCSC(Matrix{Data: B}))
+	if err != nil {
+		t.Fatalf("CSC.Mul() unexpected error: %v", err)
+	}
+	if !areMatricesEqual(gotCSC.ToMatrix().Data, want) {
+		t.Errorf("CSC.Mul() = %v, want %v", gotCSC.ToMatrix().Data, want)
+	}
+
+	for name, mul := range map[string]func(Matrix) (Matrix, error){"CSR": csrA.MulMatrix, "CSC": cscA.MulMatrix} {
+		got, err := mul(Matrix{Data: B})
+		if err != nil {
+			t.Fatalf("%s.MulMatrix() unexpected error: %v", name, err)
+		}
+		if !areMatricesEqual(got.Data, want) {
+			t.Errorf("%s.MulMatrix() = %v, want %v", name, got.Data, want)
+		}
+	}
+
+	wantAx := TransposeMatrix(MultiplyMatrices(A, RowToColumnVector(x)))[0]
+	wantATy := TransposeMatrix(MultiplyMatrices(TransposeMatrix(A), RowToColumnVector(y)))[0]
+	checks := []struct {
+		name string
+		mul  func([]float64) ([]float64, error)
+		in   []float64
+		want []float64
+	}{
+		{name: "CSR.MulVec", mul: csrA.MulVec, in: x, want: wantAx},
+		{name: "CSC.MulVec", mul: cscA.MulVec, in: x, want: wantAx},
+		{name: "CSR.MulVecTrans", mul: csrA.MulVecTrans, in: y, want: wantATy},
+		{name: "CSC.MulVecTrans", mul: cscA.MulVecTrans, in: y, want: wantATy},
+	}
+	for _, c := range checks {
+		got, err := c.mul(c.in)
+		if err != nil {
+			t.Fatalf("%s() unexpected error: %v", c.name, err)
+		}
+		for i := range c.want {
+			if math.Abs(got[i]-c.want[i]) > 1e-12 {
+				t.Errorf("%s() = %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+
+	if _, err := csrA.MulVec(y); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("CSR.MulVec() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := csrA.Mul(csrA); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("CSR.Mul() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := csrA.MulMatrix(Matrix{Data: A}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("CSR.MulMatrix() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestSparseLaplacian(t *testing.T) {
+	// a large system where the dense matrix would take 8 * n^2 bytes
+	n := 100000
+	A := laplacian1D(n).ToCSR()
+	if A.NNZ() != 3*n-2 {
+		t.Fatalf("NNZ() = %d, want %d", A.NNZ(), 3*n-2)
+	}
+
+	// A * (1, 1, ..., 1) is 1 at both ends and 0 inside
+	ones := make([]float64, n)
+	for i := range ones {
+		ones[i] = 1
+	}
+	got, err := A.MulVec(ones)
+	if err != nil {
+		t.Fatalf("CSR.MulVec() unexpected error: %v", err)
+	}
+	for i, v := range got {
+		want := 0.0
+		if i == 0 || i == n-1 {
+			want = 1
+		}
+		if v != want {
+			t.Fatalf("(A * 1)[%d] = %v, want %v", i, v, want)
+		}
+	}
+
+	// A^2 is pentadiagonal
+	A2, err := A.Mul(A)
+	if err != nil {
+		t.Fatalf("CSR.Mul() unexpected error: %v", err)
+	}
+	if A2.NNZ() != 5*n-6 {
+		t.Errorf("NNZ() of A^2 = %d, want %d", A2.NNZ(), 5*n-6)
+	}
+	if A2.At(5, 5) != 6 || A2.At(5, 6) != -4 || A2.At(5, 7) != 1 || A2.At(5, 8) != 0 {
+		t.Errorf("row 5 of A^2 = %v %v %v %v, want 6 -4 1 0", A2.At(5, 5), A2.At(5, 6), A2.At(5, 7), A2.At(5, 8))
+	}
+}
@@ -0,0 +1,306 @@
+package linearalgebra
+
+import (
+	"math"
+	"sort"
+)
+
+// jacobiSVD computes the thin singular value decomposition A = U * diag(sigma) * V^T
+// with the one-sided Jacobi method. Pairs of columns of A are rotated until they
+// are all orthogonal to each other, the rotations accumulate into V and the norms
+// of the final columns are the singular values. A^T*A is never formed, so small
+// singular values keep their relative accuracy.
+// The singular values are sorted in descending order. The columns of U that belong
+// to a 0 singular value are left as 0.
+// It returns a ConvergenceError if the columns are not orthogonal after
+// jacobiSweeps sweeps, which only happens for NaN or infinite entries.
+func jacobiSVD(a [][]float64) ([][]float64, []float64, [][]float64, error) {
+	shape := GetShape(a)
+	rows, cols := shape.Rows, shape.Cols
+	if rows < cols {
+		// decompose A^T = V * S * U^T instead
+		v, sigma, u, err := jacobiSVD(TransposeMatrix(a))
+		return u, sigma, v, err
+	}
+
+	u := CopyMatrix(a)
+	v := GenerateIdentityMatrix(cols)
+
+	for sweep := 0; ; sweep++ {
+		if sweep == jacobiSweeps {
+			return nil, nil, nil, &ConvergenceError{Op: "SVD", Iterations: sweep}
+		}
+		rotated := false
+		for p := 0; p < cols-1; p++ {
+			for q := p + 1; q < cols; q++ {
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for i := 0; i < rows; i++ {
+					alpha += u[i][p] * u[i][p]
+					beta += u[i][q] * u[i][q]
+					gamma += u[i][p] * u[i][q]
+				}
+				if gamma == 0 || math.Abs(gamma) <= machineEpsilon*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+
+				// rotation that makes columns p and q orthogonal
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				for i := 0; i < rows; i++ {
+					up, uq := u[i][p], u[i][q]
+					u[i][p] = c*up - s*uq
+					u[i][q] = s*up + c*uq
+				}
+				for i := 0; i < cols; i++ {
+					vp, vq := v[i][p], v[i][q]
+					v[i][p] = c*vp - s*vq
+					v[i][q] = s*vp + c*vq
+				}
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	sigma := make([]float64, cols)
+	column := make([]float64, rows)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			column[i] = u[i][j]
+		}
+		sigma[j] = norm2(column)
+		for i := 0; i < rows; i++ {
+			if sigma[j] != 0 {
+				u[i][j] /= sigma[j]
+			} else {
+				u[i][j] = 0
+			}
+		}
+	}
+
+	// sort the singular values and their vectors in descending order
+	order := make([]int, cols)
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return sigma[order[i]] > sigma[order[j]]
+	})
+
+	sortedSigma := make([]float64, cols)
+	sortedU := make([][]float64, rows)
+	for i := range sortedU {
+		sortedU[i] = make([]float64, cols)
+	}
+	sortedV := make([][]float64, cols)
+	for i := range sortedV {
+		sortedV[i] = make([]float64, cols)
+	}
+	for newIndex, oldIndex := range order {
+		sortedSigma[newIndex] = sigma[oldIndex]
+		for i := 0; i < rows; i++ {
+			sortedU[i][newIndex] = u[i][oldIndex]
+		}
+		for i := 0; i < cols; i++ {
+			sortedV[i][newIndex] = v[i][oldIndex]
+		}
+	}
+
+	return sortedU, sortedSigma, sortedV, nil
+}
+
+// jacobiSweeps is the number of sweeps after which the Jacobi methods of
+// jacobiSVD, EigenSym and EigenHermitian give up. They converge quadratically,
+// a few sweeps are enough for most matrices.
+const jacobiSweeps = 100
+
+// numericalRank returns the number of singular values larger than
+// max(m, n) * eps * the largest singular value
+func numericalRank(sigma []float64, rows, cols int) int {
+	if len(sigma) == 0 {
+		return 0
+	}
+
+	tol := float64(max(rows, cols)) * machineEpsilon * sigma[0]
+	rank := 0
+	for _, s := range sigma {
+		if s > tol {
+			rank++
+		}
+	}
+
+	return rank
+}
+
+// SVDMode selects the size of the U, S and V factors
+type SVDMode int
+
+const (
+	// SVDThin returns U as m x k, S as k x k and V as n x k where k = min(m, n)
+	SVDThin SVDMode = iota
+	// SVDFull returns U as m x m, S as m x n and V as n x n
+	SVDFull
+)
+
+// SVDOptions controls SVDWithOptions
+type SVDOptions struct {
+	// Mode is the size of the factors, SVDThin if it is not set
+	Mode SVDMode
+	// Sign is the sign convention of the singular vectors, it is decided by
+	// the columns of V and the matching columns of U are flipped with them
+	Sign SignConvention
+}
+
+// SVDWithMode performs Singular Value Decomposition on a matrix A = U * S * V^T
+// using the one-sided Jacobi method, see SVD
+func SVDWithMode(m *Matrix, mode SVDMode) SVDResult {
+	svd, err := SVDWithOptions(m, SVDOptions{Mode: mode})
+	if err != nil {
+		panic(err)
+	}
+
+	return svd
+}
+
+// SVDWithOptions performs Singular Value Decomposition on a matrix A = U * S * V^T
+// with the factor sizes and sign convention of opts, see SVD.
+// It returns an error if A is not rectangular, the options are invalid or the
+// Jacobi sweeps do not converge.
+func SVDWithOptions(m *Matrix, opts SVDOptions) (SVDResult, error) {
+	const op = "SVDWithOptions"
+	if err := checkRectangular(op, m.Data); err != nil {
+		return SVDResult{}, err
+	}
+	if err := opts.Sign.validate(op); err != nil {
+		return SVDResult{}, err
+	}
+	mode := opts.Mode
+	shape := GetShape(m.Data)
+	rows, cols := shape.Rows, shape.Cols
+	k := min(rows, cols)
+
+	U, sigma, V, err := jacobiSVD(m.Data)
+	if err != nil {
+		return SVDResult{}, err
+	}
+	rank := numericalRank(sigma, rows, cols)
+
+	uCols, vCols := k, k
+	if mode == SVDFull {
+		uCols, vCols = rows, cols
+	}
+
+	// the singular vectors of the 0 singular values are not determined by A,
+	// any orthonormal completion of the vectors of the non 0 ones is valid
+	U = completeOrthonormalColumns(U, rank, uCols)
+	V = completeOrthonormalColumns(V, rank, vCols)
+	if opts.Sign == SignLargestPositive {
+		canonicalizeSVDSigns(U, V)
+	}
+
+	S := make([][]float64, uCols)
+	for i := range S {
+		S[i] = make([]float64, vCols)
+		if i < k {
+			S[i][i] = sigma[i]
+		}
+	}
+
+	return SVDResult{
+		U:              Matrix{Data: U},
+		S:              Matrix{Data: S},
+		V:              Matrix{Data: V},
+		SingularValues: sigma,
+	}, nil
+}
+
+// TruncatedSVD returns the k largest singular values and their singular vectors,
+// U is m x k, S is k x k and V is n x k. U * S * V^T is the best rank k
+// approximation of A.
+// It computes the thin SVD of A and keeps its first k columns, so it costs as
+// much as SVD whatever k is. RandomizedSVD is much faster for k much smaller
+// than min(m, n).
+// It returns an IndexError if k is not in [0, min(m, n)].
+func TruncatedSVD(m *Matrix, k int) (SVDResult, error) {
+	shape := GetShape(m.Data)
+	if k < 0 || k > min(shape.Rows, shape.Cols) {
+		return SVDResult{}, &IndexError{Op: "TruncatedSVD", Index: k, Len: min(shape.Rows, shape.Cols)}
+	}
+
+	svd, err := SVDWithOptions(m, SVDOptions{})
+	if err != nil {
+		return SVDResult{}, err
+	}
+	U := make([][]float64, len(svd.U.Data))
+	for i := range U {
+		U[i] = svd.U.Data[i][:k]
+	}
+	V := make([][]float64, len(svd.V.Data))
+	for i := range V {
+		V[i] = svd.V.Data[i][:k]
+	}
+	S := make([][]float64, k)
+	for i := range S {
+		S[i] = svd.S.Data[i][:k]
+	}
+
+	return SVDResult{
+		U:              Matrix{Data: U},
+		S:              Matrix{Data: S},
+		V:              Matrix{Data: V},
+		SingularValues: svd.SingularValues[:k],
+	}, nil
+}
+
+// completeOrthonormalColumns keeps the first valid columns of Q, which must be
+// orthonormal, and fills the result up to cols columns with unit vectors that are
+// orthogonal to them. The new columns come from Gram-Schmidt on the standard basis,
+// orthogonalized twice so they stay orthogonal to machine precision.
+func completeOrthonormalColumns(Q [][]float64, valid int, cols int) [][]float64 {
+	rows := len(Q)
+	res := make([][]float64, rows)
+	for i := range res {
+		res[i] = make([]float64, cols)
+		copy(res[i], Q[i][:min(valid, cols)])
+	}
+
+	filled := min(valid, cols)
+	candidate := make([]float64, rows)
+	for e := 0; e < rows && filled < cols; e++ {
+		for i := range candidate {
+			candidate[i] = 0
+		}
+		candidate[e] = 1
+
+		for pass := 0; pass < 2; pass++ {
+			for j := 0; j < filled; j++ {
+				dot := 0.0
+				for i := 0; i < rows; i++ {
+					dot += res[i][j] * candidate[i]
+				}
+				for i := 0; i < rows; i++ {
+					candidate[i] -= dot * res[i][j]
+				}
+			}
+		}
+
+		norm := norm2(candidate)
+		if norm < 1e-8 {
+			// e is in the span of the columns we already have
+			continue
+		}
+		for i := 0; i < rows; i++ {
+			res[i][filled] = candidate[i] / norm
+		}
+		filled++
+	}
+
+	return res
+}
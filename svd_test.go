@@ -0,0 +1,234 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestJacobiSVD(t *testing.T) {
+	tests := []struct {
+		name      string
+		matrix    [][]float64
+		wantSigma []float64
+	}{
+		{
+			name:      "symmetric 2x2",
+			matrix:    [][]float64{{3, 1}, {1, 3}},
+			wantSigma: []float64{4, 2},
+		},
+		{
+			name:      "diagonal unsorted",
+			matrix:    [][]float64{{1, 0, 0}, {0, 3, 0}, {0, 0, 2}},
+			wantSigma: []float64{3, 2, 1},
+		},
+		{
+			name:      "rank deficient tall",
+			matrix:    [][]float64{{1, 2}, {2, 4}, {3, 6}},
+			wantSigma: []float64{math.Sqrt(70), 0},
+		},
+		{
+			name:      "wide",
+			matrix:    [][]float64{{3, 0, 0, 0}, {0, 0, 4, 0}},
+			wantSigma: []float64{4, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			U, sigma, V, err := jacobiSVD(tt.matrix)
+			if err != nil {
+				t.Fatalf("jacobiSVD() unexpected error: %v", err)
+			}
+			for i := range tt.wantSigma {
+				if math.Abs(sigma[i]-tt.wantSigma[i]) > 1e-10 {
+					t.Fatalf("jacobiSVD() sigma = %v, want %v", sigma, tt.wantSigma)
+				}
+			}
+
+			S := make([][]float64, len(sigma))
+			for i := range S {
+				S[i] = make([]float64, len(sigma))
+				S[i][i] = sigma[i]
+			}
+			reconstructed := MultiplyMatrices(MultiplyMatrices(U, S), TransposeMatrix(V))
+			if !areMatricesEqual(reconstructed, tt.matrix) {
+				t.Errorf("U * S * V^T = %v, want %v", reconstructed, tt.matrix)
+			}
+
+			vtv := MultiplyMatrices(TransposeMatrix(V), V)
+			if !areMatricesEqual(vtv, GenerateIdentityMatrix(len(vtv))) {
+				t.Errorf("V^T * V = %v, want identity", vtv)
+			}
+		})
+	}
+}
+
+func TestSVDWithMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		mode   SVDMode
+		wantU  Shape
+		wantS  Shape
+		wantV  Shape
+	}{
+		{
+			name:   "tall thin",
+			matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}},
+			mode:   SVDThin,
+			wantU:  Shape{Rows: 4, Cols: 2},
+			wantS:  Shape{Rows: 2, Cols: 2},
+			wantV:  Shape{Rows: 2, Cols: 2},
+		},
+		{
+			name:   "tall full",
+			matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}},
+			mode:   SVDFull,
+			wantU:  Shape{Rows: 4, Cols: 4},
+			wantS:  Shape{Rows: 4, Cols: 2},
+			wantV:  Shape{Rows: 2, Cols: 2},
+		},
+		{
+			name:   "wide thin",
+			matrix: [][]float64{{1, 2, 3, 4}, {2, 0, 1, 1}},
+			mode:   SVDThin,
+			wantU:  Shape{Rows: 2, Cols: 2},
+			wantS:  Shape{Rows: 2, Cols: 2},
+			wantV:  Shape{Rows: 4, Cols: 2},
+		},
+		{
+			name:   "wide full",
+			matrix: [][]float64{{1, 2, 3, 4}, {2, 0, 1, 1}},
+			mode:   SVDFull,
+			wantU:  Shape{Rows: 2, Cols: 2},
+			wantS:  Shape{Rows: 2, Cols: 4},
+			wantV:  Shape{Rows: 4, Cols: 4},
+		},
+		{
+			name:   "rank deficient full",
+			matrix: [][]float64{{1, 2, 3}, {2, 4, 6}, {3, 6, 9}, {1, 1, 1}},
+			mode:   SVDFull,
+			wantU:  Shape{Rows: 4, Cols: 4},
+			wantS:  Shape{Rows: 4, Cols: 3},
+			wantV:  Shape{Rows: 3, Cols: 3},
+		},
+		{
+			name:   "zero matrix",
+			matrix: [][]float64{{0, 0}, {0, 0}, {0, 0}},
+			mode:   SVDThin,
+			wantU:  Shape{Rows: 3, Cols: 2},
+			wantS:  Shape{Rows: 2, Cols: 2},
+			wantV:  Shape{Rows: 2, Cols: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svd := SVDWithMode(&Matrix{Data: tt.matrix}, tt.mode)
+			if GetShape(svd.U.Data) != tt.wantU || GetShape(svd.S.Data) != tt.wantS || GetShape(svd.V.Data) != tt.wantV {
+				t.Fatalf("SVDWithMode() shapes U %v S %v V %v, want %v %v %v",
+					GetShape(svd.U.Data), GetShape(svd.S.Data), GetShape(svd.V.Data), tt.wantU, tt.wantS, tt.wantV)
+			}
+
+			reconstructed := MultiplyMatrices(MultiplyMatrices(svd.U.Data, svd.S.Data), TransposeMatrix(svd.V.Data))
+			if !areMatricesEqual(reconstructed, tt.matrix) {
+				t.Errorf("U * S * V^T = %v, want %v", reconstructed, tt.matrix)
+			}
+
+			utu := MultiplyMatrices(TransposeMatrix(svd.U.Data), svd.U.Data)
+			if !areMatricesEqual(utu, GenerateIdentityMatrix(tt.wantU.Cols)) {
+				t.Errorf("U^T * U = %v, want identity", utu)
+			}
+			vtv := MultiplyMatrices(TransposeMatrix(svd.V.Data), svd.V.Data)
+			if !areMatricesEqual(vtv, GenerateIdentityMatrix(tt.wantV.Cols)) {
+				t.Errorf("V^T * V = %v, want identity", vtv)
+			}
+
+			for i := 1; i < len(svd.SingularValues); i++ {
+				if svd.SingularValues[i] > svd.SingularValues[i-1] {
+					t.Errorf("singular values not sorted: %v", svd.SingularValues)
+				}
+			}
+		})
+	}
+}
+
+func TestSVDSmallSingularValues(t *testing.T) {
+	// A = U * diag(1, 1e-9) * V^T with rotations U and V, going through A^T*A
+	// would square 1e-9 to 1e-18 which is lost next to 1
+	c, s := math.Cos(0.3), math.Sin(0.3)
+	U := [][]float64{{c, -s}, {s, c}}
+	c, s = math.Cos(1.1), math.Sin(1.1)
+	V := [][]float64{{c, -s}, {s, c}}
+	A := MultiplyMatrices(MultiplyMatrices(U, [][]float64{{1, 0}, {0, 1e-9}}), TransposeMatrix(V))
+
+	svd := SVD(&Matrix{Data: A})
+	if math.Abs(svd.SingularValues[0]-1) > 1e-14 {
+		t.Errorf("largest singular value = %v, want 1", svd.SingularValues[0])
+	}
+	if math.Abs(svd.SingularValues[1]-1e-9)/1e-9 > 1e-6 {
+		t.Errorf("smallest singular value = %v, want 1e-9", svd.SingularValues[1])
+	}
+}
+
+func TestTruncatedSVD(t *testing.T) {
+	A := [][]float64{
+		{4, 0, 0, 0},
+		{0, 3, 0, 0},
+		{0, 0, 2, 0},
+		{0, 0, 0, 1},
+		{0, 0, 0, 0},
+	}
+	m := &Matrix{Data: A}
+
+	svd, err := TruncatedSVD(m, 2)
+	if err != nil {
+		t.Fatalf("TruncatedSVD() unexpected error: %v", err)
+	}
+	if GetShape(svd.U.Data) != (Shape{Rows: 5, Cols: 2}) || GetShape(svd.V.Data) != (Shape{Rows: 4, Cols: 2}) {
+		t.Fatalf("TruncatedSVD() U is %v and V is %v", GetShape(svd.U.Data), GetShape(svd.V.Data))
+	}
+	if len(svd.SingularValues) != 2 || svd.SingularValues[0] != 4 || svd.SingularValues[1] != 3 {
+		t.Errorf("TruncatedSVD() SingularValues = %v, want [4 3]", svd.SingularValues)
+	}
+
+	// the best rank 2 approximation keeps the two largest entries
+	approx := MultiplyMatrices(MultiplyMatrices(svd.U.Data, svd.S.Data), TransposeMatrix(svd.V.Data))
+	want := [][]float64{{4, 0, 0, 0}, {0, 3, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}
+	if !areMatricesEqual(approx, want) {
+		t.Errorf("rank 2 approximation = %v, want %v", approx, want)
+	}
+
+	for _, k := range []int{-1, 5} {
+		_, err := TruncatedSVD(m, k)
+		var indexErr *IndexError
+		if !errors.As(err, &indexErr) || indexErr.Index != k || indexErr.Len != 4 {
+			t.Errorf("TruncatedSVD(%d) error = %v, want an IndexError with length 4", k, err)
+		}
+	}
+}
+
+func TestSVDNoConvergence(t *testing.T) {
+	m := Matrix{Data: [][]float64{{1, math.NaN()}, {2, 3}}}
+	if _, _, _, err := jacobiSVD(m.Data); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("jacobiSVD() error = %v, want %v", err, ErrNoConvergence)
+	}
+	if _, err := SVDWithOptions(&m, SVDOptions{}); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("SVDWithOptions() error = %v, want %v", err, ErrNoConvergence)
+	}
+	if _, err := TruncatedSVD(&m, 1); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("TruncatedSVD() error = %v, want %v", err, ErrNoConvergence)
+	}
+	if _, err := Cond(m); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Cond() error = %v, want %v", err, ErrNoConvergence)
+	}
+	if _, err := SVDWithOptions(&m, SVDOptions{Sign: SignConvention(9)}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("SVDWithOptions() error = %v, want %v", err, ErrInvalidArgument)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SVD() did not panic")
+		}
+	}()
+	SVD(&m)
+}
@@ -0,0 +1,245 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// Tolerance decides when a computed entry is small enough to be treated as 0.
+// An entry x computed from a matrix A counts as 0 when
+// |x| <= max(Absolute, Relative * ||A||)
+// where ||A|| is the largest absolute entry of A. The relative part scales with
+// the data, so A and c * A have the same rank, pivots and null space for every
+// c != 0, however large or small the entries of A are. The absolute part is a
+// floor that treats everything under it as 0 regardless of the scale.
+// The zero Tolerance stands for DefaultTolerance.
+type Tolerance struct {
+	// Absolute is the threshold that does not depend on the matrix
+	Absolute float64
+	// Relative is the threshold as a fraction of the largest entry of the matrix
+	Relative float64
+}
+
+// DefaultTolerance is used by the *WithTolerance functions when they get the
+// zero Tolerance. It is purely relative, about a million times the rounding
+// error of a single operation.
+var DefaultTolerance = Tolerance{Relative: 1e-10}
+
+var (
+	// rrefTolerance is the fixed tolerance of ToRowReducedEchelonForm and GetPivotEntries
+	rrefTolerance = Tolerance{Absolute: 1e-10}
+	// equalityTolerance is the fixed tolerance of areMatricesEqual and
+	// IsVectorInTheNullSpaceOfMatrix, equal up to 3 decimals
+	equalityTolerance = Tolerance{Absolute: 1e-3}
+)
+
+// resolve fills in the default for the zero Tolerance and rejects negative or NaN parts
+func (t Tolerance) resolve(op string) (Tolerance, error) {
+	if t == (Tolerance{}) {
+		return DefaultTolerance, nil
+	}
+	if !(t.Absolute >= 0) || !(t.Relative >= 0) {
+		return Tolerance{}, fmt.Errorf("%s: %w: tolerance %+v must not be negative", op, ErrInvalidArgument, t)
+	}
+	return t, nil
+}
+
+// threshold is the largest absolute value treated as 0 for data whose largest entry is scale
+func (t Tolerance) threshold(scale float64) float64 {
+	return math.Max(t.Absolute, t.Relative*scale)
+}
+
+// ToRowReducedEchelonFormWithTolerance is ToRowReducedEchelonForm with entries
+// treated as 0 according to tol instead of below the fixed 1e-10.
+// It also returns the pivot column of every nonzero row of the result.
+// It returns an error if the rows have different lengths or tol is negative.
+func ToRowReducedEchelonFormWithTolerance(matrix [][]float64, tol Tolerance) ([][]float64, []int, error) {
+	const op = "ToRowReducedEchelonFormWithTolerance"
+	if err := checkRectangular(op, matrix); err != nil {
+		return nil, nil, err
+	}
+	tol, err := tol.resolve(op)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reduced, pivots := reduceWithTolerance(matrix, tol, maxAbsEntry(matrix))
+	return reduced, pivots, nil
+}
+
+// reduceWithTolerance runs gaussJordan on a copy of matrix divided by scale.
+// Dividing by a constant does not change the reduced row echelon form, but it
+// brings the rows that still have to be reduced and the pivot rows, which are
+// divided by their pivot, to the same size, so one threshold fits both.
+func reduceWithTolerance(matrix [][]float64, tol Tolerance, scale float64) ([][]float64, []int) {
+	reduced := CopyMatrix(matrix)
+	if len(reduced) == 0 {
+		return reduced, []int{}
+	}
+
+	zero := tol.Absolute
+	if scale > 0 {
+		for i := range reduced {
+			for j := range reduced[i] {
+				reduced[i][j] /= scale
+			}
+		}
+		zero = math.Max(tol.Absolute/scale, tol.Relative)
+	}
+	_, pivots := gaussJordan(reduced, len(reduced[0]), zero)
+
+	return reduced, pivots
+}
+
+// GetMatrixRankWithTolerance is GetMatrixRank with entries treated as 0 according to tol.
+// It returns an error if the rows have different lengths or tol is negative.
+func GetMatrixRankWithTolerance(matrix [][]float64, tol Tolerance) (int, error) {
+	_, pivots, err := ToRowReducedEchelonFormWithTolerance(matrix, tol)
+	if err != nil {
+		return 0, err
+	}
+	return len(pivots), nil
+}
+
+// GetNullSpaceOfMatrixWithTolerance is GetNullSpaceOfMatrix with entries treated
+// as 0 according to tol.
+// It returns an error if the rows have different lengths or tol is negative.
+func GetNullSpaceOfMatrixWithTolerance(matrix [][]float64, tol Tolerance) ([][]float64, error) {
+	rref, pivots, err := ToRowReducedEchelonFormWithTolerance(matrix, tol)
+	if err != nil {
+		return nil, err
+	}
+	return nullSpaceFromRREF(rref, pivots), nil
+}
+
+// IsVectorInTheNullSpaceOfMatrixWithTolerance checks that every entry of A * v
+// is 0 according to tol. An entry of A * v is at most ||A|| * (|v_1| + ... + |v_n|),
+// which is the scale the relative part of tol is applied to.
+// It returns an error if the length of vector is not the number of columns of
+// matrix or tol is negative.
+func IsVectorInTheNullSpaceOfMatrixWithTolerance(vector []float64, matrix [][]float64, tol Tolerance) (bool, error) {
+	const op = "IsVectorInTheNullSpaceOfMatrixWithTolerance"
+	m := Matrix{Data: matrix}
+	if err := checkRectangular(op, matrix); err != nil {
+		return false, err
+	}
+	if m.Cols() != len(vector) {
+		return false, newShapeError(op, ErrDimensionMismatch, matrix, [][]float64{vector})
+	}
+	tol, err := tol.resolve(op)
+	if err != nil {
+		return false, err
+	}
+
+	sum := 0.0
+	for _, v := range vector {
+		sum += math.Abs(v)
+	}
+	zero := tol.threshold(maxAbsEntry(matrix) * sum)
+	product, err := m.MulVec(vector)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range product {
+		if math.Abs(v) > zero {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// MatricesNearlyEqual checks that a and b have the same shape and that every
+// entry of a - b is 0 according to tol, scaled by the largest entry of a and b.
+// It returns false if the shapes differ or tol is negative.
+func MatricesNearlyEqual(a, b [][]float64, tol Tolerance) bool {
+	tol, err := tol.resolve("MatricesNearlyEqual")
+	if err != nil {
+		return false
+	}
+	if !isRectangular(a) || !isRectangular(b) || GetShape(a) != GetShape(b) {
+		return false
+	}
+
+	zero := tol.threshold(math.Max(maxAbsEntry(a), maxAbsEntry(b)))
+	for i := range a {
+		for j := range a[i] {
+			if math.Abs(a[i][j]-b[i][j]) > zero {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// GetEigenvectorsWithTolerance is GetEigenvectors with the numerical decisions
+// made according to tol, scaled by the largest entry of the matrix: eigenvalues
+// closer than the threshold are treated as one repeated eigenvalue, and the
+// number of eigenvectors for it is the nullity of A - lambda * I at that threshold.
+// It returns an error if the matrix is not square, tol is negative or the
+// eigenvalues do not converge.
+func GetEigenvectorsWithTolerance(matrix [][]float64, tol Tolerance) ([][]complex128, error) {
+	return eigenvectorsWithTolerance("GetEigenvectorsWithTolerance", matrix, tol, SignLargestPositive)
+}
+
+// eigenvectorsWithTolerance checks the arguments of GetEigenvectorsWithTolerance
+// and GetEigenvectorsWithOptions and computes the eigenvectors
+func eigenvectorsWithTolerance(op string, matrix [][]float64, tol Tolerance, sign SignConvention) ([][]complex128, error) {
+	if err := checkSquare(op, matrix); err != nil {
+		return nil, err
+	}
+	if err := sign.validate(op); err != nil {
+		return nil, err
+	}
+	tol, err := tol.resolve(op)
+	if err != nil {
+		return nil, err
+	}
+	eigenvalues, err := TryGetEigenvalues(matrix)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := maxAbsEntry(matrix)
+	return eigenvectors(matrix, clusterEigenvalues(eigenvalues, tol.threshold(scale)), tol, scale, sign), nil
+}
+
+// clusterEigenvalues replaces every group of eigenvalues within zero of each
+// other by their mean, so a repeated eigenvalue that rounding split into
+// nearby values gets one eigenspace.
+func clusterEigenvalues(eigenvalues []complex128, zero float64) []complex128 {
+	clustered := make([]complex128, len(eigenvalues))
+	group := make([]int, len(eigenvalues))
+	for i := range group {
+		group[i] = i
+	}
+	for i := range eigenvalues {
+		for j := 0; j < i; j++ {
+			if group[j] == j && cmplx.Abs(eigenvalues[i]-eigenvalues[j]) <= zero {
+				group[i] = j
+				break
+			}
+		}
+	}
+
+	for i := range eigenvalues {
+		if group[i] != i {
+			continue
+		}
+		sum, count := complex(0, 0), 0
+		for j := i; j < len(eigenvalues); j++ {
+			if group[j] == i {
+				sum += eigenvalues[j]
+				count++
+			}
+		}
+		clustered[i] = sum / complex(float64(count), 0)
+	}
+	for i := range eigenvalues {
+		clustered[i] = clustered[group[i]]
+	}
+
+	return clustered
+}
@@ -0,0 +1,225 @@
+package linearalgebra
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestRankWithToleranceIsScaleInvariant(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		rank   int
+	}{
+		{name: "rank 2", matrix: [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, rank: 2},
+		{name: "full rank", matrix: [][]float64{{2, 1}, {1, 3}}, rank: 2},
+		{name: "rank 1", matrix: [][]float64{{1, -2, 0.5}, {-3, 6, -1.5}}, rank: 1},
+		{name: "zero", matrix: [][]float64{{0, 0}, {0, 0}}, rank: 0},
+	}
+	for _, tt := range tests {
+		var want [][]float64
+		for _, scale := range []float64{1, 1e-12, 1e12} {
+			scaled := MultiplyMatrixByScalar(CopyMatrix(tt.matrix), scale)
+			rank, err := GetMatrixRankWithTolerance(scaled, Tolerance{})
+			if err != nil {
+				t.Fatalf("%s: GetMatrixRankWithTolerance() unexpected error: %v", tt.name, err)
+			}
+			if rank != tt.rank {
+				t.Errorf("%s * %g: GetMatrixRankWithTolerance() = %d, want %d", tt.name, scale, rank, tt.rank)
+			}
+
+			rref, pivots, err := ToRowReducedEchelonFormWithTolerance(scaled, Tolerance{})
+			if err != nil {
+				t.Fatalf("%s: ToRowReducedEchelonFormWithTolerance() unexpected error: %v", tt.name, err)
+			}
+			if len(pivots) != tt.rank {
+				t.Errorf("%s * %g: pivots = %v, want %d of them", tt.name, scale, pivots, tt.rank)
+			}
+			if want == nil {
+				want = rref
+			} else if !MatricesNearlyEqual(rref, want, Tolerance{Absolute: 1e-12}) {
+				t.Errorf("%s * %g: RREF = %v, want %v", tt.name, scale, rref, want)
+			}
+		}
+	}
+
+	// the fixed 1e-10 of GetMatrixRank sees a tiny matrix as 0
+	tiny := [][]float64{{2e-12, 1e-12}, {1e-12, 3e-12}}
+	if rank := GetMatrixRank(tiny); rank != 0 {
+		t.Errorf("GetMatrixRank() = %d, want 0", rank)
+	}
+}
+
+func TestToleranceAbsoluteFloor(t *testing.T) {
+	matrix := [][]float64{{1, 0}, {0, 1e-4}}
+	tests := []struct {
+		tol  Tolerance
+		rank int
+	}{
+		{tol: Tolerance{}, rank: 2},
+		{tol: Tolerance{Absolute: 1e-3}, rank: 1},
+		{tol: Tolerance{Relative: 1e-3}, rank: 1},
+		{tol: Tolerance{Absolute: 1e-6, Relative: 1e-6}, rank: 2},
+	}
+	for _, tt := range tests {
+		rank, err := GetMatrixRankWithTolerance(matrix, tt.tol)
+		if err != nil {
+			t.Fatalf("GetMatrixRankWithTolerance(%+v) unexpected error: %v", tt.tol, err)
+		}
+		if rank != tt.rank {
+			t.Errorf("GetMatrixRankWithTolerance(%+v) = %d, want %d", tt.tol, rank, tt.rank)
+		}
+	}
+}
+
+func TestGetNullSpaceOfMatrixWithTolerance(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   [][]float64
+	}{
+		{name: "tiny", matrix: [][]float64{{1e-12, 2e-12}, {2e-12, 4e-12}}, want: [][]float64{{-2, 1}}},
+		{name: "huge", matrix: [][]float64{{1e12, 2e12, 0}, {0, 0, 1e12}}, want: [][]float64{{-2, 1, 0}}},
+		{name: "full rank", matrix: [][]float64{{1, 2}, {3, 4}}, want: [][]float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNullSpaceOfMatrixWithTolerance(tt.matrix, Tolerance{})
+			if err != nil {
+				t.Fatalf("GetNullSpaceOfMatrixWithTolerance() unexpected error: %v", err)
+			}
+			if !MatricesNearlyEqual(got, tt.want, Tolerance{Absolute: 1e-9}) {
+				t.Errorf("GetNullSpaceOfMatrixWithTolerance() = %v, want %v", got, tt.want)
+			}
+			for _, v := range got {
+				in, err := IsVectorInTheNullSpaceOfMatrixWithTolerance(v, tt.matrix, Tolerance{})
+				if err != nil || !in {
+					t.Errorf("IsVectorInTheNullSpaceOfMatrixWithTolerance(%v) = %v, %v, want true", v, in, err)
+				}
+			}
+		})
+	}
+}
+
+func TestIsVectorInTheNullSpaceOfMatrixWithTolerance(t *testing.T) {
+	tiny := [][]float64{{1e-6, 0}, {0, 1e-6}}
+	// 3 decimals cannot tell the tiny matrix from 0
+	if !IsVectorInTheNullSpaceOfMatrix([]float64{1, 1}, tiny) {
+		t.Errorf("IsVectorInTheNullSpaceOfMatrix() = false, want true")
+	}
+	if in, err := IsVectorInTheNullSpaceOfMatrixWithTolerance([]float64{1, 1}, tiny, Tolerance{}); err != nil || in {
+		t.Errorf("IsVectorInTheNullSpaceOfMatrixWithTolerance() = %v, %v, want false", in, err)
+	}
+
+	huge := [][]float64{{1e9, 1e9}}
+	// 1e9 * (1 + 1e-15) - 1e9 is rounding, not a nonzero product
+	if in, err := IsVectorInTheNullSpaceOfMatrixWithTolerance([]float64{1 + 1e-15, -1}, huge, Tolerance{}); err != nil || !in {
+		t.Errorf("IsVectorInTheNullSpaceOfMatrixWithTolerance() = %v, %v, want true", in, err)
+	}
+
+	if _, err := IsVectorInTheNullSpaceOfMatrixWithTolerance([]float64{1}, huge, Tolerance{}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("IsVectorInTheNullSpaceOfMatrixWithTolerance() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+}
+
+func TestMatricesNearlyEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a    [][]float64
+		b    [][]float64
+		tol  Tolerance
+		want bool
+	}{
+		{name: "relative to the size", a: [][]float64{{1e9, 1}}, b: [][]float64{{1e9 + 1, 2}}, tol: Tolerance{Relative: 1e-6}, want: true},
+		{name: "too far", a: [][]float64{{1, 1}}, b: [][]float64{{1, 1.001}}, tol: Tolerance{Relative: 1e-6}, want: false},
+		{name: "absolute", a: [][]float64{{1e-9}}, b: [][]float64{{2e-9}}, tol: Tolerance{Absolute: 1e-8}, want: true},
+		{name: "default", a: [][]float64{{3e-20, 1e-20}}, b: [][]float64{{3e-20, 1e-20 + 1e-32}}, want: true},
+		{name: "default too far", a: [][]float64{{3e-20, 1e-20}}, b: [][]float64{{3e-20, 2e-20}}, want: false},
+		{name: "shapes differ", a: [][]float64{{1, 2}}, b: [][]float64{{1}, {2}}, want: false},
+		{name: "jagged", a: [][]float64{{1, 2}, {3}}, b: [][]float64{{1, 2}, {3}}, want: false},
+		{name: "negative", a: [][]float64{{1}}, b: [][]float64{{1}}, tol: Tolerance{Absolute: -1}, want: false},
+		{name: "empty", a: [][]float64{}, b: [][]float64{}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatricesNearlyEqual(tt.a, tt.b, tt.tol); got != tt.want {
+				t.Errorf("MatricesNearlyEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEigenvectorsWithTolerance(t *testing.T) {
+	// Q * diag(2, 2, 5) * Q^T with the Householder reflection Q = I - 2 * u * u^T,
+	// rounding splits the double eigenvalue 2
+	u := []float64{1.0 / 3, 2.0 / 3, 2.0 / 3}
+	Q := GenerateIdentityMatrix(3)
+	for i := range Q {
+		for j := range Q[i] {
+			Q[i][j] -= 2 * u[i] * u[j]
+		}
+	}
+	base := MultiplyMatrices(MultiplyMatrices(Q, [][]float64{{2, 0, 0}, {0, 2, 0}, {0, 0, 5}}), TransposeMatrix(Q))
+
+	for _, scale := range []float64{1, 1e-12, 1e9} {
+		A := MultiplyMatrixByScalar(CopyMatrix(base), scale)
+		eigenvalues, err := TryGetEigenvalues(A)
+		if err != nil {
+			t.Fatalf("TryGetEigenvalues() unexpected error: %v", err)
+		}
+		vectors, err := GetEigenvectorsWithTolerance(A, Tolerance{})
+		if err != nil {
+			t.Fatalf("GetEigenvectorsWithTolerance() unexpected error: %v", err)
+		}
+
+		realVectors := make([][]float64, len(vectors))
+		for i, v := range vectors {
+			realVectors[i] = complexToRealVector(v)
+			lambda := real(eigenvalues[i])
+			Av := MultiplyMatrices(A, RowToColumnVector(realVectors[i]))
+			for j := range Av {
+				if d := math.Abs(Av[j][0] - lambda*realVectors[i][j]); d > 1e-9*scale {
+					t.Errorf("scale %g: |A*v - lambda*v| = %g for lambda = %g", scale, d, lambda)
+				}
+			}
+			if math.Abs(GetVectorLength(realVectors[i])-1) > 1e-9 {
+				t.Errorf("scale %g: eigenvector %v is not a unit vector", scale, realVectors[i])
+			}
+		}
+		if rank, _ := GetMatrixRankWithTolerance(realVectors, Tolerance{}); rank != 3 {
+			t.Errorf("scale %g: eigenvectors %v have rank %d, want 3", scale, realVectors, rank)
+		}
+	}
+}
+
+func TestClusterEigenvalues(t *testing.T) {
+	got := clusterEigenvalues([]complex128{1 + 2e-12, 3, 1 - 2e-12, 1, complex(3, 1)}, 1e-10)
+	want := []complex128{1, 3, 1, 1, complex(3, 1)}
+	for i := range want {
+		if cmplx.Abs(got[i]-want[i]) > 1e-15 {
+			t.Errorf("clusterEigenvalues() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestToleranceErrors(t *testing.T) {
+	negative := Tolerance{Relative: -1}
+	if _, err := GetMatrixRankWithTolerance([][]float64{{1}}, negative); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("GetMatrixRankWithTolerance() error = %v, want %v", err, ErrInvalidArgument)
+	}
+	if _, err := GetNullSpaceOfMatrixWithTolerance([][]float64{{1}}, Tolerance{Absolute: math.NaN()}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("GetNullSpaceOfMatrixWithTolerance() error = %v, want %v", err, ErrInvalidArgument)
+	}
+	if _, _, err := ToRowReducedEchelonFormWithTolerance([][]float64{{1, 2}, {3}}, Tolerance{}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ToRowReducedEchelonFormWithTolerance() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+	if _, err := GetEigenvectorsWithTolerance([][]float64{{1, 2}}, Tolerance{}); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("GetEigenvectorsWithTolerance() error = %v, want %v", err, ErrNotSquare)
+	}
+	if _, err := GetEigenvectorsWithTolerance([][]float64{{1}}, negative); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("GetEigenvectorsWithTolerance() error = %v, want %v", err, ErrInvalidArgument)
+	}
+}
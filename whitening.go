@@ -0,0 +1,154 @@
+package linearalgebra
+
+import (
+	"fmt"
+	"math"
+)
+
+// WhiteningMethod selects the whitening matrix built by NewWhitening
+type WhiteningMethod int
+
+const (
+	// WhiteningPCA rotates the centered data onto the principal components and
+	// scales every component to unit variance: W = L^-1/2 * U^T, where
+	// cov = U * L * U^T is the eigendecomposition of the covariance matrix
+	WhiteningPCA WhiteningMethod = iota
+	// WhiteningZCA rotates the PCA whitened data back to the original axes:
+	// W = U * L^-1/2 * U^T. Of all whitening matrices it changes the data the
+	// least, so every whitened column still corresponds to an original one.
+	WhiteningZCA
+)
+
+// WhiteningOptions controls NewWhitening
+type WhiteningOptions struct {
+	// Method is the whitening matrix to build, WhiteningPCA if it is not set
+	Method WhiteningMethod
+	// Epsilon is added to every eigenvalue of the covariance matrix before the
+	// inverse square root, it keeps directions with (almost) no variance from
+	// being blown up. With Epsilon 0 such directions are an error.
+	Epsilon float64
+}
+
+// Whitening is a linear transform fitted to a data set with one sample per row
+// that decorrelates the columns and gives them unit variance: the covariance
+// matrix of the transformed training data is the identity.
+type Whitening struct {
+	// Means are the column means of the training data
+	Means []float64
+	// W is the whitening matrix, a sample x becomes W * (x - Means)
+	W Matrix
+	// Inverse is W^-1, it maps whitened samples back to the original space
+	Inverse Matrix
+	// Method is the method W was built with
+	Method WhiteningMethod
+}
+
+// NewWhitening builds a whitening transform from the eigendecomposition of
+// GetCovarianceMatrix of m.
+// It returns an error wrapping ErrSingular if the covariance matrix has an
+// eigenvalue that is 0 relative to the largest one, for example because a
+// column is constant or a combination of others, unless Epsilon is set.
+// It also returns an error if m has fewer than 2 rows or the options are invalid.
+func NewWhitening(m Matrix, opts WhiteningOptions) (Whitening, error) {
+	const op = "NewWhitening"
+	if err := checkRectangular(op, m.Data); err != nil {
+		return Whitening{}, err
+	}
+	rows, cols := m.Dims()
+	if rows < 2 || cols == 0 {
+		return Whitening{}, fmt.Errorf("%s: %w: need at least 2 samples and 1 feature, got %dx%d", op, ErrInvalidArgument, rows, cols)
+	}
+	if opts.Method != WhiteningPCA && opts.Method != WhiteningZCA {
+		return Whitening{}, fmt.Errorf("%s: %w: unknown method %d", op, ErrInvalidArgument, opts.Method)
+	}
+	if !(opts.Epsilon >= 0) {
+		return Whitening{}, fmt.Errorf("%s: %w: epsilon %v must not be negative", op, ErrInvalidArgument, opts.Epsilon)
+	}
+
+	values, U, err := EigenSym(m.GetCovarianceMatrix())
+	if err != nil {
+		return Whitening{}, err
+	}
+	// the same cutoff SVD uses for the numerical rank
+	cutoff := float64(cols) * machineEpsilon * values[0]
+	scales := make([]float64, cols)
+	for i, value := range values {
+		value += opts.Epsilon
+		if value <= cutoff || value <= 0 {
+			return Whitening{}, fmt.Errorf("%s: %w: the covariance matrix has eigenvalue %g, set Epsilon to regularize",
+				op, ErrSingular, values[i])
+		}
+		scales[i] = math.Sqrt(value)
+	}
+
+	// row i of W is column i of U divided by sqrt(lambda_i), column i of W^-1 is
+	// column i of U times sqrt(lambda_i)
+	W := NewZeroMatrix(cols, cols)
+	inverse := NewZeroMatrix(cols, cols)
+	for i := 0; i < cols; i++ {
+		for j := 0; j < cols; j++ {
+			W.Data[i][j] = U.Data[j][i] / scales[i]
+			inverse.Data[j][i] = U.Data[j][i] * scales[i]
+		}
+	}
+	if opts.Method == WhiteningZCA {
+		W = NewMatrix(MultiplyMatrices(U.Data, W.Data))
+		inverse = NewMatrix(MultiplyMatrices(inverse.Data, TransposeMatrix(U.Data)))
+	}
+
+	means, _ := columnMeansAndStds(m.Data)
+	return Whitening{Means: means, W: W, Inverse: inverse, Method: opts.Method}, nil
+}
+
+// Transform whitens the rows of m, row x becomes W * (x - Means).
+// It returns an error if m does not have as many columns as the training data.
+func (w Whitening) Transform(m Matrix) (Matrix, error) {
+	if err := w.checkColumns("Whitening.Transform", m); err != nil {
+		return Matrix{}, err
+	}
+
+	centered := NewMatrix(m.Data)
+	for i := range centered.Data {
+		for j := range centered.Data[i] {
+			centered.Data[i][j] -= w.Means[j]
+		}
+	}
+	if len(centered.Data) == 0 {
+		return centered, nil
+	}
+
+	return NewMatrix(MultiplyMatrices(centered.Data, TransposeMatrix(w.W.Data))), nil
+}
+
+// InverseTransform maps whitened rows back, row z becomes W^-1 * z + Means.
+// It returns an error if m does not have as many columns as the training data.
+func (w Whitening) InverseTransform(m Matrix) (Matrix, error) {
+	if err := w.checkColumns("Whitening.InverseTransform", m); err != nil {
+		return Matrix{}, err
+	}
+	if len(m.Data) == 0 {
+		return NewMatrix(m.Data), nil
+	}
+
+	res := NewMatrix(MultiplyMatrices(m.Data, TransposeMatrix(w.Inverse.Data)))
+	for i := range res.Data {
+		for j := range res.Data[i] {
+			res.Data[i][j] += w.Means[j]
+		}
+	}
+
+	return res, nil
+}
+
+// checkColumns returns an error if m is not rectangular or its rows are not as
+// long as the training rows
+func (w Whitening) checkColumns(op string, m Matrix) error {
+	if err := checkRectangular(op, m.Data); err != nil {
+		return err
+	}
+	if rows, cols := m.Dims(); rows > 0 && cols != len(w.Means) {
+		return newShapeError(op, ErrDimensionMismatch, m.Data, w.W.Data)
+	}
+
+	return nil
+}
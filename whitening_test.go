@@ -0,0 +1,69 @@
+package linearalgebra
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWhitening(t *testing.T) {
+	m := ReadCSVToMatrixFromFile("data/pca_dataset.csv", true)
+	identity := GenerateIdentityMatrix(m.Cols())
+	for _, method := range []WhiteningMethod{WhiteningPCA, WhiteningZCA} {
+		w, err := NewWhitening(m, WhiteningOptions{Method: method})
+		if err != nil {
+			t.Fatalf("NewWhitening(%d) unexpected error: %v", method, err)
+		}
+		white, err := w.Transform(m)
+		if err != nil {
+			t.Fatalf("Transform() unexpected error: %v", err)
+		}
+		if cov := white.GetCovarianceMatrix(); !MatricesNearlyEqual(cov.Data, identity, Tolerance{Absolute: 1e-8}) {
+			t.Errorf("method %d: cov(Transform(m)) is not the identity", method)
+		}
+		if product := MultiplyMatrices(w.W.Data, w.Inverse.Data); !MatricesNearlyEqual(product, identity, Tolerance{Absolute: 1e-8}) {
+			t.Errorf("method %d: W * Inverse is not the identity", method)
+		}
+		restored, err := w.InverseTransform(white)
+		if err != nil {
+			t.Fatalf("InverseTransform() unexpected error: %v", err)
+		}
+		if !MatricesNearlyEqual(restored.Data, m.Data, Tolerance{Relative: 1e-10}) {
+			t.Errorf("method %d: InverseTransform(Transform(m)) != m", method)
+		}
+		if method == WhiteningZCA && !IsMatrixSymmetric(w.W.Data) {
+			t.Errorf("ZCA whitening matrix is not symmetric")
+		}
+	}
+}
+
+func TestWhiteningErrors(t *testing.T) {
+	// the third column is the sum of the first two
+	dependent := NewMatrix([][]float64{{1, 2, 3}, {2, 0, 2}, {0, 1, 1}, {4, 3, 7}})
+	if _, err := NewWhitening(dependent, WhiteningOptions{Method: WhiteningZCA}); !errors.Is(err, ErrSingular) {
+		t.Errorf("NewWhitening() error = %v, want %v", err, ErrSingular)
+	}
+	w, err := NewWhitening(dependent, WhiteningOptions{Method: WhiteningZCA, Epsilon: 1e-3})
+	if err != nil {
+		t.Fatalf("NewWhitening() with Epsilon unexpected error: %v", err)
+	}
+	if _, err := w.Transform(NewMatrix([][]float64{{1, 2}})); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Transform() error = %v, want %v", err, ErrDimensionMismatch)
+	}
+
+	tests := []struct {
+		name string
+		m    Matrix
+		opts WhiteningOptions
+	}{
+		{name: "one row", m: NewMatrix([][]float64{{1, 2}})},
+		{name: "method", m: dependent, opts: WhiteningOptions{Method: WhiteningMethod(7)}},
+		{name: "epsilon", m: dependent, opts: WhiteningOptions{Epsilon: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewWhitening(tt.m, tt.opts); !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("NewWhitening() error = %v, want %v", err, ErrInvalidArgument)
+			}
+		})
+	}
+}